@@ -3,94 +3,184 @@
 package bscript
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 
 	"github.com/libsv/go-bk/base58"
 	"github.com/libsv/go-bk/bec"
 	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2/network"
 )
 
+// AddressType is the type of script an address pays to.
+type AddressType string
+
+// Address types.
 const (
-	hashP2PKH        = 0x00
-	hashTestNetP2PKH = 0x6f
-	hashP2SH         = 0x05 // TODO: remove deprecated p2sh stuff
-	hashTestNetP2SH  = 0xc4
+	AddressTypeP2PKH AddressType = "pubkeyhash"
+	AddressTypeP2SH  AddressType = "scripthash" // legacy, P2SH outputs are non-standard after genesis
 )
 
 // An Address struct contains the address string as well as the hash160 hex string of the public key.
 // The address string will be human-readable and specific to the network type, but the public key hash
 // is useful because it stays the same regardless of the network type (mainnet, testnet).
+//
+// Test networks share address version bytes, so an address of any test network
+// is decoded with a Network of network.Testnet.
 type Address struct {
 	AddressString string
 	PublicKeyHash string
+	ScriptHash    string // set, instead of PublicKeyHash, for P2SH addresses
+	Network       *network.Params
+	Type          AddressType
+}
+
+// AddressVersionError is returned when decoding an address with a version byte
+// which does not belong to a known network and address type. It matches both
+// ErrUnknownAddressVersion and ErrUnsupportedAddress with errors.Is.
+type AddressVersionError struct {
+	Address string
+	Version byte
 }
 
-// NewAddressFromString takes a string address (P2PKH) and returns a pointer to an Address
-// which contains the address string as well as the public key hash string.
+// Error returns the error message.
+func (e AddressVersionError) Error() string {
+	return fmt.Sprintf("%s 0x%02x for '%s'", ErrUnknownAddressVersion, e.Version, e.Address)
+}
+
+// Is returns true if target is ErrUnknownAddressVersion or ErrUnsupportedAddress.
+func (e AddressVersionError) Is(target error) bool {
+	return target == ErrUnknownAddressVersion || target == ErrUnsupportedAddress
+}
+
+// NewAddressFromString takes a string address (P2PKH or legacy P2SH) and returns a pointer
+// to an Address which contains the address string, the hash it pays to and the network
+// and type detected from its version byte. An address whose checksum does not match
+// is rejected with ErrInvalidAddressChecksum.
 func NewAddressFromString(addr string) (*Address, error) {
-	pkh, err := addressToPubKeyHashStr(addr)
-	if err != nil {
-		return nil, err
+	decoded := base58.Decode(addr)
+	if len(decoded) != 25 {
+		return nil, fmt.Errorf("%w for '%s'", ErrInvalidAddressLength, addr)
 	}
-	return &Address{
-		AddressString: addr,
-		PublicKeyHash: pkh,
-	}, nil
+	if ckSum := checksum(decoded[:21]); !bytes.Equal(ckSum[:], decoded[21:25]) {
+		return nil, fmt.Errorf("%w for '%s'", ErrInvalidAddressChecksum, addr)
+	}
+
+	a := &Address{AddressString: addr}
+	hash := hex.EncodeToString(decoded[1:21])
+
+	var err error
+	if a.Network, err = network.ByPubKeyHashAddrID(decoded[0]); err == nil {
+		a.Type, a.PublicKeyHash = AddressTypeP2PKH, hash
+		return a, nil
+	}
+	if a.Network, err = network.ByScriptHashAddrID(decoded[0]); err == nil {
+		a.Type, a.ScriptHash = AddressTypeP2SH, hash
+		return a, nil
+	}
+
+	return nil, AddressVersionError{Address: addr, Version: decoded[0]}
 }
 
-func addressToPubKeyHashStr(address string) (string, error) {
-	decoded := base58.Decode(address)
+// NewAddressFromLockingScript returns the address, for the network provided, which
+// the P2PKH or legacy P2SH locking script pays to.
+func NewAddressFromLockingScript(s *Script, net *network.Params) (*Address, error) {
+	switch {
+	case s.IsP2PKH():
+		return NewAddressFromPublicKeyHashForNetwork((*s)[3:23], net)
+	case s.IsP2SH():
+		bb := make([]byte, 0, 21)
+		bb = append(bb, net.ScriptHashAddrID)
+		bb = append(bb, (*s)[2:22]...)
+
+		return &Address{
+			AddressString: Base58EncodeMissingChecksum(bb),
+			ScriptHash:    hex.EncodeToString((*s)[2:22]),
+			Network:       net,
+			Type:          AddressTypeP2SH,
+		}, nil
+	}
 
-	if len(decoded) != 25 {
-		return "", fmt.Errorf("%w for '%s'", ErrInvalidAddressLength, address)
+	return nil, ErrNoAddress
+}
+
+// LockingScript returns the locking script which pays to the address.
+func (a *Address) LockingScript() (*Script, error) {
+	if a.Type == AddressTypeP2SH {
+		hash, err := hex.DecodeString(a.ScriptHash)
+		if err != nil {
+			return nil, err
+		}
+		if len(hash) != 20 {
+			return nil, ErrInvalidAddressLength
+		}
+
+		s := &Script{OpHASH160, OpDATA20}
+		*s = append(*s, hash...)
+		*s = append(*s, OpEQUAL)
+		return s, nil
 	}
 
-	switch decoded[0] {
-	case hashP2PKH: // Pubkey hash (P2PKH address)
-		return hex.EncodeToString(decoded[1 : len(decoded)-4]), nil
+	hash, err := hex.DecodeString(a.PublicKeyHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) != 20 {
+		return nil, ErrInvalidPKHLen
+	}
 
-	case hashTestNetP2PKH: // Testnet pubkey hash (P2PKH address)
-		return hex.EncodeToString(decoded[1 : len(decoded)-4]), nil
+	return NewP2PKHFromPubKeyHash(hash)
+}
 
-	case hashP2SH: // Script hash (P2SH address)
-		fallthrough
-	case hashTestNetP2SH: // Testnet script hash (P2SH address)
-		fallthrough
-	default:
-		return "", fmt.Errorf("%w %s", ErrUnsupportedAddress, address)
+// IsForNetwork returns true if the address is encoded for the network provided.
+func (a *Address) IsForNetwork(net *network.Params) bool {
+	if a.Network == nil {
+		return false
+	}
+	if a.Type == AddressTypeP2SH {
+		return a.Network.ScriptHashAddrID == net.ScriptHashAddrID
 	}
+
+	return a.Network.PubKeyHashAddrID == net.PubKeyHashAddrID
 }
 
 // NewAddressFromPublicKeyString takes a public key string and returns an Address struct pointer.
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKeyString(pubKey string, mainnet bool) (*Address, error) {
+	return NewAddressFromPublicKeyStringForNetwork(pubKey, network.FromMainnet(mainnet))
+}
+
+// NewAddressFromPublicKeyStringForNetwork takes a public key string and returns an Address
+// struct pointer for the network provided.
+func NewAddressFromPublicKeyStringForNetwork(pubKey string, net *network.Params) (*Address, error) {
 	pubKeyBytes, err := hex.DecodeString(pubKey)
 	if err != nil {
 		return nil, err
 	}
-	return NewAddressFromPublicKeyHash(crypto.Hash160(pubKeyBytes), mainnet)
+	return NewAddressFromPublicKeyHashForNetwork(crypto.Hash160(pubKeyBytes), net)
 }
 
 // NewAddressFromPublicKeyHash takes a public key hash in bytes and returns an Address struct pointer.
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKeyHash(hash []byte, mainnet bool) (*Address, error) {
+	return NewAddressFromPublicKeyHashForNetwork(hash, network.FromMainnet(mainnet))
+}
 
-	// regtest := 111
-	// mainnet: 0
-
-	bb := make([]byte, 1)
-	if !mainnet {
-		bb[0] = 111
-	}
-	//nolint: makezero // we need to set up the array with 1
+// NewAddressFromPublicKeyHashForNetwork takes a public key hash in bytes and returns an
+// Address struct pointer for the network provided.
+func NewAddressFromPublicKeyHashForNetwork(hash []byte, net *network.Params) (*Address, error) {
+	bb := make([]byte, 0, len(hash)+1)
+	bb = append(bb, net.PubKeyHashAddrID)
 	bb = append(bb, hash...)
 
 	return &Address{
 		AddressString: Base58EncodeMissingChecksum(bb),
 		PublicKeyHash: hex.EncodeToString(hash),
+		Network:       net,
+		Type:          AddressTypeP2PKH,
 	}, nil
 }
 
@@ -98,22 +188,13 @@ func NewAddressFromPublicKeyHash(hash []byte, mainnet bool) (*Address, error) {
 // If mainnet parameter is true it will return a mainnet address (starting with a 1).
 // Otherwise, (mainnet is false) it will return a testnet address (starting with an m or n).
 func NewAddressFromPublicKey(pubKey *bec.PublicKey, mainnet bool) (*Address, error) {
-	hash := crypto.Hash160(pubKey.SerialiseCompressed())
-
-	// regtest := 111
-	// mainnet: 0
-
-	bb := make([]byte, 1)
-	if !mainnet {
-		bb[0] = 111
-	}
-	//nolint: makezero // we need to set up the array with 1
-	bb = append(bb, hash...)
+	return NewAddressFromPublicKeyForNetwork(pubKey, network.FromMainnet(mainnet))
+}
 
-	return &Address{
-		AddressString: Base58EncodeMissingChecksum(bb),
-		PublicKeyHash: hex.EncodeToString(hash),
-	}, nil
+// NewAddressFromPublicKeyForNetwork takes a bec public key and returns an Address struct
+// pointer for the network provided.
+func NewAddressFromPublicKeyForNetwork(pubKey *bec.PublicKey, net *network.Params) (*Address, error) {
+	return NewAddressFromPublicKeyHashForNetwork(crypto.Hash160(pubKey.SerialiseCompressed()), net)
 }
 
 // Base58EncodeMissingChecksum appends a checksum to a byte sequence
@@ -7,6 +7,7 @@ import (
 
 	"github.com/libsv/go-bk/bec"
 	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,11 +47,17 @@ func TestNewAddressFromString(t *testing.T) {
 	})
 
 	t.Run("unsupported address", func(t *testing.T) {
-		unsupportedAddress := "27BvY7rFguYQvEL872Y7Fo77Y3EBApC2EK"
+		unsupportedAddress := "27BvY7rFguYQvEL872Y7Fo77Y3EB7VHzqp"
 		addr, err := bscript.NewAddressFromString(unsupportedAddress)
 		assert.Error(t, err)
 		assert.Nil(t, addr)
-		assert.EqualError(t, err, "address not supported "+unsupportedAddress)
+		assert.EqualError(t, err, "unknown address version 0x02 for '"+unsupportedAddress+"'")
+		assert.ErrorIs(t, err, bscript.ErrUnknownAddressVersion)
+		assert.ErrorIs(t, err, bscript.ErrUnsupportedAddress)
+
+		var verErr bscript.AddressVersionError
+		assert.ErrorAs(t, err, &verErr)
+		assert.Equal(t, byte(0x02), verErr.Version)
 	})
 
 }
@@ -113,4 +120,168 @@ func TestBase58EncodeMissingChecksum(t *testing.T) {
 		"xpub661MyMwAqRbcF5ivRisXcZTEoy7d9DfLF6fLqpu5GWMfeUyGHuWJHVp5uexDqXTWoySh8pNx3ELW7qymwPNg3UEYHjwh1tpdm3P9J2j4g32",
 		bscript.Base58EncodeMissingChecksum(input),
 	)
-}
\ No newline at end of file
+}
+
+func TestNewAddressFromPublicKeyHashForNetwork(t *testing.T) {
+	t.Parallel()
+
+	hash, err := hex.DecodeString(testPublicKeyHash)
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		net     *network.Params
+		expAddr string
+	}{
+		"mainnet": {
+			net:     network.Mainnet,
+			expAddr: "114ZWApV4EEU8frr7zygqQcB1V2BodGZuS",
+		},
+		"testnet": {
+			net:     network.Testnet,
+			expAddr: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+		},
+		"regtest": {
+			net:     network.Regtest,
+			expAddr: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+		},
+		"stn": {
+			net:     network.STN,
+			expAddr: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, err := bscript.NewAddressFromPublicKeyHashForNetwork(hash, test.net)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expAddr, addr.AddressString)
+			assert.Equal(t, testPublicKeyHash, addr.PublicKeyHash)
+
+			ok, err := bscript.ValidateAddressForNetwork(addr.AddressString, test.net)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			addr2, err := bscript.NewAddressFromString(addr.AddressString)
+			assert.NoError(t, err)
+			assert.Equal(t, addr.PublicKeyHash, addr2.PublicKeyHash)
+			assert.True(t, addr2.IsForNetwork(test.net))
+		})
+	}
+}
+
+func TestNewAddressFromString_NetworkAndType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		address    string
+		expNetwork *network.Params
+		expType    bscript.AddressType
+		expHash    string
+		expScript  string
+	}{
+		"mainnet p2pkh": {
+			address:    "1E7ucTTWRTahCyViPhxSMor2pj4VGQdFMr",
+			expNetwork: network.Mainnet,
+			expType:    bscript.AddressTypeP2PKH,
+			expHash:    "8fe80c75c9560e8b56ed64ea3c26e18d2c52211b",
+			expScript:  "76a9148fe80c75c9560e8b56ed64ea3c26e18d2c52211b88ac",
+		},
+		"testnet p2pkh": {
+			address:    "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPd",
+			expNetwork: network.Testnet,
+			expType:    bscript.AddressTypeP2PKH,
+			expHash:    "8fe80c75c9560e8b56ed64ea3c26e18d2c52211b",
+			expScript:  "76a9148fe80c75c9560e8b56ed64ea3c26e18d2c52211b88ac",
+		},
+		"mainnet p2sh": {
+			address:    "3CMNFxN1oHBc4R1EpboAL5yzHGgE611Xou",
+			expNetwork: network.Mainnet,
+			expType:    bscript.AddressTypeP2SH,
+			expHash:    "74f209f6ea907e2ea48f74fae05782ae8a665257",
+			expScript:  "a91474f209f6ea907e2ea48f74fae05782ae8a66525787",
+		},
+		"testnet p2sh": {
+			address:    "2N3uaKhJ3QjgxGCdnVjR2x2yFVctPu1tHSY",
+			expNetwork: network.Testnet,
+			expType:    bscript.AddressTypeP2SH,
+			expHash:    "74f209f6ea907e2ea48f74fae05782ae8a665257",
+			expScript:  "a91474f209f6ea907e2ea48f74fae05782ae8a66525787",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, err := bscript.NewAddressFromString(test.address)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expNetwork, addr.Network)
+			assert.Equal(t, test.expType, addr.Type)
+			if test.expType == bscript.AddressTypeP2SH {
+				assert.Equal(t, test.expHash, addr.ScriptHash)
+				assert.Empty(t, addr.PublicKeyHash)
+			} else {
+				assert.Equal(t, test.expHash, addr.PublicKeyHash)
+			}
+
+			s, err := addr.LockingScript()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expScript, s.String())
+
+			addr2, err := bscript.NewAddressFromLockingScript(s, test.expNetwork)
+			assert.NoError(t, err)
+			assert.Equal(t, addr, addr2)
+		})
+	}
+}
+
+func TestNewAddressFromString_InvalidChecksum(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		address string
+	}{
+		"mainnet p2pkh": {
+			address: "1E7ucTTWRTahCyViPhxSMor2pj4VGQdFMs",
+		},
+		"testnet p2pkh": {
+			address: "mtdruWYVEV1wz5yL7GvpBj4MgifCB7yhPe",
+		},
+		"mainnet p2sh": {
+			address: "3CMNFxN1oHBc4R1EpboAL5yzHGgE611Xov",
+		},
+		"testnet p2sh": {
+			address: "2N3uaKhJ3QjgxGCdnVjR2x2yFVctPu1tHSZ",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			addr, err := bscript.NewAddressFromString(test.address)
+			assert.ErrorIs(t, err, bscript.ErrInvalidAddressChecksum)
+			assert.Nil(t, addr)
+
+			s, err := bscript.NewP2PKHFromAddress(test.address)
+			assert.Error(t, err)
+			assert.Nil(t, s)
+		})
+	}
+}
+
+func TestNewAddressFromLockingScript(t *testing.T) {
+	t.Parallel()
+
+	s, err := bscript.NewFromHexString("006a0568656c6c6f")
+	assert.NoError(t, err)
+
+	addr, err := bscript.NewAddressFromLockingScript(s, network.Mainnet)
+	assert.ErrorIs(t, err, bscript.ErrNoAddress)
+	assert.Nil(t, addr)
+}
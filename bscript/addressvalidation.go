@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2/network"
 )
 
 type a25 [25]byte
@@ -49,25 +50,39 @@ func (a *a25) set58(s []byte) error {
 }
 
 // ValidateAddress checks if an address string is a valid BitCoin address (ex. P2PKH, BIP276).
-// Checks both mainnet and testnet.
+// Checks all known networks.
 func ValidateAddress(address string) (bool, error) {
+	return ValidateAddressForNetwork(address, nil)
+}
+
+// ValidateAddressForNetwork checks if an address string is a valid BitCoin address
+// (ex. P2PKH, BIP276) for the network provided. If net is nil, all known networks are checked.
+func ValidateAddressForNetwork(address string, net *network.Params) (bool, error) {
 	if strings.HasPrefix(address, "bitcoin-script:") {
-		if _, err := DecodeBIP276(address); err != nil {
+		b, err := DecodeBIP276(address)
+		if err != nil {
 			return false, fmt.Errorf("bitcoin-script invalid [%w]", err)
 		}
+		if net != nil && b.Network != net.BIP276Network {
+			return false, ErrWrongNetwork
+		}
 		return true, nil
 	}
 
-	return validA58([]byte(address))
+	return validA58([]byte(address), net)
 }
 
-func validA58(a58 []byte) (bool, error) {
+func validA58(a58 []byte, net *network.Params) (bool, error) {
 	var a a25
 	if err := a.set58(a58); err != nil {
 		return false, err
 	}
-	if a[0] != 0 && a[0] != 0x6f {
-		return false, ErrEncodingInvalidVersion
+	if net == nil {
+		if _, err := network.ByPubKeyHashAddrID(a[0]); err != nil {
+			return false, ErrEncodingInvalidVersion
+		}
+	} else if a[0] != net.PubKeyHashAddrID {
+		return false, ErrWrongNetwork
 	}
 
 	if a.embeddedChecksum() != a.computeChecksum() {
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,3 +53,66 @@ func TestValidateAddress(t *testing.T) {
 		assert.Equal(t, false, ok)
 	})
 }
+
+func TestValidateAddressForNetwork(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		address string
+		net     *network.Params
+		expErr  error
+	}{
+		"mainnet address on mainnet": {
+			address: "114ZWApV4EEU8frr7zygqQcB1V2BodGZuS",
+			net:     network.Mainnet,
+		},
+		"mainnet address on testnet": {
+			address: "114ZWApV4EEU8frr7zygqQcB1V2BodGZuS",
+			net:     network.Testnet,
+			expErr:  bscript.ErrWrongNetwork,
+		},
+		"testnet address on stn": {
+			address: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+			net:     network.STN,
+		},
+		"testnet address on mainnet": {
+			address: "mfaWoDuTsFfiunLTqZx4fKpVsUctiDV9jk",
+			net:     network.Mainnet,
+			expErr:  bscript.ErrWrongNetwork,
+		},
+		"mainnet BIP276 on mainnet": {
+			address: "bitcoin-script:010166616b65207363726970746f0cd86a",
+			net:     network.Mainnet,
+		},
+		"testnet BIP276 on regtest": {
+			address: "bitcoin-script:020166616b65207363726970742577a444",
+			net:     network.Regtest,
+		},
+		"testnet BIP276 on mainnet": {
+			address: "bitcoin-script:020166616b65207363726970742577a444",
+			net:     network.Mainnet,
+			expErr:  bscript.ErrWrongNetwork,
+		},
+		"p2sh address": {
+			address: "3CMNFxN1oHBc4R1EpboAL5yzHGgE611Xou",
+			expErr:  bscript.ErrEncodingInvalidVersion,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ok, err := bscript.ValidateAddressForNetwork(test.address, test.net)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				assert.False(t, ok)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package bscript
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/libsv/go-bt/v2/sighash"
+)
+
+// sigHashFlags are the sighash flags by their name, as decorating a signature.
+var sigHashFlags = func() map[string]sighash.Flag {
+	ff := map[string]sighash.Flag{}
+	for _, f := range []sighash.Flag{
+		sighash.All, sighash.None, sighash.Single,
+		sighash.AllForkID, sighash.NoneForkID, sighash.SingleForkID,
+	} {
+		ff[f.String()] = f
+		ff[(f | sighash.AnyOneCanPay).String()] = f | sighash.AnyOneCanPay
+	}
+
+	return ff
+}()
+
+// decodeSigHashASM returns the signature of an ASM section of a signature decorated
+// with its sighash flag, such as <sig>[ALL|FORKID], and true, or false if the section
+// is not decorated.
+func decodeSigHashASM(section string) ([]byte, bool, error) {
+	i := strings.IndexByte(section, '[')
+	if i <= 0 || !strings.HasSuffix(section, "]") {
+		return nil, false, nil
+	}
+
+	flag, ok := sigHashFlags[section[i+1:len(section)-1]]
+	if !ok {
+		return nil, true, ErrInvalidSigHash
+	}
+	sig, err := hex.DecodeString(section[:i])
+	if err != nil {
+		return nil, true, ErrInvalidOpCode
+	}
+
+	return append(sig, byte(flag)), true, nil
+}
+
+// ToNodeASM returns the ASM of the script as the node renders it, such as in the
+// output of decoderawtransaction. Unlike ToASM, pushes of up to 4 bytes are shown as
+// numbers, as are OP_0, OP_1NEGATE and OP_1 to OP_16, and undefined opcodes are
+// shown as OP_UNKNOWN.
+//
+// If decodeSighash is true, as the node does for unlocking scripts, pushes of a
+// strict DER signature with a defined FORKID sighash flag are shown without the
+// flag byte, followed by the flag in brackets, such as <sig>[ALL|FORKID].
+//
+// The ASM can be read back with NewFromNodeASM.
+func (s *Script) ToNodeASM(decodeSighash bool) string {
+	if s == nil || len(*s) == 0 {
+		return ""
+	}
+
+	// as the node, data scripts are never decoded as holding signatures
+	decodeSighash = decodeSighash && !s.IsData()
+
+	ops, err := decodeOps(*s)
+	parts := make([]string, 0, len(ops)+1)
+	for _, op := range ops {
+		parts = append(parts, nodeOpASM(op, decodeSighash))
+	}
+	if err != nil {
+		parts = append(parts, "[error]")
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// NewFromNodeASM creates a new script from ASM as output by ToNodeASM, reading
+// numbers back as the minimal push of the number, using OP_0, OP_1NEGATE and
+// OP_1 to OP_16 where possible. Scripts pushing numbers minimally, as is standard,
+// therefore round trip through ToNodeASM and NewFromNodeASM.
+//
+// As ToNodeASM shows pushes of up to 4 bytes as numbers, a section of 10 decimal
+// digits is read as a number if it is within the range of a 4 byte number, and
+// as hex data otherwise. ASM ending in [error] or holding OP_UNKNOWN, which does
+// not show the opcode, cannot be read back and is rejected with ErrInvalidOpCode.
+func NewFromNodeASM(str string) (*Script, error) {
+	s := Script{}
+	if str == "" {
+		return &s, nil
+	}
+
+	for _, section := range strings.Split(str, " ") {
+		if n, ok := nodeASMNumber(section); ok {
+			if err := s.AppendPushInt(n); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if section == "OP_UNKNOWN" {
+			return nil, ErrInvalidOpCode
+		}
+		if err := s.appendASM(section); err != nil {
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
+
+// nodeASMNumber returns the number of an ASM section, and true, if the section is
+// a number as shown by ToNodeASM, which is within the range of a 4 byte number.
+func nodeASMNumber(section string) (int64, bool) {
+	n, err := strconv.ParseInt(section, 10, 32)
+	if err != nil || n == math.MinInt32 || strconv.FormatInt(n, 10) != section {
+		return 0, false
+	}
+
+	return n, true
+}
+
+func nodeOpASM(op scriptOp, decodeSighash bool) string {
+	switch {
+	case op.isPush() && len(op.data) <= 4:
+		return strconv.FormatInt(decodeScriptNum(op.data), 10)
+	case op.isPush():
+		if decodeSighash {
+			if flag, ok := sigHashFlag(op.data); ok {
+				return hex.EncodeToString(op.data[:len(op.data)-1]) + "[" + flag.String() + "]"
+			}
+		}
+		return hex.EncodeToString(op.data)
+	case op.op == Op1NEGATE:
+		return "-1"
+	case op.op >= OpONE && op.op <= Op16:
+		return strconv.Itoa(int(op.op-OpONE) + 1)
+	case op.op >= OpUNKNOWN186 && op.op != OpINVALIDOPCODE:
+		return "OP_UNKNOWN"
+	}
+
+	return opCodeValues[op.op]
+}
+
+// sigHashFlag returns the sighash flag of sig, and true, if sig is a strict DER
+// signature followed by a defined sighash flag using FORKID, as checked by the
+// node before decorating a signature.
+func sigHashFlag(sig []byte) (sighash.Flag, bool) {
+	if !isStrictDER(sig) {
+		return 0, false
+	}
+
+	flag := sighash.Flag(sig[len(sig)-1])
+	base := flag &^ (sighash.ForkID | sighash.AnyOneCanPay)
+	if base < sighash.All || base > sighash.Single || !flag.Has(sighash.ForkID) {
+		return 0, false
+	}
+
+	return flag, true
+}
+
+// isStrictDER returns true if sig is a DER encoded signature, followed by a sighash
+// byte, in the strict encoding of BIP66.
+func isStrictDER(sig []byte) bool {
+	// 0x30 [total-length] 0x02 [R-length] [R] 0x02 [S-length] [S] [sighash]
+	if len(sig) < 9 || len(sig) > 73 {
+		return false
+	}
+	if sig[0] != 0x30 || int(sig[1]) != len(sig)-3 {
+		return false
+	}
+
+	lenR := int(sig[3])
+	if 5+lenR >= len(sig) {
+		return false
+	}
+	lenS := int(sig[5+lenR])
+	if lenR+lenS+7 != len(sig) {
+		return false
+	}
+
+	if sig[2] != 0x02 || lenR == 0 || sig[4]&0x80 != 0 {
+		return false
+	}
+	if lenR > 1 && sig[4] == 0x00 && sig[5]&0x80 == 0 {
+		return false
+	}
+
+	if sig[lenR+4] != 0x02 || lenS == 0 || sig[lenR+6]&0x80 != 0 {
+		return false
+	}
+
+	return !(lenS > 1 && sig[lenR+6] == 0x00 && sig[lenR+7]&0x80 == 0)
+}
@@ -0,0 +1,497 @@
+package bscript
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxMacroDepth limits nested macro expansion, guarding against recursive macros.
+const maxMacroDepth = 32
+
+// maxLabelPasses limits the passes made resolving label offsets. Offsets only
+// grow between passes, so they settle well within this.
+const maxLabelPasses = 16
+
+// SourcePosition is a 1 based line and column within assembler source.
+type SourcePosition struct {
+	Line   int
+	Column int
+}
+
+// String returns the position as line:column.
+func (p SourcePosition) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// SourceMapping maps a single operation of an assembled script back to its source.
+type SourceMapping struct {
+	// OpcodeIdx the index of the operation within the script, matching the
+	// OpcodeIdx reported by the interpreter.
+	OpcodeIdx int
+	// Offset the byte offset of the operation within the script.
+	Offset int
+	// Pos the position of the token which produced the operation. For tokens
+	// within a macro defined through Assembler.Macro, this is the position
+	// within the macro source.
+	Pos SourcePosition
+	// Macro the name of the macro the operation was expanded from, if any.
+	Macro string
+	// CallSite the position in the assembled source of the outermost macro
+	// invocation which produced the operation, or Pos if not from a macro.
+	CallSite SourcePosition
+	// Labels the names of any labels defined at the operation.
+	Labels []string
+}
+
+// SourceMap maps the operations of an assembled script back to their source,
+// ordered by OpcodeIdx.
+type SourceMap []SourceMapping
+
+// Opcode returns the mapping for the operation at the given opcode index, such
+// as the OpcodeIdx of the interpreter state when an error occurs.
+func (m SourceMap) Opcode(idx int) (SourceMapping, bool) {
+	if idx < 0 || idx >= len(m) {
+		return SourceMapping{}, false
+	}
+
+	return m[idx], true
+}
+
+// Offset returns the mapping for the operation containing the given byte offset.
+func (m SourceMap) Offset(offset int) (SourceMapping, bool) {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i].Offset <= offset {
+			return m[i], true
+		}
+	}
+
+	return SourceMapping{}, false
+}
+
+// Label returns the mapping for the operation the named label was defined at.
+func (m SourceMap) Label(name string) (SourceMapping, bool) {
+	for _, mapping := range m {
+		for _, l := range mapping.Labels {
+			if l == name {
+				return mapping, true
+			}
+		}
+	}
+
+	return SourceMapping{}, false
+}
+
+// Assembler assembles scripts from a richer source language than NewFromASM.
+//
+// The source is made up of whitespace separated tokens:
+//
+//	OP_DUP              an opcode
+//	750000, -1          a number, pushed as a minimally encoded script number
+//	0xc28f832c          hex data, pushed as is
+//	"hello"             a string literal, pushed as UTF-8, supporting Go escapes
+//	PKH                 a constant or macro, defined in source or on the Assembler
+//	start:              a label, marking the byte offset of the next operation
+//	@start              a label reference, pushing the label's byte offset
+//	# comment           a comment until the end of the line, as is // comment
+//
+// Constants and macros can be defined within the source using directives:
+//
+//	.const PKH 0xc28f832c3d539933e0c719297340b34eee0f4c34
+//	.macro P2PKH
+//	    OP_DUP OP_HASH160 PKH OP_EQUALVERIFY OP_CHECKSIG
+//	.endm
+//
+// Labels may be referenced before they are defined, such as to push the offset
+// of a later OP_CODESEPARATOR. A label defined within a macro may only be
+// expanded once per script.
+//
+// An Assembler can be reused, with constants and macros defined on it being
+// available to all source it assembles.
+type Assembler struct {
+	constants map[string]*Script
+	macros    map[string][]asmToken
+}
+
+// NewAssembler returns a new Assembler with no constants or macros defined.
+func NewAssembler() *Assembler {
+	return &Assembler{
+		constants: map[string]*Script{},
+		macros:    map[string][]asmToken{},
+	}
+}
+
+// Assemble assembles the source using a new Assembler.
+func Assemble(src string) (*Script, SourceMap, error) {
+	return NewAssembler().Assemble(src)
+}
+
+// Const defines a constant pushing the data provided.
+func (a *Assembler) Const(name string, data []byte) error {
+	if err := a.checkName(name); err != nil {
+		return err
+	}
+
+	s := &Script{}
+	if err := s.AppendPushData(data); err != nil {
+		return err
+	}
+	a.constants[name] = s
+	return nil
+}
+
+// ConstInt defines a constant pushing n as a minimally encoded script number.
+func (a *Assembler) ConstInt(name string, n int64) error {
+	if err := a.checkName(name); err != nil {
+		return err
+	}
+
+	s := &Script{}
+	if err := s.AppendPushInt(n); err != nil {
+		return err
+	}
+	a.constants[name] = s
+	return nil
+}
+
+// Macro defines a macro which expands to the source provided.
+func (a *Assembler) Macro(name, src string) error {
+	if err := a.checkName(name); err != nil {
+		return err
+	}
+
+	tokens, err := tokeniseAsm(src)
+	if err != nil {
+		return fmt.Errorf("macro %s: %w", name, err)
+	}
+	a.macros[name] = tokens
+	return nil
+}
+
+// Assemble assembles the source into a script, returning a SourceMap
+// mapping each operation of the script back to the source.
+//
+// Errors are reported with the line and column of the offending token.
+func (a *Assembler) Assemble(src string) (*Script, SourceMap, error) {
+	tokens, err := tokeniseAsm(src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Source definitions are scoped to this call.
+	asm := &Assembler{
+		constants: make(map[string]*Script, len(a.constants)),
+		macros:    make(map[string][]asmToken, len(a.macros)),
+	}
+	for k, v := range a.constants {
+		asm.constants[k] = v
+	}
+	for k, v := range a.macros {
+		asm.macros[k] = v
+	}
+
+	body, err := asm.directives(tokens)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The push of a label offset grows with the offset, moving any later labels,
+	// so emit until the offsets assumed match those defined.
+	var offsets map[string]int
+	for pass := 0; pass < maxLabelPasses; pass++ {
+		e := &asmEmitter{
+			asm:     asm,
+			s:       &Script{},
+			sm:      SourceMap{},
+			offsets: offsets,
+			labels:  map[string]int{},
+		}
+		if err = e.emit(body, "", nil, 0); err != nil {
+			return nil, nil, err
+		}
+		for _, ref := range e.refs {
+			if _, ok := e.labels[ref.text[1:]]; !ok {
+				return nil, nil, asmError(ref.pos, "undefined label "+ref.text[1:])
+			}
+		}
+		if sameOffsets(offsets, e.labels) {
+			return e.s, e.sm, nil
+		}
+		offsets = e.labels
+	}
+
+	return nil, nil, fmt.Errorf("%w: label offsets did not settle", ErrAssemble)
+}
+
+func sameOffsets(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if o, ok := b[k]; !ok || o != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// directives processes .const and .macro directives, returning the remaining tokens.
+func (a *Assembler) directives(tokens []asmToken) ([]asmToken, error) {
+	body := make([]asmToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.str || !strings.HasPrefix(tok.text, ".") {
+			body = append(body, tok)
+			continue
+		}
+
+		switch tok.text {
+		case ".const":
+			if i+2 >= len(tokens) {
+				return nil, asmError(tok.pos, "expected name and value after .const")
+			}
+			name, value := tokens[i+1], tokens[i+2]
+			i += 2
+			if err := a.checkName(name.text); err != nil {
+				return nil, asmError(name.pos, err.Error())
+			}
+
+			s := &Script{}
+			if err := a.appendLiteral(s, value); err != nil {
+				return nil, err
+			}
+			a.constants[name.text] = s
+		case ".macro":
+			if i+1 >= len(tokens) {
+				return nil, asmError(tok.pos, "expected name after .macro")
+			}
+			name := tokens[i+1]
+			if err := a.checkName(name.text); err != nil {
+				return nil, asmError(name.pos, err.Error())
+			}
+
+			end := -1
+			for j := i + 2; j < len(tokens); j++ {
+				if !tokens[j].str && tokens[j].text == ".endm" {
+					end = j
+					break
+				}
+			}
+			if end == -1 {
+				return nil, asmError(tok.pos, "unterminated .macro "+name.text)
+			}
+			a.macros[name.text] = tokens[i+2 : end]
+			i = end
+		default:
+			return nil, asmError(tok.pos, "unknown directive "+tok.text)
+		}
+	}
+
+	return body, nil
+}
+
+// appendLiteral appends a number, hex, string or constant token to the script.
+func (a *Assembler) appendLiteral(s *Script, tok asmToken) error {
+	if tok.str {
+		return s.AppendPushData([]byte(tok.text))
+	}
+
+	if strings.HasPrefix(tok.text, "0x") {
+		b, err := hex.DecodeString(tok.text[2:])
+		if err != nil {
+			return asmError(tok.pos, "invalid hex "+tok.text)
+		}
+		return s.AppendPushData(b)
+	}
+
+	if c := tok.text[0]; c == '-' || (c >= '0' && c <= '9') {
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return asmError(tok.pos, "invalid number "+tok.text)
+		}
+		return s.AppendPushInt(n)
+	}
+
+	if c, ok := a.constants[tok.text]; ok {
+		*s = append(*s, *c...)
+		return nil
+	}
+
+	return asmError(tok.pos, "unknown identifier "+tok.text)
+}
+
+func (a *Assembler) checkName(name string) error {
+	if name == "" || strings.HasPrefix(name, "OP_") {
+		return fmt.Errorf("%w: invalid name '%s'", ErrAssemble, name)
+	}
+	for i, c := range name {
+		if !(c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (i > 0 && c >= '0' && c <= '9')) {
+			return fmt.Errorf("%w: invalid name '%s'", ErrAssemble, name)
+		}
+	}
+
+	return nil
+}
+
+type asmEmitter struct {
+	asm *Assembler
+	s   *Script
+	sm  SourceMap
+
+	// offsets the label offsets assumed from the previous pass.
+	offsets map[string]int
+	// labels the label offsets defined in this pass.
+	labels map[string]int
+	// pending the labels awaiting the next operation's mapping.
+	pending []string
+	refs    []asmToken
+}
+
+func (e *asmEmitter) emit(tokens []asmToken, macro string, callSite *SourcePosition, depth int) error {
+	if depth > maxMacroDepth {
+		return asmError(*callSite, "macro expansion too deep, is "+macro+" recursive?")
+	}
+
+	for _, tok := range tokens {
+		site := tok.pos
+		if callSite != nil {
+			site = *callSite
+		}
+
+		if !tok.str {
+			if strings.HasPrefix(tok.text, ".") {
+				return asmError(tok.pos, "directive "+tok.text+" not allowed here")
+			}
+			if body, ok := e.asm.macros[tok.text]; ok {
+				if err := e.emit(body, tok.text, &site, depth+1); err != nil {
+					return err
+				}
+				continue
+			}
+			if strings.HasSuffix(tok.text, ":") {
+				if err := e.label(tok); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		offset := len(*e.s)
+		switch op, ok := opCodeStrings[tok.text]; {
+		case tok.str:
+			if err := e.asm.appendLiteral(e.s, tok); err != nil {
+				return err
+			}
+		case ok:
+			if err := e.s.AppendOpcodes(op); err != nil {
+				return asmError(tok.pos, err.Error())
+			}
+		case strings.HasPrefix(tok.text, "@"):
+			// An undefined label is reported once all labels are known.
+			e.refs = append(e.refs, tok)
+			if err := e.s.AppendPushInt(int64(e.offsets[tok.text[1:]])); err != nil {
+				return asmError(tok.pos, err.Error())
+			}
+		default:
+			if err := e.asm.appendLiteral(e.s, tok); err != nil {
+				return err
+			}
+		}
+
+		// A constant may expand to more than one operation.
+		ops, err := decodeOps((*e.s)[offset:])
+		if err != nil {
+			return asmError(tok.pos, err.Error())
+		}
+		for _, op := range ops {
+			e.sm = append(e.sm, SourceMapping{
+				OpcodeIdx: len(e.sm),
+				Offset:    offset + op.offset,
+				Pos:       tok.pos,
+				Macro:     macro,
+				CallSite:  site,
+				Labels:    e.pending,
+			})
+			e.pending = nil
+		}
+	}
+
+	return nil
+}
+
+// label defines the label at the current offset of the script.
+func (e *asmEmitter) label(tok asmToken) error {
+	name := strings.TrimSuffix(tok.text, ":")
+	if err := e.asm.checkName(name); err != nil {
+		return asmError(tok.pos, err.Error())
+	}
+	if _, ok := e.labels[name]; ok {
+		return asmError(tok.pos, "duplicate label "+name)
+	}
+
+	e.labels[name] = len(*e.s)
+	e.pending = append(e.pending, name)
+	return nil
+}
+
+type asmToken struct {
+	text string
+	str  bool
+	pos  SourcePosition
+}
+
+func tokeniseAsm(src string) ([]asmToken, error) {
+	tokens := make([]asmToken, 0)
+	line, col := 1, 1
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line, col = line+1, 1
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			col++
+			i++
+		case c == '#' || strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '\n' {
+					break
+				}
+			}
+			if j >= len(src) || src[j] != '"' {
+				return nil, asmError(SourcePosition{line, col}, "unterminated string")
+			}
+			str, err := strconv.Unquote(src[i : j+1])
+			if err != nil {
+				return nil, asmError(SourcePosition{line, col}, "invalid string "+src[i:j+1])
+			}
+			tokens = append(tokens, asmToken{text: str, str: true, pos: SourcePosition{line, col}})
+			col += j + 1 - i
+			i = j + 1
+		default:
+			j := i
+			for j < len(src) && !strings.ContainsRune(" \t\r\n#\"", rune(src[j])) && !strings.HasPrefix(src[j:], "//") {
+				j++
+			}
+			tokens = append(tokens, asmToken{text: src[i:j], pos: SourcePosition{line, col}})
+			col += j - i
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+func asmError(pos SourcePosition, msg string) error {
+	return fmt.Errorf("%w at %s: %s", ErrAssemble, pos, msg)
+}
@@ -0,0 +1,231 @@
+package bscript_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/bscript/interpreter/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssemble(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		src    string
+		expHex string
+		expErr string
+	}{
+		"opcodes": {
+			src:    "OP_DUP OP_HASH160",
+			expHex: "76a9",
+		},
+		"numbers are minimally encoded": {
+			src:    "0 -1 1 16 17 -17 750000",
+			expHex: "004f51600111019103b0710b",
+		},
+		"hex data": {
+			src:    "0xc28f832c OP_DROP",
+			expHex: "04c28f832c75",
+		},
+		"string literal with escapes": {
+			src:    `"hi there\n" OP_DROP`,
+			expHex: "0968692074686572650a75",
+		},
+		"comments": {
+			src:    "OP_1 # push one\nOP_2 // push two\n// OP_3",
+			expHex: "5152",
+		},
+		"const directive": {
+			src: `.const PKH 0xc28f832c3d539933e0c719297340b34eee0f4c34
+				OP_DUP OP_HASH160 PKH OP_EQUALVERIFY OP_CHECKSIG`,
+			expHex: "76a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+		},
+		"numeric const": {
+			src:    ".const N 2\nN N OP_ADD",
+			expHex: "525293",
+		},
+		"macro directive": {
+			src: `.const PKH 0xc28f832c3d539933e0c719297340b34eee0f4c34
+				.macro P2PKH
+					OP_DUP OP_HASH160 PKH OP_EQUALVERIFY OP_CHECKSIG
+				.endm
+				.macro CLTV_P2PKH
+					750000 OP_CHECKLOCKTIMEVERIFY OP_DROP P2PKH
+				.endm
+				CLTV_P2PKH`,
+			expHex: "03b0710bb17576a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+		},
+		"unknown identifier": {
+			src:    "OP_1\n  NOPE",
+			expErr: "invalid assembler source at 2:3: unknown identifier NOPE",
+		},
+		"invalid hex": {
+			src:    "0xabc",
+			expErr: "invalid assembler source at 1:1: invalid hex 0xabc",
+		},
+		"invalid number": {
+			src:    "99999999999999999999",
+			expErr: "invalid assembler source at 1:1: invalid number 99999999999999999999",
+		},
+		"unterminated string": {
+			src:    `OP_1 "abc`,
+			expErr: "invalid assembler source at 1:6: unterminated string",
+		},
+		"unterminated macro": {
+			src:    ".macro M OP_1",
+			expErr: "invalid assembler source at 1:1: unterminated .macro M",
+		},
+		"unknown directive": {
+			src:    ".data 0x00",
+			expErr: "invalid assembler source at 1:1: unknown directive .data",
+		},
+		"recursive macro": {
+			src:    ".macro M M .endm M",
+			expErr: "invalid assembler source at 1:18: macro expansion too deep, is M recursive?",
+		},
+		"label reference": {
+			src:    "start: OP_1 OP_DROP @start",
+			expHex: "517500",
+		},
+		"forward label reference": {
+			src:    "@end OP_DROP end: OP_CODESEPARATOR",
+			expHex: "5275ab",
+		},
+		"label offsets settle as pushes grow": {
+			src:    "@end 0xc28f832c3d539933e0c719297340b34eee0f4c34 OP_DROP end: OP_1",
+			expHex: "0118" + "14c28f832c3d539933e0c719297340b34eee0f4c34" + "7551",
+		},
+		"undefined label": {
+			src:    "OP_1 @nope",
+			expErr: "invalid assembler source at 1:6: undefined label nope",
+		},
+		"duplicate label": {
+			src:    "a: OP_1 a: OP_2",
+			expErr: "invalid assembler source at 1:9: duplicate label a",
+		},
+		"labels in macros expanded twice": {
+			src:    ".macro M l: OP_1 .endm M M",
+			expErr: "invalid assembler source at 1:10: duplicate label l",
+		},
+		"opcode names cannot be redefined": {
+			src:    ".const OP_DUP 1",
+			expErr: "invalid assembler source at 1:8: invalid assembler source: invalid name 'OP_DUP'",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _, err := bscript.Assemble(test.src)
+			if test.expErr != "" {
+				assert.EqualError(t, err, test.expErr)
+				assert.True(t, errors.Is(err, bscript.ErrAssemble))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expHex, s.String())
+		})
+	}
+}
+
+func TestAssembler_ConstAndMacro(t *testing.T) {
+	t.Parallel()
+
+	a := bscript.NewAssembler()
+	assert.NoError(t, a.Const("PKH", mustDecodeHex(t, "c28f832c3d539933e0c719297340b34eee0f4c34")))
+	assert.NoError(t, a.ConstInt("LOCK", 750000))
+	assert.NoError(t, a.Macro("P2PKH", "OP_DUP OP_HASH160 PKH OP_EQUALVERIFY OP_CHECKSIG"))
+	assert.Error(t, a.ConstInt("9LIVES", 9))
+	assert.Error(t, a.Macro("BAD", `"unterminated`))
+
+	s, _, err := a.Assemble("LOCK OP_CHECKLOCKTIMEVERIFY OP_DROP P2PKH")
+	assert.NoError(t, err)
+	assert.True(t, s.IsCLTVP2PKH())
+
+	// Definitions made in source do not leak into the assembler.
+	_, _, err = a.Assemble(".const X 1 X")
+	assert.NoError(t, err)
+	_, _, err = a.Assemble("X")
+	assert.EqualError(t, err, "invalid assembler source at 1:1: unknown identifier X")
+}
+
+func TestAssemble_SourceMap(t *testing.T) {
+	t.Parallel()
+
+	a := bscript.NewAssembler()
+	assert.NoError(t, a.Macro("CHECK", "OP_EQUALVERIFY\nOP_CHECKSIG"))
+
+	s, sm, err := a.Assemble(".macro PREFIX OP_DUP OP_HASH160 .endm\nPREFIX 0x0102\n  CHECK")
+	assert.NoError(t, err)
+	assert.Equal(t, "76a9020102"+"88ac", s.String())
+	assert.Equal(t, bscript.SourceMap{
+		{OpcodeIdx: 0, Offset: 0, Pos: bscript.SourcePosition{Line: 1, Column: 15}, Macro: "PREFIX", CallSite: bscript.SourcePosition{Line: 2, Column: 1}},
+		{OpcodeIdx: 1, Offset: 1, Pos: bscript.SourcePosition{Line: 1, Column: 22}, Macro: "PREFIX", CallSite: bscript.SourcePosition{Line: 2, Column: 1}},
+		{OpcodeIdx: 2, Offset: 2, Pos: bscript.SourcePosition{Line: 2, Column: 8}, CallSite: bscript.SourcePosition{Line: 2, Column: 8}},
+		{OpcodeIdx: 3, Offset: 5, Pos: bscript.SourcePosition{Line: 1, Column: 1}, Macro: "CHECK", CallSite: bscript.SourcePosition{Line: 3, Column: 3}},
+		{OpcodeIdx: 4, Offset: 6, Pos: bscript.SourcePosition{Line: 2, Column: 1}, Macro: "CHECK", CallSite: bscript.SourcePosition{Line: 3, Column: 3}},
+	}, sm)
+
+	m, ok := sm.Offset(3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, m.OpcodeIdx)
+	_, ok = sm.Opcode(5)
+	assert.False(t, ok)
+}
+
+func TestAssemble_Labels(t *testing.T) {
+	t.Parallel()
+
+	s, sm, err := bscript.Assemble("OP_1 @sep OP_DROP\nsep: check: OP_CODESEPARATOR\nend:")
+	assert.NoError(t, err)
+	assert.Equal(t, "515375ab", s.String())
+
+	m, ok := sm.Label("check")
+	assert.True(t, ok)
+	assert.Equal(t, bscript.SourceMapping{
+		OpcodeIdx: 3,
+		Offset:    3,
+		Pos:       bscript.SourcePosition{Line: 2, Column: 13},
+		CallSite:  bscript.SourcePosition{Line: 2, Column: 13},
+		Labels:    []string{"sep", "check"},
+	}, m)
+
+	// A label after the last operation has no operation to map to.
+	_, ok = sm.Label("end")
+	assert.False(t, ok)
+}
+
+func TestAssemble_SourceMapInterpreterError(t *testing.T) {
+	t.Parallel()
+
+	locking, sm, err := bscript.Assemble(`
+		OP_1 OP_ADD
+		3 OP_EQUALVERIFY  # fails, 1 + 1 != 3
+		OP_TRUE`)
+	assert.NoError(t, err)
+
+	unlocking, _, err := bscript.Assemble("1")
+	assert.NoError(t, err)
+
+	var pos bscript.SourcePosition
+	dbg := debug.NewDebugger()
+	dbg.AttachAfterError(func(state *interpreter.State, err error) {
+		if m, ok := sm.Opcode(state.OpcodeIdx); ok {
+			pos = m.Pos
+		}
+	})
+
+	err = interpreter.NewEngine().Execute(
+		interpreter.WithScripts(locking, unlocking),
+		interpreter.WithAfterGenesis(),
+		interpreter.WithDebugger(dbg),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, bscript.SourcePosition{Line: 3, Column: 5}, pos)
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2/network"
 )
 
 // BIP276 proposes a scheme for encoding typed bitcoin related data in a user-friendly way
@@ -32,11 +33,11 @@ const PrefixTemplate = "bitcoin-template"
 const CurrentVersion = 1
 
 // NetworkMainnet specifies that the data is only
-// valid for use on the main network.
+// valid for use on the main network. It matches network.Mainnet.BIP276Network.
 const NetworkMainnet = 1
 
 // NetworkTestnet specifies that the data is only
-// valid for use on the test network.
+// valid for use on the test network. It matches network.Testnet.BIP276Network.
 const NetworkTestnet = 2
 
 var validBIP276 = regexp.MustCompile(`^(.+?):(\d{2})(\d{2})([0-9A-Fa-f]+)([0-9A-Fa-f]{8})$`)
@@ -73,16 +74,17 @@ func DecodeBIP276(text string) (*BIP276, error) {
 		Prefix: res[1],
 	}
 
-	version, err := strconv.Atoi(res[2])
+	// The network precedes the version, as written by createBIP276.
+	net, err := strconv.Atoi(res[2])
 	if err != nil {
 		return nil, err
 	}
-	s.Version = version
-	network, err := strconv.Atoi(res[3])
+	s.Network = net
+	version, err := strconv.Atoi(res[3])
 	if err != nil {
 		return nil, err
 	}
-	s.Network = network
+	s.Version = version
 	data, err := hex.DecodeString(res[4])
 	if err != nil {
 		return nil, err
@@ -94,3 +96,19 @@ func DecodeBIP276(text string) (*BIP276, error) {
 
 	return &s, nil
 }
+
+// NewBIP276 returns BIP276 data of the current version for the network provided.
+func NewBIP276(prefix string, net *network.Params, data []byte) BIP276 {
+	return BIP276{
+		Prefix:  prefix,
+		Version: CurrentVersion,
+		Network: net.BIP276Network,
+		Data:    data,
+	}
+}
+
+// NetworkParams returns the parameters of the network the data is valid for.
+// Test networks share a BIP276 network identifier, and so resolve to network.Testnet.
+func (b *BIP276) NetworkParams() (*network.Params, error) {
+	return network.ByBIP276Network(b.Network)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -127,4 +128,24 @@ func TestDecodeBIP276(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, script)
 	})
-}
\ No newline at end of file
+}
+
+func TestBIP276_Network(t *testing.T) {
+	t.Parallel()
+
+	s := bscript.EncodeBIP276(bscript.NewBIP276(bscript.PrefixScript, network.Regtest, []byte("fake script")))
+	assert.Equal(t, "bitcoin-script:020166616b65207363726970742577a444", s)
+
+	b, err := bscript.DecodeBIP276(s)
+	assert.NoError(t, err)
+	assert.Equal(t, bscript.NetworkTestnet, b.Network)
+	assert.Equal(t, bscript.CurrentVersion, b.Version)
+
+	net, err := b.NetworkParams()
+	assert.NoError(t, err)
+	assert.Equal(t, network.Testnet, net)
+
+	b.Network = 9
+	_, err = b.NetworkParams()
+	assert.ErrorIs(t, err, network.ErrUnknownNetwork)
+}
@@ -11,18 +11,30 @@ var (
 
 // Sentinel errors raised by addresses.
 var (
-	ErrInvalidAddressLength = errors.New("invalid address length")
-	ErrUnsupportedAddress   = errors.New("address not supported")
+	ErrInvalidAddressLength   = errors.New("invalid address length")
+	ErrInvalidAddressChecksum = errors.New("invalid address checksum")
+	ErrUnsupportedAddress     = errors.New("address not supported")
+	ErrUnknownAddressVersion  = errors.New("unknown address version")
+	ErrNoAddress              = errors.New("locking script does not pay to an address")
 )
 
 // Sentinel errors raised through encoding.
 var (
 	ErrEncodingBadChar         = errors.New("bad char")
 	ErrEncodingTooLong         = errors.New("too long")
-	ErrEncodingInvalidVersion  = errors.New("not version 0 of 6f")
+	ErrEncodingInvalidVersion  = errors.New("not a known address version")
 	ErrEncodingInvalidChecksum = errors.New("invalid checksum")
 	ErrEncodingChecksumFailed  = errors.New("checksum failed")
 	ErrTextNoBIP76             = errors.New("text did not match the bip276 format")
+	ErrWrongNetwork            = errors.New("address is not for the network")
+)
+
+// Sentinel errors raised by script templates.
+var (
+	ErrInvalidTemplate  = errors.New("template must be non nil and named")
+	ErrTemplateMismatch = errors.New("script does not match template")
+	ErrPatternSyntax    = errors.New("invalid script pattern")
+	ErrAssemble         = errors.New("invalid assembler source")
 )
 
 // Sentinel errors raised by the package.
@@ -32,4 +44,12 @@ var (
 	ErrEmptyScript       = errors.New("script is empty")
 	ErrNotP2PKH          = errors.New("not a P2PKH")
 	ErrInvalidOpcodeType = errors.New("use AppendPushData for push data funcs")
+	ErrNotHashPuzzle     = errors.New("not a hash puzzle")
+	ErrNotRPuzzle        = errors.New("not an R-puzzle")
+	ErrInvalidRValue     = errors.New("invalid R value")
+	ErrInvalidPKHLen     = errors.New("invalid public key hash length")
+	ErrNotTimelock       = errors.New("not a timelocked P2PKH")
+	ErrInvalidLockTime   = errors.New("lock time must be 4 bytes little endian")
+	ErrInvalidMultiSig   = errors.New("invalid multisig parameters")
+	ErrInvalidSigHash    = errors.New("invalid sighash flag")
 )
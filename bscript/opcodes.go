@@ -453,7 +453,9 @@ var opCodeStrings = map[string]byte{
 	"OP_CHECKMULTISIGVERIFY": OpCHECKMULTISIGVERIFY,
 	"OP_NOP1":                OpNOP1,
 	"OP_NOP2":                OpNOP2,
+	"OP_CHECKLOCKTIMEVERIFY": OpCHECKLOCKTIMEVERIFY,
 	"OP_NOP3":                OpNOP3,
+	"OP_CHECKSEQUENCEVERIFY": OpCHECKSEQUENCEVERIFY,
 	"OP_NOP4":                OpNOP4,
 	"OP_NOP5":                OpNOP5,
 	"OP_NOP6":                OpNOP6,
@@ -0,0 +1,65 @@
+package bscript
+
+import "encoding/binary"
+
+// scriptOp is a single decoded operation of a script, being either
+// an opcode or a data push.
+type scriptOp struct {
+	// op the opcode, which for data pushes is the push opcode used.
+	op byte
+	// data the data pushed, if op is a data push.
+	data []byte
+	// offset the position of the operation within the script.
+	offset int
+}
+
+// isPush returns true if the operation pushes data, including OP_0.
+func (o scriptOp) isPush() bool {
+	return o.op <= OpPUSHDATA4
+}
+
+// decodeOps decodes b into its operations, keeping the distinction between
+// opcodes and data pushes which DecodeParts does not.
+func decodeOps(b []byte) ([]scriptOp, error) {
+	ops := make([]scriptOp, 0)
+	for i := 0; i < len(b); {
+		op := scriptOp{op: b[i], offset: i}
+		i++
+
+		var l int
+		switch {
+		case op.op >= OpDATA1 && op.op <= OpDATA75:
+			l = int(op.op)
+		case op.op == OpPUSHDATA1:
+			if len(b) < i+1 {
+				return ops, ErrDataTooSmall
+			}
+			l = int(b[i])
+			i++
+		case op.op == OpPUSHDATA2:
+			if len(b) < i+2 {
+				return ops, ErrDataTooSmall
+			}
+			l = int(binary.LittleEndian.Uint16(b[i:]))
+			i += 2
+		case op.op == OpPUSHDATA4:
+			if len(b) < i+4 {
+				return ops, ErrDataTooSmall
+			}
+			l = int(binary.LittleEndian.Uint32(b[i:]))
+			i += 4
+		}
+
+		if op.isPush() {
+			if len(b) < i+l {
+				return ops, ErrDataTooSmall
+			}
+			op.data = b[i : i+l]
+			i += l
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
@@ -0,0 +1,349 @@
+package bscript
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pattern is a compiled script pattern which can be matched against scripts.
+//
+// A pattern is a whitespace separated list of elements:
+//
+//	OP_DUP            the opcode OP_DUP
+//	c28f832c          a push of exactly the data c28f832c
+//	<20 bytes:pkh>    a push of 20 bytes, captured as "pkh"
+//	<33-65 bytes>     a push of between 33 and 65 bytes, not captured
+//	<2- bytes>        a push of at least 2 bytes
+//	<bytes:data>      a push of any length, captured as "data"
+//	<num:lock>        a minimally encoded number, including OP_0, OP_1NEGATE and OP_1 to OP_16
+//	*                 any single opcode or push
+//	...               any number of opcodes or pushes, including none
+//	[ OP_DROP ]       an optional segment
+//
+// For example, a P2PKH script is matched by
+//
+//	OP_DUP OP_HASH160 <20 bytes:pkh> OP_EQUALVERIFY OP_CHECKSIG
+type Pattern struct {
+	src   string
+	elems []patternElem
+}
+
+// PatternCaptures are the named pushes captured when matching a Pattern.
+type PatternCaptures map[string][]byte
+
+// Int returns a capture decoded as a script number.
+func (c PatternCaptures) Int(name string) (int64, bool) {
+	b, ok := c[name]
+	if !ok {
+		return 0, false
+	}
+
+	return decodeScriptNum(b), true
+}
+
+type patternElemType int
+
+const (
+	patternOpcode patternElemType = iota
+	patternLiteral
+	patternPush
+	patternNum
+	patternAny
+	patternAnyMany
+	patternOptional
+)
+
+type patternElem struct {
+	typ   patternElemType
+	op    byte
+	data  []byte
+	min   int
+	max   int
+	name  string
+	group []patternElem
+}
+
+// CompilePattern compiles the pattern source into a Pattern. The syntax
+// is described on the Pattern type.
+func CompilePattern(src string) (*Pattern, error) {
+	tokens, err := tokenisePattern(src)
+	if err != nil {
+		return nil, err
+	}
+
+	elems, rest, err := parsePattern(tokens, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%w: unexpected '%s'", ErrPatternSyntax, rest[0])
+	}
+
+	return &Pattern{src: src, elems: elems}, nil
+}
+
+// MustCompilePattern is like CompilePattern but panics if the pattern is invalid.
+// It is intended for package level pattern variables.
+func MustCompilePattern(src string) *Pattern {
+	p, err := CompilePattern(src)
+	if err != nil {
+		panic(err)
+	}
+
+	return p
+}
+
+// String returns the source of the pattern.
+func (p *Pattern) String() string {
+	return p.src
+}
+
+// Match matches the script against the pattern, returning the named captures
+// and true if the whole script matches.
+func (p *Pattern) Match(s *Script) (PatternCaptures, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	ops, err := decodeOps(*s)
+	if err != nil {
+		return nil, false
+	}
+
+	return matchPattern(p.elems, ops, PatternCaptures{})
+}
+
+// Matches returns true if the whole script matches the pattern.
+func (p *Pattern) Matches(s *Script) bool {
+	_, ok := p.Match(s)
+	return ok
+}
+
+// MatchPattern returns the captures and true if the script matches the pattern source.
+// If the same pattern is used repeatedly, compile it once with CompilePattern instead.
+func (s *Script) MatchPattern(src string) (PatternCaptures, bool, error) {
+	p, err := CompilePattern(src)
+	if err != nil {
+		return nil, false, err
+	}
+
+	caps, ok := p.Match(s)
+	return caps, ok, nil
+}
+
+func matchPattern(elems []patternElem, ops []scriptOp, caps PatternCaptures) (PatternCaptures, bool) {
+	if len(elems) == 0 {
+		return caps, len(ops) == 0
+	}
+
+	e, rest := elems[0], elems[1:]
+	switch e.typ {
+	case patternAnyMany:
+		for i := 0; i <= len(ops); i++ {
+			if c, ok := matchPattern(rest, ops[i:], caps); ok {
+				return c, true
+			}
+		}
+		return nil, false
+	case patternOptional:
+		group := make([]patternElem, 0, len(e.group)+len(rest))
+		group = append(group, e.group...)
+		group = append(group, rest...)
+		if c, ok := matchPattern(group, ops, caps); ok {
+			return c, true
+		}
+		return matchPattern(rest, ops, caps)
+	}
+
+	if len(ops) == 0 {
+		return nil, false
+	}
+
+	op := ops[0]
+	var captured []byte
+	switch e.typ {
+	case patternOpcode:
+		if op.op != e.op || (op.isPush() && len(op.data) > 0) {
+			return nil, false
+		}
+	case patternLiteral:
+		if !op.isPush() || string(op.data) != string(e.data) {
+			return nil, false
+		}
+	case patternPush:
+		if !op.isPush() || len(op.data) < e.min || (e.max >= 0 && len(op.data) > e.max) {
+			return nil, false
+		}
+		captured = op.data
+	case patternNum:
+		n, ok := opNumber(op)
+		if !ok {
+			return nil, false
+		}
+		captured = encodeScriptNum(n)
+	}
+
+	if e.name != "" {
+		c := make(PatternCaptures, len(caps)+1)
+		for k, v := range caps {
+			c[k] = v
+		}
+		c[e.name] = captured
+		caps = c
+	}
+
+	return matchPattern(rest, ops[1:], caps)
+}
+
+// opNumber returns the number pushed by the operation, if it is a minimally encoded number.
+func opNumber(op scriptOp) (int64, bool) {
+	switch {
+	case op.op == OpZERO:
+		return 0, true
+	case op.op == Op1NEGATE:
+		return -1, true
+	case op.op >= Op1 && op.op <= Op16:
+		return int64(op.op-Op1) + 1, true
+	case op.op < OpDATA1 || op.op > OpDATA75 || len(op.data) > 8:
+		return 0, false
+	}
+
+	n := decodeScriptNum(op.data)
+	if (n >= -1 && n <= 16) || string(encodeScriptNum(n)) != string(op.data) {
+		return 0, false
+	}
+
+	return n, true
+}
+
+func tokenisePattern(src string) ([]string, error) {
+	tokens := make([]string, 0)
+	for i := 0; i < len(src); {
+		switch c := src[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '[' || c == ']':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '<':
+			end := strings.IndexByte(src[i:], '>')
+			if end == -1 {
+				return nil, fmt.Errorf("%w: unterminated '<' at %d", ErrPatternSyntax, i)
+			}
+			tokens = append(tokens, src[i:i+end+1])
+			i += end + 1
+		default:
+			j := i
+			for j < len(src) && !strings.ContainsRune(" \t\n\r[]<", rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, src[i:j])
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+func parsePattern(tokens []string, inGroup bool) ([]patternElem, []string, error) {
+	elems := make([]patternElem, 0)
+	for len(tokens) > 0 {
+		tok := tokens[0]
+		tokens = tokens[1:]
+
+		switch {
+		case tok == "]":
+			if !inGroup {
+				return nil, nil, fmt.Errorf("%w: unexpected ']'", ErrPatternSyntax)
+			}
+			return elems, tokens, nil
+		case tok == "[":
+			group, rest, err := parsePattern(tokens, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			elems = append(elems, patternElem{typ: patternOptional, group: group})
+			tokens = rest
+		case tok == "*":
+			elems = append(elems, patternElem{typ: patternAny})
+		case tok == "...":
+			elems = append(elems, patternElem{typ: patternAnyMany})
+		case strings.HasPrefix(tok, "<"):
+			e, err := parsePatternPush(tok)
+			if err != nil {
+				return nil, nil, err
+			}
+			elems = append(elems, e)
+		default:
+			if op, ok := opCodeStrings[tok]; ok {
+				elems = append(elems, patternElem{typ: patternOpcode, op: op})
+				continue
+			}
+			data, err := hex.DecodeString(tok)
+			if err != nil || len(data) == 0 {
+				return nil, nil, fmt.Errorf("%w: unknown element '%s'", ErrPatternSyntax, tok)
+			}
+			elems = append(elems, patternElem{typ: patternLiteral, data: data})
+		}
+	}
+
+	if inGroup {
+		return nil, nil, fmt.Errorf("%w: unterminated '['", ErrPatternSyntax)
+	}
+
+	return elems, tokens, nil
+}
+
+// parsePatternPush parses a push element of the form <[min[-[max]]] bytes[:name]> or <num[:name]>.
+func parsePatternPush(tok string) (patternElem, error) {
+	body := strings.TrimSpace(tok[1 : len(tok)-1])
+
+	var name string
+	if idx := strings.LastIndexByte(body, ':'); idx != -1 {
+		name = strings.TrimSpace(body[idx+1:])
+		body = strings.TrimSpace(body[:idx])
+		if name == "" {
+			return patternElem{}, fmt.Errorf("%w: empty capture name in '%s'", ErrPatternSyntax, tok)
+		}
+	}
+
+	if body == "num" {
+		return patternElem{typ: patternNum, name: name}, nil
+	}
+
+	fields := strings.Fields(body)
+	if len(fields) == 0 || len(fields) > 2 || fields[len(fields)-1] != "bytes" {
+		return patternElem{}, fmt.Errorf("%w: invalid push '%s'", ErrPatternSyntax, tok)
+	}
+
+	e := patternElem{typ: patternPush, name: name, max: -1}
+	if len(fields) == 1 {
+		return e, nil
+	}
+
+	lower, upper := fields[0], ""
+	isRange := strings.Contains(lower, "-")
+	if isRange {
+		idx := strings.IndexByte(lower, '-')
+		lower, upper = lower[:idx], lower[idx+1:]
+	}
+
+	n, err := strconv.Atoi(lower)
+	if err != nil || n < 0 {
+		return patternElem{}, fmt.Errorf("%w: invalid length in '%s'", ErrPatternSyntax, tok)
+	}
+	e.min, e.max = n, n
+
+	if isRange {
+		e.max = -1
+		if upper != "" {
+			if e.max, err = strconv.Atoi(upper); err != nil || e.max < n {
+				return patternElem{}, fmt.Errorf("%w: invalid length in '%s'", ErrPatternSyntax, tok)
+			}
+		}
+	}
+
+	return e, nil
+}
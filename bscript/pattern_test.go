@@ -0,0 +1,194 @@
+package bscript_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPattern_Match(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		pattern  string
+		script   string
+		expMatch bool
+		expCaps  bscript.PatternCaptures
+	}{
+		"p2pkh with capture": {
+			pattern:  "OP_DUP OP_HASH160 <20 bytes:pkh> OP_EQUALVERIFY OP_CHECKSIG",
+			script:   "76a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+			expMatch: true,
+			expCaps: bscript.PatternCaptures{
+				"pkh": mustDecodeHex(t, "c28f832c3d539933e0c719297340b34eee0f4c34"),
+			},
+		},
+		"p2pkh with wrong length push": {
+			pattern: "OP_DUP OP_HASH160 <20 bytes:pkh> OP_EQUALVERIFY OP_CHECKSIG",
+			script:  "76a913c28f832c3d539933e0c719297340b34eee0f4c88ac",
+		},
+		"trailing ops do not match": {
+			pattern: "OP_DUP OP_HASH160 <20 bytes> OP_EQUALVERIFY OP_CHECKSIG",
+			script:  "76a914c28f832c3d539933e0c719297340b34eee0f4c3488ac75",
+		},
+		"length range": {
+			pattern:  "<33-65 bytes:pk> OP_CHECKSIG",
+			script:   "21023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6ac",
+			expMatch: true,
+			expCaps: bscript.PatternCaptures{
+				"pk": mustDecodeHex(t, "023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6"),
+			},
+		},
+		"length range too short": {
+			pattern: "<33-65 bytes:pk> OP_CHECKSIG",
+			script:  "0102ac",
+		},
+		"minimum length": {
+			pattern:  "<2- bytes>",
+			script:   "4c4c" + "00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{},
+		},
+		"literal push": {
+			pattern:  "OP_FALSE OP_RETURN 746f6b656e <bytes:data>",
+			script:   "006a05746f6b656e0568656c6c6f",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{"data": []byte("hello")},
+		},
+		"literal push mismatch": {
+			pattern: "OP_FALSE OP_RETURN 746f6b656e <bytes:data>",
+			script:  "006a05746f6b656f0568656c6c6f",
+		},
+		"wildcard any single": {
+			pattern:  "* OP_DROP OP_TRUE",
+			script:   "0568656c6c6f7551",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{},
+		},
+		"wildcard many": {
+			pattern:  "OP_FALSE OP_RETURN ...",
+			script:   "006a0568656c6c6f0568656c6c6f0568656c6c6f",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{},
+		},
+		"wildcard many matches none": {
+			pattern:  "OP_FALSE OP_RETURN ...",
+			script:   "006a",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{},
+		},
+		"wildcard many before suffix": {
+			pattern:  "... OP_DUP OP_HASH160 <20 bytes:pkh> OP_EQUALVERIFY OP_CHECKSIG",
+			script:   "0063036f726451126170706c69636174696f6e2f6a736f6e0068" + "76a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+			expMatch: true,
+			expCaps: bscript.PatternCaptures{
+				"pkh": mustDecodeHex(t, "c28f832c3d539933e0c719297340b34eee0f4c34"),
+			},
+		},
+		"optional segment present": {
+			pattern:  "[ <bytes:tag> OP_DROP ] OP_DUP OP_HASH160 <20 bytes:pkh> OP_EQUALVERIFY OP_CHECKSIG",
+			script:   "03746b6e7576a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+			expMatch: true,
+			expCaps: bscript.PatternCaptures{
+				"tag": []byte("tkn"),
+				"pkh": mustDecodeHex(t, "c28f832c3d539933e0c719297340b34eee0f4c34"),
+			},
+		},
+		"optional segment absent": {
+			pattern:  "[ <bytes:tag> OP_DROP ] OP_DUP OP_HASH160 <20 bytes:pkh> OP_EQUALVERIFY OP_CHECKSIG",
+			script:   "76a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+			expMatch: true,
+			expCaps: bscript.PatternCaptures{
+				"pkh": mustDecodeHex(t, "c28f832c3d539933e0c719297340b34eee0f4c34"),
+			},
+		},
+		"number capture": {
+			pattern:  "<num:lock> OP_CHECKLOCKTIMEVERIFY OP_DROP ...",
+			script:   "03b0710bb17576a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{"lock": {0xb0, 0x71, 0x0b}},
+		},
+		"small number capture": {
+			pattern:  "<num:m> ... <num:n> OP_CHECKMULTISIG",
+			script:   "51210279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f8179851ae",
+			expMatch: true,
+			expCaps:  bscript.PatternCaptures{"m": {0x01}, "n": {0x01}},
+		},
+		"non minimal number does not match": {
+			pattern: "<num:n>",
+			script:  "0105",
+		},
+		"invalid script does not match": {
+			pattern: "...",
+			script:  "4c",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := bscript.CompilePattern(test.pattern)
+			assert.NoError(t, err)
+
+			s, err := bscript.NewFromHexString(test.script)
+			assert.NoError(t, err)
+
+			caps, ok := p.Match(s)
+			assert.Equal(t, test.expMatch, ok)
+			assert.Equal(t, test.expMatch, p.Matches(s))
+			if test.expMatch {
+				assert.Equal(t, test.expCaps, caps)
+			}
+		})
+	}
+}
+
+func TestPatternCaptures_Int(t *testing.T) {
+	t.Parallel()
+
+	s, err := bscript.NewFromHexString("03b0710bb17576a914c28f832c3d539933e0c719297340b34eee0f4c3488ac")
+	assert.NoError(t, err)
+
+	caps, ok, err := s.MatchPattern("<num:lock> OP_CHECKLOCKTIMEVERIFY OP_DROP ...")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	n, ok := caps.Int("lock")
+	assert.True(t, ok)
+	assert.Equal(t, int64(750000), n)
+
+	_, ok = caps.Int("missing")
+	assert.False(t, ok)
+}
+
+func TestCompilePattern_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"unknown opcode":       "OP_NOTREAL",
+		"unterminated push":    "<20 bytes",
+		"unterminated group":   "[ OP_DROP",
+		"unexpected close":     "OP_DROP ]",
+		"invalid push":         "<20 things>",
+		"invalid length":       "<x bytes>",
+		"invalid range":        "<20-10 bytes>",
+		"empty capture name":   "<20 bytes:>",
+		"odd length hex value": "abc",
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := bscript.CompilePattern(src)
+			assert.ErrorIs(t, err, bscript.ErrPatternSyntax)
+		})
+	}
+
+	assert.Panics(t, func() { bscript.MustCompilePattern("OP_NOTREAL") })
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+	return b
+}
@@ -0,0 +1,152 @@
+package bscript
+
+import (
+	"bytes"
+
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2/sighash"
+)
+
+// rPuzzlePrefix extracts the R value from a DER signature which sits second
+// on the stack, leaving the R value on top of the stack for comparison.
+var rPuzzlePrefix = []byte{
+	OpOVER, Op3, OpSPLIT, OpNIP, Op1, OpSPLIT, OpSWAP, OpSPLIT, OpDROP,
+}
+
+// NewHashPuzzleFromSecret takes a secret and a public key hash and creates a
+// hash puzzle + P2PKH locking script from it. The secret is hashed with HASH160
+// before being committed to the script.
+func NewHashPuzzleFromSecret(secret, pubKeyHash []byte) (*Script, error) {
+	return NewHashPuzzle(crypto.Hash160(secret), pubKeyHash)
+}
+
+// NewHashPuzzle takes the HASH160 of a secret and a public key hash and creates
+// a hash puzzle + P2PKH locking script from it:
+//
+//	OP_HASH160 <secretHash> OP_EQUALVERIFY OP_DUP OP_HASH160 <pubKeyHash> OP_EQUALVERIFY OP_CHECKSIG
+func NewHashPuzzle(secretHash, pubKeyHash []byte) (*Script, error) {
+	s := &Script{}
+
+	_ = s.AppendOpcodes(OpHASH160)
+	if err := s.AppendPushData(secretHash); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(OpEQUALVERIFY, OpDUP, OpHASH160)
+
+	if err := s.AppendPushData(pubKeyHash); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(OpEQUALVERIFY, OpCHECKSIG)
+
+	return s, nil
+}
+
+// NewHashPuzzleUnlockingScript creates a new unlocking script which spends
+// a hash puzzle locking script from a public key, a signature, a SIGHASH flag
+// and the secret preimage.
+func NewHashPuzzleUnlockingScript(pubKey, sig []byte, sigHashFlag sighash.Flag, secret []byte) (*Script, error) {
+	s, err := NewP2PKHUnlockingScript(pubKey, sig, sigHashFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.AppendPushData(secret); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// NewRPuzzle takes the R value of a signature, as it is encoded in a DER signature,
+// and creates an R-puzzle locking script which can only be unlocked by a signature
+// using that R value:
+//
+//	OP_OVER OP_3 OP_SPLIT OP_NIP OP_1 OP_SPLIT OP_SWAP OP_SPLIT OP_DROP <r> OP_EQUALVERIFY OP_CHECKSIG
+func NewRPuzzle(r []byte) (*Script, error) {
+	if len(r) == 0 {
+		return nil, ErrInvalidRValue
+	}
+
+	s := &Script{}
+	*s = append(*s, rPuzzlePrefix...)
+
+	if err := s.AppendPushData(r); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(OpEQUALVERIFY, OpCHECKSIG)
+
+	return s, nil
+}
+
+// NewRPuzzleUnlockingScript creates a new unlocking script which spends
+// an R-puzzle locking script from a public key, a signature, and a SIGHASH flag.
+func NewRPuzzleUnlockingScript(pubKey, sig []byte, sigHashFlag sighash.Flag) (*Script, error) {
+	return NewP2PKHUnlockingScript(pubKey, sig, sigHashFlag)
+}
+
+// IsHashPuzzle returns true if this is a hash puzzle + P2PKH output script.
+func (s *Script) IsHashPuzzle() bool {
+	parts, err := DecodeParts(*s)
+	if err != nil || len(parts) != 8 {
+		return false
+	}
+
+	return isOpcode(parts[0], OpHASH160) &&
+		len(parts[1]) == 20 &&
+		isOpcode(parts[2], OpEQUALVERIFY) &&
+		isOpcode(parts[3], OpDUP) &&
+		isOpcode(parts[4], OpHASH160) &&
+		len(parts[5]) == 20 &&
+		isOpcode(parts[6], OpEQUALVERIFY) &&
+		isOpcode(parts[7], OpCHECKSIG)
+}
+
+// IsRPuzzle returns true if this is an R-puzzle output script.
+func (s *Script) IsRPuzzle() bool {
+	b := []byte(*s)
+	if len(b) < len(rPuzzlePrefix)+4 || !bytes.HasPrefix(b, rPuzzlePrefix) {
+		return false
+	}
+
+	parts, err := DecodeParts(b[len(rPuzzlePrefix):])
+	if err != nil || len(parts) != 3 {
+		return false
+	}
+
+	return len(parts[0]) > 1 &&
+		isOpcode(parts[1], OpEQUALVERIFY) &&
+		isOpcode(parts[2], OpCHECKSIG)
+}
+
+// HashPuzzleParts returns the secret hash and public key hash committed to
+// by a hash puzzle script.
+func (s *Script) HashPuzzleParts() (secretHash, pubKeyHash []byte, err error) {
+	if !s.IsHashPuzzle() {
+		return nil, nil, ErrNotHashPuzzle
+	}
+
+	parts, err := DecodeParts(*s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parts[1], parts[5], nil
+}
+
+// RPuzzleValue returns the R value committed to by an R-puzzle script.
+func (s *Script) RPuzzleValue() ([]byte, error) {
+	if !s.IsRPuzzle() {
+		return nil, ErrNotRPuzzle
+	}
+
+	parts, err := DecodeParts((*s)[len(rPuzzlePrefix):])
+	if err != nil {
+		return nil, err
+	}
+
+	return parts[0], nil
+}
+
+func isOpcode(part []byte, op byte) bool {
+	return len(part) == 1 && part[0] == op
+}
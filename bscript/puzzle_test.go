@@ -0,0 +1,93 @@
+package bscript_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/sighash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHashPuzzle(t *testing.T) {
+	t.Parallel()
+
+	pkh, err := hex.DecodeString("c28f832c3d539933e0c719297340b34eee0f4c34")
+	assert.NoError(t, err)
+
+	s, err := bscript.NewHashPuzzleFromSecret([]byte("secret1"), pkh)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"a914d3f9e3d971764be5838307b175ee4e08ba427b908876a914c28f832c3d539933e0c719297340b34eee0f4c3488ac",
+		s.String(),
+	)
+	assert.True(t, s.IsHashPuzzle())
+	assert.False(t, s.IsRPuzzle())
+	assert.False(t, s.IsP2PKH())
+
+	secretHash, pubKeyHash, err := s.HashPuzzleParts()
+	assert.NoError(t, err)
+	assert.Equal(t, "d3f9e3d971764be5838307b175ee4e08ba427b90", hex.EncodeToString(secretHash))
+	assert.Equal(t, pkh, pubKeyHash)
+
+	p2pkh, err := bscript.NewP2PKHFromPubKeyHash(pkh)
+	assert.NoError(t, err)
+	_, _, err = p2pkh.HashPuzzleParts()
+	assert.ErrorIs(t, err, bscript.ErrNotHashPuzzle)
+}
+
+func TestNewHashPuzzleUnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	pubKey, err := hex.DecodeString("02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66")
+	assert.NoError(t, err)
+
+	s, err := bscript.NewHashPuzzleUnlockingScript(pubKey, []byte("some-signature"), sighash.AllForkID, []byte("secret1"))
+	assert.NoError(t, err)
+
+	asm, err := s.ToASM()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"736f6d652d7369676e617475726541 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66 73656372657431",
+		asm,
+	)
+}
+
+func TestNewRPuzzle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid r", func(t *testing.T) {
+		r, err := hex.DecodeString("00c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5")
+		assert.NoError(t, err)
+
+		s, err := bscript.NewRPuzzle(r)
+		assert.NoError(t, err)
+
+		asm, err := s.ToASM()
+		assert.NoError(t, err)
+		assert.Equal(t,
+			"OP_OVER OP_3 OP_SPLIT OP_NIP OP_TRUE OP_SPLIT OP_SWAP OP_SPLIT OP_DROP "+
+				"00c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5 OP_EQUALVERIFY OP_CHECKSIG",
+			asm,
+		)
+		assert.True(t, s.IsRPuzzle())
+		assert.False(t, s.IsHashPuzzle())
+
+		v, err := s.RPuzzleValue()
+		assert.NoError(t, err)
+		assert.Equal(t, r, v)
+	})
+
+	t.Run("empty r", func(t *testing.T) {
+		_, err := bscript.NewRPuzzle(nil)
+		assert.ErrorIs(t, err, bscript.ErrInvalidRValue)
+	})
+
+	t.Run("not an r puzzle", func(t *testing.T) {
+		s, err := bscript.NewFromHexString("76a914c28f832c3d539933e0c719297340b34eee0f4c3488ac")
+		assert.NoError(t, err)
+
+		_, err = s.RPuzzleValue()
+		assert.ErrorIs(t, err, bscript.ErrNotRPuzzle)
+	})
+}
@@ -44,23 +44,43 @@ func NewFromBytes(b []byte) *Script {
 	return &s
 }
 
-// NewFromASM creates a new script from a BitCoin ASM formatted string.
+// NewFromASM creates a new script from a BitCoin ASM formatted string, as output
+// by ToASM.
+//
+// Signatures decorated with their sighash flag, as output by ToNodeASM when
+// decoding sighashes, such as <sig>[ALL|FORKID], are pushed with the flag as
+// their last byte. To parse the numbers of ToNodeASM too, use NewFromNodeASM.
 func NewFromASM(str string) (*Script, error) {
 	s := Script{}
 
 	for _, section := range strings.Split(str, " ") {
-		if val, ok := opCodeStrings[section]; ok {
-			_ = s.AppendOpcodes(val)
-		} else {
-			if err := s.AppendPushDataHexString(section); err != nil {
-				return nil, ErrInvalidOpCode
-			}
+		if err := s.appendASM(section); err != nil {
+			return nil, err
 		}
 	}
 
 	return &s, nil
 }
 
+// appendASM appends an opcode, a signature decorated with its sighash flag or a
+// push of hex data, from a section of ASM.
+func (s *Script) appendASM(section string) error {
+	if val, ok := opCodeStrings[section]; ok {
+		return s.AppendOpcodes(val)
+	}
+	if sig, ok, err := decodeSigHashASM(section); ok {
+		if err != nil {
+			return err
+		}
+		return s.AppendPushData(sig)
+	}
+	if err := s.AppendPushDataHexString(section); err != nil {
+		return ErrInvalidOpCode
+	}
+
+	return nil
+}
+
 // NewP2PKHFromPubKeyEC takes a public key hex string (in
 // compressed format) and creates a P2PKH script from it.
 func NewP2PKHFromPubKeyEC(pubKey *bec.PublicKey) (*Script, error) {
@@ -120,20 +140,11 @@ func NewP2PKHFromAddress(addr string) (*Script, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	var publicKeyHashBytes []byte
-	if publicKeyHashBytes, err = hex.DecodeString(a.PublicKeyHash); err != nil {
-		return nil, err
-	}
-
-	s := new(Script)
-	_ = s.AppendOpcodes(OpDUP, OpHASH160)
-	if err = s.AppendPushData(publicKeyHashBytes); err != nil {
-		return nil, err
+	if a.Type != AddressTypeP2PKH {
+		return nil, ErrNotP2PKH
 	}
-	_ = s.AppendOpcodes(OpEQUALVERIFY, OpCHECKSIG)
 
-	return s, nil
+	return a.LockingScript()
 }
 
 // NewP2PKHFromBip32ExtKey takes a *bip32.ExtendedKey and creates a P2PKH script from it,
@@ -322,6 +333,27 @@ func (s *Script) IsData() bool {
 		(len(b) > 1 && b[0] == OpFALSE && b[1] == OpRETURN)
 }
 
+// DataOffset returns the offset in the script at which its data begins, and true,
+// or false if the script carries no data. Data begins at an OP_RETURN starting the
+// script, or at an OP_FALSE OP_RETURN anywhere in the script, such as following the
+// locking script of an inscription. Everything from the offset on is data.
+func (s *Script) DataOffset() (int, bool) {
+	b := []byte(*s)
+	if len(b) > 0 && b[0] == OpRETURN {
+		return 0, true
+	}
+
+	// the data itself need not decode
+	ops, _ := decodeOps(b)
+	for i := 0; i+1 < len(ops); i++ {
+		if ops[i].op == OpFALSE && ops[i+1].op == OpRETURN {
+			return ops[i].offset, true
+		}
+	}
+
+	return 0, false
+}
+
 // IsMultiSigOut returns true if this is a multisig output script.
 func (s *Script) IsMultiSigOut() bool {
 	parts, err := DecodeParts(*s)
@@ -370,42 +402,43 @@ func (s *Script) PublicKeyHash() ([]byte, error) {
 }
 
 // ScriptType returns the type of script this is as a string.
+//
+// The script is matched against the templates in the DefaultTemplates registry,
+// so custom types can be reported by registering a ScriptTemplate. Hash puzzles,
+// R-puzzles and timelocked P2PKH scripts are reported as nonstandard, as they
+// always have been; their template is returned by MatchTemplate.
 func (s *Script) ScriptType() string {
 	if len(*s) == 0 {
 		return ScriptTypeEmpty
 	}
-	if s.IsP2PKH() {
-		return ScriptTypePubKeyHash
-	}
-	if s.IsP2PK() {
-		return ScriptTypePubKey
+	t, ok := MatchTemplate(s)
+	if !ok {
+		return ScriptTypeNonStandard
 	}
-	if s.IsMultiSigOut() {
-		return ScriptTypeMultiSig
+	switch t.(type) {
+	case HashPuzzleTemplate, RPuzzleTemplate, CLTVP2PKHTemplate, CSVP2PKHTemplate:
+		return ScriptTypeNonStandard
 	}
-	if s.IsData() {
-		return ScriptTypeNullData
-	}
-	return ScriptTypeNonStandard
+
+	return t.Name()
 }
 
 // Addresses will return all addresses found in the script, if any.
+//
+// Addresses are found by matching the script against the templates in the
+// DefaultTemplates registry which implement AddressTemplate.
 func (s *Script) Addresses() ([]string, error) {
-	addresses := make([]string, 0)
-	if s.IsP2PKH() {
-		pkh, err := s.PublicKeyHash()
-		if err != nil {
-			return nil, err
-		}
-		a, err := NewAddressFromPublicKeyHash(pkh, true)
-		if err != nil {
-			return nil, err
-		}
-		addresses = []string{a.AddressString}
+	t, ok := MatchTemplate(s)
+	if !ok {
+		return []string{}, nil
+	}
+	at, ok := t.(AddressTemplate)
+	if !ok {
+		return []string{}, nil
 	}
-	// TODO: handle multisig, and other outputs
+	// TODO: handle multisig
 	// https://github.com/libsv/go-bt/issues/6
-	return addresses, nil
+	return at.Addresses(s)
 }
 
 // Equals will compare the script to b and return true if they match.
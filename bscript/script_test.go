@@ -123,6 +123,185 @@ func TestScript_ToASM(t *testing.T) {
 	}
 }
 
+func TestNewFromNodeASM_ToNodeASMRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		script string
+		expASM string
+	}{
+		"p2pkh unlocking script": {
+			script: "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+			expASM: "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[ALL|FORKID] 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+		},
+		"none anyonecanpay signature": {
+			script: "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cfc2",
+			expASM: "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[NONE|FORKID|ANYONECANPAY]",
+		},
+		"non DER data is not decoded": {
+			script: "0648656c6c6f41",
+			expASM: "48656c6c6f41",
+		},
+		"locking script is unchanged": {
+			script: "76a914e2a623699e81b291c0327f408fea765d534baa2a88ac",
+			expASM: "OP_DUP OP_HASH160 e2a623699e81b291c0327f408fea765d534baa2a OP_EQUALVERIFY OP_CHECKSIG",
+		},
+		"multisig unlocking script": {
+			script: "004730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41",
+			expASM: "0 30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[ALL|FORKID]",
+		},
+		"multisig locking script": {
+			script: "51210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf852ae",
+			expASM: "1 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8 2 OP_CHECKMULTISIG",
+		},
+		"numbers": {
+			script: "004f515f6002ff0002e8830400e1f5050500e40b5402",
+			expASM: "0 -1 1 15 16 255 -1000 100000000 00e40b5402",
+		},
+		"timelock": {
+			script: "0400e1f505b175",
+			expASM: "100000000 OP_NOP2 OP_DROP",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewFromHexString(test.script)
+			assert.NoError(t, err)
+
+			asm := s.ToNodeASM(true)
+			assert.Equal(t, test.expASM, asm)
+
+			s2, err := bscript.NewFromNodeASM(asm)
+			assert.NoError(t, err)
+			assert.Equal(t, test.script, s2.String())
+		})
+	}
+}
+
+func TestNewFromNodeASM(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		asm       string
+		expScript string
+		expErr    error
+	}{
+		"empty": {
+			asm:       "",
+			expScript: "",
+		},
+		"10 digits within range are a number": {
+			asm:       "2147483647",
+			expScript: "04ffffff7f",
+		},
+		"10 digits out of range are hex": {
+			asm:       "2147483648",
+			expScript: "052147483648",
+		},
+		"leading zeros are hex": {
+			asm:       "0010",
+			expScript: "020010",
+		},
+		"error": {
+			asm:    "OP_DUP [error]",
+			expErr: bscript.ErrInvalidOpCode,
+		},
+		"unknown opcode": {
+			asm:    "OP_UNKNOWN",
+			expErr: bscript.ErrInvalidOpCode,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewFromNodeASM(test.asm)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expScript, s.String())
+		})
+	}
+}
+
+func TestNewFromASM_SighashDecoration(t *testing.T) {
+	t.Parallel()
+
+	s, err := bscript.NewFromASM("30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[ALL]")
+	assert.NoError(t, err)
+	assert.Equal(t, "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf01", s.String())
+
+	_, err = bscript.NewFromASM("3044[ALL|UNKNOWN]")
+	assert.ErrorIs(t, err, bscript.ErrInvalidSigHash)
+
+	_, err = bscript.NewFromASM("zz[ALL|FORKID]")
+	assert.ErrorIs(t, err, bscript.ErrInvalidOpCode)
+}
+
+func TestScript_ToNodeASM(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		script        string
+		decodeSighash bool
+		expASM        string
+	}{
+		"p2pkh unlocking script": {
+			script:        "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+			decodeSighash: true,
+			expASM:        "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[ALL|FORKID] 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+		},
+		"p2pkh unlocking script not decoded": {
+			script: "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+			expASM: "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+		},
+		"signature without forkid is not decoded": {
+			script:        "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf01",
+			decodeSighash: true,
+			expASM:        "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf01",
+		},
+		"anyonecanpay signature": {
+			script:        "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cfc3",
+			decodeSighash: true,
+			expASM:        "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[SINGLE|FORKID|ANYONECANPAY]",
+		},
+		"numbers": {
+			script: "004f515f60010502ff0002e88301800481000000",
+			expASM: "0 -1 1 15 16 5 255 -1000 0 129",
+		},
+		"small pushes as numbers": {
+			script: "0102020304",
+			expASM: "2 1027",
+		},
+		"data": {
+			script: "006a0548656c6c6f",
+			expASM: "0 OP_RETURN 48656c6c6f",
+		},
+		"unknown opcodes": {
+			script: "babbff",
+			expASM: "OP_UNKNOWN OP_UNKNOWN OP_INVALIDOPCODE",
+		},
+		"truncated push": {
+			script: "76a914e2a6",
+			expASM: "OP_DUP OP_HASH160 [error]",
+		},
+		"empty script": {
+			script: "",
+			expASM: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewFromHexString(test.script)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expASM, s.ToNodeASM(test.decodeSighash))
+		})
+	}
+}
+
 func TestNewFromASM(t *testing.T) {
 	t.Parallel()
 
@@ -179,6 +358,57 @@ func TestScript_IsData(t *testing.T) {
 	assert.Equal(t, true, scriptPub.IsData())
 }
 
+func TestScript_DataOffset(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		script    string
+		expOffset int
+		expData   bool
+	}{
+		"op_return": {
+			script:  "6a0401020304",
+			expData: true,
+		},
+		"op_false op_return": {
+			script:  "006a0401020304",
+			expData: true,
+		},
+		"op_false op_return after a locking script": {
+			script:    "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac006a0401020304",
+			expOffset: 25,
+			expData:   true,
+		},
+		"data which does not decode": {
+			script:    "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac006a4c",
+			expOffset: 25,
+			expData:   true,
+		},
+		"op_return not at the start": {
+			script: "516a",
+		},
+		"op_false op_return pushed as data": {
+			script: "02006a75",
+		},
+		"p2pkh": {
+			script: "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac",
+		},
+		"empty": {
+			script: "",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewFromHexString(test.script)
+			assert.NoError(t, err)
+
+			offset, ok := s.DataOffset()
+			assert.Equal(t, test.expData, ok)
+			assert.Equal(t, test.expOffset, offset)
+		})
+	}
+}
+
 func TestScript_IsMultisigOut(t *testing.T) { // TODO: check this
 	t.Parallel()
 
@@ -564,4 +794,12 @@ func TestRunScriptExample3(t *testing.T) {
 	if asm != expected {
 		t.Errorf("\nExpected %q\ngot      %q", expected, asm)
 	}
-}
\ No newline at end of file
+}
+
+func TestNewP2PKHFromAddress_P2SH(t *testing.T) {
+	t.Parallel()
+
+	s, err := bscript.NewP2PKHFromAddress("3CMNFxN1oHBc4R1EpboAL5yzHGgE611Xou")
+	assert.ErrorIs(t, err, bscript.ErrNotP2PKH)
+	assert.Nil(t, s)
+}
@@ -0,0 +1,96 @@
+package bscript
+
+// AppendPushInt appends the minimal encoding of n to the script, using
+// OP_0, OP_1NEGATE and OP_1 to OP_16 where possible, otherwise pushing the
+// little endian sign-magnitude encoding used by the script interpreter.
+func (s *Script) AppendPushInt(n int64) error {
+	switch {
+	case n == 0:
+		*s = append(*s, OpZERO)
+		return nil
+	case n == -1:
+		*s = append(*s, Op1NEGATE)
+		return nil
+	case n >= 1 && n <= 16:
+		*s = append(*s, Op1+byte(n-1))
+		return nil
+	}
+
+	return s.AppendPushData(encodeScriptNum(n))
+}
+
+// encodeScriptNum encodes n in the little endian sign-magnitude
+// format used for numbers by the script interpreter.
+func encodeScriptNum(n int64) []byte {
+	if n == 0 {
+		return []byte{}
+	}
+
+	negative := n < 0
+	m := uint64(n)
+	if negative {
+		m = uint64(-n)
+	}
+
+	b := make([]byte, 0, 9)
+	for m > 0 {
+		b = append(b, byte(m&0xff))
+		m >>= 8
+	}
+
+	// If the most significant byte has the sign bit set, an extra
+	// byte is required to hold the sign.
+	if b[len(b)-1]&0x80 != 0 {
+		extra := byte(0x00)
+		if negative {
+			extra = 0x80
+		}
+		b = append(b, extra)
+	} else if negative {
+		b[len(b)-1] |= 0x80
+	}
+
+	return b
+}
+
+// decodeScriptNum decodes a little endian sign-magnitude number
+// as used by the script interpreter.
+func decodeScriptNum(b []byte) int64 {
+	if len(b) == 0 {
+		return 0
+	}
+
+	var n int64
+	for i, v := range b {
+		n |= int64(v) << uint8(8*i)
+	}
+
+	if b[len(b)-1]&0x80 != 0 {
+		n &= ^(int64(0x80) << uint8(8*(len(b)-1)))
+		return -n
+	}
+
+	return n
+}
+
+// readPushInt reads a number pushed at the start of b, returning the number
+// and the amount of bytes it occupied. False is returned if b does not start
+// with a number push of at most maxLen bytes.
+func readPushInt(b []byte, maxLen int) (int64, int, bool) {
+	if len(b) == 0 {
+		return 0, 0, false
+	}
+
+	switch op := b[0]; {
+	case op == OpZERO:
+		return 0, 1, true
+	case op == Op1NEGATE:
+		return -1, 1, true
+	case op >= Op1 && op <= Op16:
+		return int64(op-Op1) + 1, 1, true
+	case op >= OpDATA1 && int(op) <= maxLen && len(b) > int(op):
+		return decodeScriptNum(b[1 : 1+int(op)]), 1 + int(op), true
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,159 @@
+package bscript
+
+import (
+	"sync"
+)
+
+// TemplateParams are the named parameters extracted from, or used to build,
+// a script matching a ScriptTemplate. The names used are defined by each template.
+type TemplateParams map[string][]byte
+
+// ScriptTemplate interface to allow custom script types (tokens, ordinals, contracts etc.)
+// to be recognised and built. Templates registered with a TemplateRegistry are consulted
+// by `Script.ScriptType`, `Script.Addresses` and when estimating the size of unsigned inputs.
+//
+// If the template can also provide a `bt.Unlocker` for the scripts it matches, implement
+// the `bt.UnlockerTemplate` interface.
+type ScriptTemplate interface {
+	// Name of the template, which is the script type returned by `Script.ScriptType`
+	// for matching scripts, other than for the hash puzzle, R-puzzle and timelock
+	// templates, which are reported as nonstandard.
+	Name() string
+	// Match returns true if the script is of this template.
+	Match(s *Script) bool
+	// Extract the template parameters from a matching script.
+	Extract(s *Script) (TemplateParams, error)
+	// Build a new script of this template from the parameters provided.
+	Build(params TemplateParams) (*Script, error)
+	// EstimateUnlockLength returns the estimated length in bytes of an unlocking
+	// script for a matching locking script.
+	EstimateUnlockLength(s *Script) int
+}
+
+// AddressTemplate can be implemented by a ScriptTemplate whose scripts pay to
+// addresses, so that `Script.Addresses` can report them.
+type AddressTemplate interface {
+	ScriptTemplate
+	// Addresses returns the mainnet addresses a matching script pays to.
+	Addresses(s *Script) ([]string, error)
+}
+
+// TemplateRegistry is a thread safe collection of ScriptTemplates.
+//
+// Templates are matched most recently registered first, so a registered custom
+// template will take precedence over the standard templates.
+type TemplateRegistry struct {
+	mu        sync.RWMutex
+	templates []ScriptTemplate
+}
+
+// NewTemplateRegistry returns a TemplateRegistry containing the templates provided,
+// matched in the order they are provided.
+func NewTemplateRegistry(tt ...ScriptTemplate) *TemplateRegistry {
+	r := &TemplateRegistry{}
+	for i := len(tt) - 1; i >= 0; i-- {
+		_ = r.Register(tt[i])
+	}
+
+	return r
+}
+
+// Register a template with the registry. If a template with the same name already
+// exists, it is replaced.
+func (r *TemplateRegistry) Register(t ScriptTemplate) error {
+	if t == nil || t.Name() == "" {
+		return ErrInvalidTemplate
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates = append([]ScriptTemplate{t}, r.remove(t.Name())...)
+	return nil
+}
+
+// Unregister removes the template with the given name from the registry, returning
+// true if it was found.
+func (r *TemplateRegistry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := len(r.templates)
+	r.templates = r.remove(name)
+	return len(r.templates) != n
+}
+
+// Template returns the template registered under the given name.
+func (r *TemplateRegistry) Template(name string) (ScriptTemplate, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.templates {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// Match returns the first template which matches the script.
+func (r *TemplateRegistry) Match(s *Script) (ScriptTemplate, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, t := range r.templates {
+		if t.Match(s) {
+			return t, true
+		}
+	}
+
+	return nil, false
+}
+
+// Templates returns the registered templates in the order they are matched.
+func (r *TemplateRegistry) Templates() []ScriptTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]ScriptTemplate{}, r.templates...)
+}
+
+func (r *TemplateRegistry) remove(name string) []ScriptTemplate {
+	tt := make([]ScriptTemplate, 0, len(r.templates))
+	for _, t := range r.templates {
+		if t.Name() != name {
+			tt = append(tt, t)
+		}
+	}
+
+	return tt
+}
+
+// DefaultTemplates is the TemplateRegistry consulted by `Script.ScriptType`
+// and `Script.Addresses`. It contains the standard templates by default.
+var DefaultTemplates = NewTemplateRegistry(
+	P2PKHTemplate{},
+	P2PKTemplate{},
+	MultiSigTemplate{},
+	NullDataTemplate{},
+	HashPuzzleTemplate{},
+	RPuzzleTemplate{},
+	CLTVP2PKHTemplate{},
+	CSVP2PKHTemplate{},
+)
+
+// RegisterTemplate registers a template with the DefaultTemplates registry.
+func RegisterTemplate(t ScriptTemplate) error {
+	return DefaultTemplates.Register(t)
+}
+
+// UnregisterTemplate removes a template from the DefaultTemplates registry.
+func UnregisterTemplate(name string) bool {
+	return DefaultTemplates.Unregister(name)
+}
+
+// MatchTemplate returns the template from the DefaultTemplates registry
+// which matches the script.
+func MatchTemplate(s *Script) (ScriptTemplate, bool) {
+	return DefaultTemplates.Match(s)
+}
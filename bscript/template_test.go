@@ -0,0 +1,216 @@
+package bscript_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenTemplate is a custom template for scripts of the form `<"tkn"> OP_DROP <p2pkh>`.
+type tokenTemplate struct{}
+
+func (tokenTemplate) Name() string { return "token" }
+
+func (tokenTemplate) Match(s *bscript.Script) bool {
+	return len(*s) == 30 && bytes.HasPrefix(*s, []byte{0x03, 't', 'k', 'n', bscript.OpDROP}) &&
+		bscript.NewFromBytes((*s)[5:]).IsP2PKH()
+}
+
+func (tokenTemplate) Extract(s *bscript.Script) (bscript.TemplateParams, error) {
+	return bscript.TemplateParams{bscript.ParamPubKeyHash: (*s)[8:28]}, nil
+}
+
+func (tokenTemplate) Build(params bscript.TemplateParams) (*bscript.Script, error) {
+	p2pkh, err := bscript.NewP2PKHFromPubKeyHash(params[bscript.ParamPubKeyHash])
+	if err != nil {
+		return nil, err
+	}
+	s := &bscript.Script{}
+	_ = s.AppendPushDataString("tkn")
+	_ = s.AppendOpcodes(bscript.OpDROP)
+	*s = append(*s, *p2pkh...)
+	return s, nil
+}
+
+func (tokenTemplate) EstimateUnlockLength(*bscript.Script) int { return 107 }
+
+func TestTemplateRegistry(t *testing.T) {
+	t.Parallel()
+
+	pkh, err := hex.DecodeString("c28f832c3d539933e0c719297340b34eee0f4c34")
+	assert.NoError(t, err)
+
+	token, err := tokenTemplate{}.Build(bscript.TemplateParams{bscript.ParamPubKeyHash: pkh})
+	assert.NoError(t, err)
+
+	t.Run("custom template is matched once registered", func(t *testing.T) {
+		r := bscript.NewTemplateRegistry(bscript.P2PKHTemplate{})
+		_, ok := r.Match(token)
+		assert.False(t, ok)
+
+		assert.NoError(t, r.Register(tokenTemplate{}))
+		tmpl, ok := r.Match(token)
+		assert.True(t, ok)
+		assert.Equal(t, "token", tmpl.Name())
+
+		assert.True(t, r.Unregister("token"))
+		assert.False(t, r.Unregister("token"))
+		_, ok = r.Match(token)
+		assert.False(t, ok)
+	})
+
+	t.Run("later registration takes precedence", func(t *testing.T) {
+		r := bscript.NewTemplateRegistry(bscript.P2PKHTemplate{}, bscript.NullDataTemplate{})
+		assert.NoError(t, r.Register(tokenTemplate{}))
+
+		names := make([]string, 0)
+		for _, tmpl := range r.Templates() {
+			names = append(names, tmpl.Name())
+		}
+		assert.Equal(t, []string{"token", bscript.ScriptTypePubKeyHash, bscript.ScriptTypeNullData}, names)
+	})
+
+	t.Run("registering an existing name replaces it", func(t *testing.T) {
+		r := bscript.NewTemplateRegistry(bscript.P2PKHTemplate{}, bscript.NullDataTemplate{})
+		assert.NoError(t, r.Register(bscript.NullDataTemplate{}))
+		assert.Len(t, r.Templates(), 2)
+
+		tmpl, ok := r.Template(bscript.ScriptTypeNullData)
+		assert.True(t, ok)
+		assert.Equal(t, bscript.ScriptTypeNullData, tmpl.Name())
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		assert.ErrorIs(t, bscript.NewTemplateRegistry().Register(nil), bscript.ErrInvalidTemplate)
+	})
+
+	t.Run("default registry drives script type", func(t *testing.T) {
+		assert.Equal(t, bscript.ScriptTypeNonStandard, token.ScriptType())
+
+		assert.NoError(t, bscript.RegisterTemplate(tokenTemplate{}))
+		defer bscript.UnregisterTemplate("token")
+
+		assert.Equal(t, "token", token.ScriptType())
+	})
+}
+
+func TestStandardTemplates(t *testing.T) {
+	t.Parallel()
+
+	pkh, err := hex.DecodeString("c28f832c3d539933e0c719297340b34eee0f4c34")
+	assert.NoError(t, err)
+	pk, err := hex.DecodeString("023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d6")
+	assert.NoError(t, err)
+	pk2, err := hex.DecodeString("02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		template      bscript.ScriptTemplate
+		params        bscript.TemplateParams
+		expType       string
+		expScriptType string
+		expUnlockLen  int
+		expAddresses  []string
+	}{
+		"p2pkh": {
+			template:      bscript.P2PKHTemplate{},
+			params:        bscript.TemplateParams{bscript.ParamPubKeyHash: pkh},
+			expType:       bscript.ScriptTypePubKeyHash,
+			expScriptType: bscript.ScriptTypePubKeyHash,
+			expUnlockLen:  107,
+			expAddresses:  []string{"1Jjk1dbo4Yimf1w2NzRqQhGt5CUEST9WZK"},
+		},
+		"p2pk": {
+			template:      bscript.P2PKTemplate{},
+			params:        bscript.TemplateParams{bscript.ParamPubKey: pk},
+			expType:       bscript.ScriptTypePubKey,
+			expScriptType: bscript.ScriptTypePubKey,
+			expUnlockLen:  73,
+			expAddresses:  []string{},
+		},
+		"multisig": {
+			template: bscript.MultiSigTemplate{},
+			params: bscript.TemplateParams{
+				bscript.ParamM:                 {2},
+				bscript.MultiSigPubKeyParam(0): pk,
+				bscript.MultiSigPubKeyParam(1): pk2,
+			},
+			expType:       bscript.ScriptTypeMultiSig,
+			expScriptType: bscript.ScriptTypeMultiSig,
+			expUnlockLen:  147,
+			expAddresses:  []string{},
+		},
+		"nulldata": {
+			template:      bscript.NullDataTemplate{},
+			params:        bscript.TemplateParams{bscript.ParamData: []byte{0x02, 'h', 'i'}},
+			expType:       bscript.ScriptTypeNullData,
+			expScriptType: bscript.ScriptTypeNullData,
+			expAddresses:  []string{},
+		},
+		"hash puzzle": {
+			template: bscript.HashPuzzleTemplate{},
+			params: bscript.TemplateParams{
+				bscript.ParamSecretHash: pkh,
+				bscript.ParamPubKeyHash: pkh,
+			},
+			expType:       bscript.ScriptTypeHashPuzzle,
+			expScriptType: bscript.ScriptTypeNonStandard,
+			expUnlockLen:  140,
+			expAddresses:  []string{"1Jjk1dbo4Yimf1w2NzRqQhGt5CUEST9WZK"},
+		},
+		"r puzzle": {
+			template:      bscript.RPuzzleTemplate{},
+			params:        bscript.TemplateParams{bscript.ParamR: pk[1:]},
+			expType:       bscript.ScriptTypeRPuzzle,
+			expScriptType: bscript.ScriptTypeNonStandard,
+			expUnlockLen:  107,
+			expAddresses:  []string{},
+		},
+		"cltv p2pkh": {
+			template: bscript.CLTVP2PKHTemplate{},
+			params: bscript.TemplateParams{
+				bscript.ParamLockTime:   {0xb0, 0x71, 0x0b, 0x00},
+				bscript.ParamPubKeyHash: pkh,
+			},
+			expType:       bscript.ScriptTypeCLTVP2PKH,
+			expScriptType: bscript.ScriptTypeNonStandard,
+			expUnlockLen:  107,
+			expAddresses:  []string{"1Jjk1dbo4Yimf1w2NzRqQhGt5CUEST9WZK"},
+		},
+		"csv p2pkh": {
+			template: bscript.CSVP2PKHTemplate{},
+			params: bscript.TemplateParams{
+				bscript.ParamSequence:   {0x90, 0x00, 0x00, 0x00},
+				bscript.ParamPubKeyHash: pkh,
+			},
+			expType:       bscript.ScriptTypeCSVP2PKH,
+			expScriptType: bscript.ScriptTypeNonStandard,
+			expUnlockLen:  107,
+			expAddresses:  []string{"1Jjk1dbo4Yimf1w2NzRqQhGt5CUEST9WZK"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := test.template.Build(test.params)
+			assert.NoError(t, err)
+			assert.True(t, test.template.Match(s))
+			tmpl, ok := bscript.MatchTemplate(s)
+			assert.True(t, ok)
+			assert.Equal(t, test.expType, tmpl.Name())
+			assert.Equal(t, test.expScriptType, s.ScriptType())
+			assert.Equal(t, test.expUnlockLen, test.template.EstimateUnlockLength(s))
+
+			params, err := test.template.Extract(s)
+			assert.NoError(t, err)
+			assert.Equal(t, test.params, params)
+
+			addresses, err := s.Addresses()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expAddresses, addresses)
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package bscript
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// TemplateParams keys used by the standard templates.
+const (
+	ParamPubKeyHash = "pubKeyHash"
+	ParamPubKey     = "pubKey"
+	ParamM          = "m"
+	ParamData       = "data"
+	ParamSecretHash = "secretHash"
+	ParamR          = "r"
+	ParamLockTime   = "lockTime"
+	ParamSequence   = "sequence"
+)
+
+// Estimated unlocking script lengths of the standard templates.
+const (
+	// sigLen a push of a typical 71 byte DER signature plus SIGHASH flag.
+	sigLen = 1 + 71 + 1
+	// p2pkhUnlockLen a signature push plus a compressed public key push.
+	p2pkhUnlockLen = sigLen + 1 + 33
+	// secretLen assumes a 32 byte secret preimage push.
+	secretLen = 1 + 32
+)
+
+// Names of the additional standard templates. `Script.ScriptType` reports
+// their scripts as nonstandard, so match them with MatchTemplate.
+const (
+	ScriptTypeHashPuzzle = "hashpuzzle"
+	ScriptTypeRPuzzle    = "rpuzzle"
+	ScriptTypeCLTVP2PKH  = "cltvpubkeyhash"
+	ScriptTypeCSVP2PKH   = "csvpubkeyhash"
+)
+
+// MultiSigPubKeyParam returns the TemplateParams key of the i'th
+// public key of a multisig script.
+func MultiSigPubKeyParam(i int) string {
+	return fmt.Sprintf("%s%d", ParamPubKey, i)
+}
+
+// P2PKHTemplate is the ScriptTemplate of a P2PKH script, using
+// the ParamPubKeyHash parameter.
+type P2PKHTemplate struct{}
+
+// Name returns ScriptTypePubKeyHash.
+func (P2PKHTemplate) Name() string { return ScriptTypePubKeyHash }
+
+// Match returns true if the script is P2PKH.
+func (P2PKHTemplate) Match(s *Script) bool { return s.IsP2PKH() }
+
+// Extract the public key hash.
+func (t P2PKHTemplate) Extract(s *Script) (TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrNotP2PKH
+	}
+
+	return TemplateParams{ParamPubKeyHash: (*s)[3:23]}, nil
+}
+
+// Build a P2PKH script from the public key hash.
+func (P2PKHTemplate) Build(params TemplateParams) (*Script, error) {
+	pkh := params[ParamPubKeyHash]
+	if len(pkh) != 20 {
+		return nil, ErrInvalidPKHLen
+	}
+
+	return NewP2PKHFromPubKeyHash(pkh)
+}
+
+// EstimateUnlockLength returns the length of a signature and compressed public key push.
+func (P2PKHTemplate) EstimateUnlockLength(*Script) int { return p2pkhUnlockLen }
+
+// Addresses returns the address paid to.
+func (t P2PKHTemplate) Addresses(s *Script) ([]string, error) {
+	params, err := t.Extract(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeyHashAddresses(params[ParamPubKeyHash])
+}
+
+// P2PKTemplate is the ScriptTemplate of a P2PK script, using
+// the ParamPubKey parameter.
+type P2PKTemplate struct{}
+
+// Name returns ScriptTypePubKey.
+func (P2PKTemplate) Name() string { return ScriptTypePubKey }
+
+// Match returns true if the script is P2PK.
+func (P2PKTemplate) Match(s *Script) bool { return s.IsP2PK() }
+
+// Extract the public key.
+func (t P2PKTemplate) Extract(s *Script) (TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	parts, err := DecodeParts(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	return TemplateParams{ParamPubKey: parts[0]}, nil
+}
+
+// Build a P2PK script from the public key.
+func (t P2PKTemplate) Build(params TemplateParams) (*Script, error) {
+	s := &Script{}
+	if err := s.AppendPushData(params[ParamPubKey]); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(OpCHECKSIG)
+
+	if !t.Match(s) {
+		return nil, ErrInvalidPKLen
+	}
+
+	return s, nil
+}
+
+// EstimateUnlockLength returns the length of a signature push.
+func (P2PKTemplate) EstimateUnlockLength(*Script) int { return sigLen }
+
+// MultiSigTemplate is the ScriptTemplate of a bare multisig script, using the ParamM
+// parameter for the required signature count and MultiSigPubKeyParam(i) parameters
+// for the public keys.
+type MultiSigTemplate struct{}
+
+// Name returns ScriptTypeMultiSig.
+func (MultiSigTemplate) Name() string { return ScriptTypeMultiSig }
+
+// Match returns true if the script is a multisig output.
+func (MultiSigTemplate) Match(s *Script) bool { return s.IsMultiSigOut() }
+
+// Extract the required signature count and public keys.
+func (t MultiSigTemplate) Extract(s *Script) (TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	parts, err := DecodeParts(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	params := TemplateParams{ParamM: []byte{smallIntValue(parts[0][0])}}
+	for i, pk := range parts[1 : len(parts)-2] {
+		params[MultiSigPubKeyParam(i)] = pk
+	}
+
+	return params, nil
+}
+
+// Build a multisig script from the required signature count and public keys.
+func (MultiSigTemplate) Build(params TemplateParams) (*Script, error) {
+	m := params[ParamM]
+	if len(m) != 1 || m[0] < 1 || m[0] > 16 {
+		return nil, ErrInvalidMultiSig
+	}
+
+	pubKeys := make([][]byte, 0)
+	for i := 0; ; i++ {
+		pk, ok := params[MultiSigPubKeyParam(i)]
+		if !ok {
+			break
+		}
+		pubKeys = append(pubKeys, pk)
+	}
+	if len(pubKeys) < int(m[0]) || len(pubKeys) > 16 {
+		return nil, ErrInvalidMultiSig
+	}
+
+	s := &Script{}
+	_ = s.AppendPushInt(int64(m[0]))
+	if err := s.AppendPushDataArray(pubKeys); err != nil {
+		return nil, err
+	}
+	_ = s.AppendPushInt(int64(len(pubKeys)))
+	_ = s.AppendOpcodes(OpCHECKMULTISIG)
+
+	return s, nil
+}
+
+// EstimateUnlockLength returns the length of an OP_0 followed by m signature pushes.
+func (MultiSigTemplate) EstimateUnlockLength(s *Script) int {
+	parts, err := DecodeParts(*s)
+	if err != nil || len(parts) == 0 {
+		return 0
+	}
+
+	return 1 + int(smallIntValue(parts[0][0]))*sigLen
+}
+
+// NullDataTemplate is the ScriptTemplate of an OP_FALSE OP_RETURN data script, using
+// the ParamData parameter which holds the script bytes following the OP_RETURN.
+type NullDataTemplate struct{}
+
+// Name returns ScriptTypeNullData.
+func (NullDataTemplate) Name() string { return ScriptTypeNullData }
+
+// Match returns true if the script is a data output.
+func (NullDataTemplate) Match(s *Script) bool { return s.IsData() }
+
+// Extract the data following the OP_RETURN.
+func (t NullDataTemplate) Extract(s *Script) (TemplateParams, error) {
+	if !t.Match(s) {
+		return nil, ErrTemplateMismatch
+	}
+
+	b := []byte(*s)
+	if b[0] == OpFALSE {
+		b = b[1:]
+	}
+
+	return TemplateParams{ParamData: b[1:]}, nil
+}
+
+// Build an OP_FALSE OP_RETURN script followed by the data, which is expected
+// to already be encoded as script.
+func (NullDataTemplate) Build(params TemplateParams) (*Script, error) {
+	s := &Script{OpFALSE, OpRETURN}
+	*s = append(*s, params[ParamData]...)
+
+	return s, nil
+}
+
+// EstimateUnlockLength returns 0, as data outputs are unspendable.
+func (NullDataTemplate) EstimateUnlockLength(*Script) int { return 0 }
+
+// HashPuzzleTemplate is the ScriptTemplate of a hash puzzle + P2PKH script, using
+// the ParamSecretHash and ParamPubKeyHash parameters.
+type HashPuzzleTemplate struct{}
+
+// Name returns ScriptTypeHashPuzzle.
+func (HashPuzzleTemplate) Name() string { return ScriptTypeHashPuzzle }
+
+// Match returns true if the script is a hash puzzle.
+func (HashPuzzleTemplate) Match(s *Script) bool { return s.IsHashPuzzle() }
+
+// Extract the secret hash and public key hash.
+func (HashPuzzleTemplate) Extract(s *Script) (TemplateParams, error) {
+	secretHash, pubKeyHash, err := s.HashPuzzleParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return TemplateParams{ParamSecretHash: secretHash, ParamPubKeyHash: pubKeyHash}, nil
+}
+
+// Build a hash puzzle script from the secret hash and public key hash.
+func (HashPuzzleTemplate) Build(params TemplateParams) (*Script, error) {
+	if len(params[ParamSecretHash]) != 20 || len(params[ParamPubKeyHash]) != 20 {
+		return nil, ErrInvalidPKHLen
+	}
+
+	return NewHashPuzzle(params[ParamSecretHash], params[ParamPubKeyHash])
+}
+
+// EstimateUnlockLength returns the length of a P2PKH unlock followed by a 32 byte secret.
+func (HashPuzzleTemplate) EstimateUnlockLength(*Script) int { return p2pkhUnlockLen + secretLen }
+
+// Addresses returns the address paid to.
+func (t HashPuzzleTemplate) Addresses(s *Script) ([]string, error) {
+	_, pkh, err := s.HashPuzzleParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeyHashAddresses(pkh)
+}
+
+// RPuzzleTemplate is the ScriptTemplate of an R-puzzle script, using the ParamR parameter.
+type RPuzzleTemplate struct{}
+
+// Name returns ScriptTypeRPuzzle.
+func (RPuzzleTemplate) Name() string { return ScriptTypeRPuzzle }
+
+// Match returns true if the script is an R-puzzle.
+func (RPuzzleTemplate) Match(s *Script) bool { return s.IsRPuzzle() }
+
+// Extract the R value.
+func (RPuzzleTemplate) Extract(s *Script) (TemplateParams, error) {
+	r, err := s.RPuzzleValue()
+	if err != nil {
+		return nil, err
+	}
+
+	return TemplateParams{ParamR: r}, nil
+}
+
+// Build an R-puzzle script from the R value.
+func (RPuzzleTemplate) Build(params TemplateParams) (*Script, error) {
+	return NewRPuzzle(params[ParamR])
+}
+
+// EstimateUnlockLength returns the length of a signature and compressed public key push.
+func (RPuzzleTemplate) EstimateUnlockLength(*Script) int { return p2pkhUnlockLen }
+
+// CLTVP2PKHTemplate is the ScriptTemplate of a P2PKH script guarded by OP_CHECKLOCKTIMEVERIFY,
+// using the ParamLockTime parameter, as 4 little endian bytes, and the ParamPubKeyHash parameter.
+type CLTVP2PKHTemplate struct{}
+
+// Name returns ScriptTypeCLTVP2PKH.
+func (CLTVP2PKHTemplate) Name() string { return ScriptTypeCLTVP2PKH }
+
+// Match returns true if the script is a CLTV P2PKH.
+func (CLTVP2PKHTemplate) Match(s *Script) bool { return s.IsCLTVP2PKH() }
+
+// Extract the lock time and public key hash.
+func (CLTVP2PKHTemplate) Extract(s *Script) (TemplateParams, error) {
+	lockTime, pkh, err := s.CLTVP2PKHParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return timelockParams(ParamLockTime, lockTime, pkh), nil
+}
+
+// Build a CLTV P2PKH script from the lock time and public key hash.
+func (CLTVP2PKHTemplate) Build(params TemplateParams) (*Script, error) {
+	lockTime := params[ParamLockTime]
+	if len(lockTime) != 4 {
+		return nil, ErrInvalidLockTime
+	}
+
+	return NewCLTVP2PKH(binary.LittleEndian.Uint32(lockTime), params[ParamPubKeyHash])
+}
+
+// EstimateUnlockLength returns the length of a signature and compressed public key push.
+func (CLTVP2PKHTemplate) EstimateUnlockLength(*Script) int { return p2pkhUnlockLen }
+
+// Addresses returns the address paid to.
+func (CLTVP2PKHTemplate) Addresses(s *Script) ([]string, error) {
+	_, pkh, err := s.CLTVP2PKHParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeyHashAddresses(pkh)
+}
+
+// CSVP2PKHTemplate is the ScriptTemplate of a P2PKH script guarded by OP_CHECKSEQUENCEVERIFY,
+// using the ParamSequence parameter, as 4 little endian bytes, and the ParamPubKeyHash parameter.
+type CSVP2PKHTemplate struct{}
+
+// Name returns ScriptTypeCSVP2PKH.
+func (CSVP2PKHTemplate) Name() string { return ScriptTypeCSVP2PKH }
+
+// Match returns true if the script is a CSV P2PKH.
+func (CSVP2PKHTemplate) Match(s *Script) bool { return s.IsCSVP2PKH() }
+
+// Extract the relative lock time sequence and public key hash.
+func (CSVP2PKHTemplate) Extract(s *Script) (TemplateParams, error) {
+	sequence, pkh, err := s.CSVP2PKHParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return timelockParams(ParamSequence, sequence, pkh), nil
+}
+
+// Build a CSV P2PKH script from the relative lock time sequence and public key hash.
+func (CSVP2PKHTemplate) Build(params TemplateParams) (*Script, error) {
+	sequence := params[ParamSequence]
+	if len(sequence) != 4 {
+		return nil, ErrInvalidLockTime
+	}
+
+	return NewCSVP2PKH(binary.LittleEndian.Uint32(sequence), params[ParamPubKeyHash])
+}
+
+// EstimateUnlockLength returns the length of a signature and compressed public key push.
+func (CSVP2PKHTemplate) EstimateUnlockLength(*Script) int { return p2pkhUnlockLen }
+
+// Addresses returns the address paid to.
+func (CSVP2PKHTemplate) Addresses(s *Script) ([]string, error) {
+	_, pkh, err := s.CSVP2PKHParts()
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeyHashAddresses(pkh)
+}
+
+func timelockParams(key string, lock uint32, pkh []byte) TemplateParams {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, lock)
+
+	return TemplateParams{key: b, ParamPubKeyHash: pkh}
+}
+
+func pubKeyHashAddresses(pkh []byte) ([]string, error) {
+	a, err := NewAddressFromPublicKeyHash(pkh, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{a.AddressString}, nil
+}
+
+func smallIntValue(op byte) byte {
+	if op == OpZERO {
+		return 0
+	}
+
+	return op - Op1 + 1
+}
@@ -0,0 +1,99 @@
+package bscript
+
+import "bytes"
+
+// maxLockTimeNumLen is the maximum length of the number pushed for a
+// CLTV/CSV check, matching the 5 bytes accepted by the interpreter.
+const maxLockTimeNumLen = 5
+
+// NewCLTVP2PKH takes an absolute lock time and a public key hash and creates a
+// P2PKH locking script which cannot be spent until the spending transaction's
+// nLockTime has reached lockTime:
+//
+//	<lockTime> OP_CHECKLOCKTIMEVERIFY OP_DROP OP_DUP OP_HASH160 <pubKeyHash> OP_EQUALVERIFY OP_CHECKSIG
+//
+// As with nLockTime, values below 500000000 are block heights and values at or above
+// are unix timestamps.
+func NewCLTVP2PKH(lockTime uint32, pubKeyHash []byte) (*Script, error) {
+	return newTimelockP2PKH(OpCHECKLOCKTIMEVERIFY, lockTime, pubKeyHash)
+}
+
+// NewCSVP2PKH takes a relative lock time, encoded as a BIP68 sequence number, and a public
+// key hash and creates a P2PKH locking script which cannot be spent until the spending
+// input's nSequence satisfies the relative lock time:
+//
+//	<sequence> OP_CHECKSEQUENCEVERIFY OP_DROP OP_DUP OP_HASH160 <pubKeyHash> OP_EQUALVERIFY OP_CHECKSIG
+func NewCSVP2PKH(sequence uint32, pubKeyHash []byte) (*Script, error) {
+	return newTimelockP2PKH(OpCHECKSEQUENCEVERIFY, sequence, pubKeyHash)
+}
+
+func newTimelockP2PKH(op byte, lock uint32, pubKeyHash []byte) (*Script, error) {
+	p2pkh, err := NewP2PKHFromPubKeyHash(pubKeyHash)
+	if err != nil {
+		return nil, err
+	}
+	if !p2pkh.IsP2PKH() {
+		return nil, ErrInvalidPKHLen
+	}
+
+	s := &Script{}
+	if err = s.AppendPushInt(int64(lock)); err != nil {
+		return nil, err
+	}
+	_ = s.AppendOpcodes(op, OpDROP)
+	*s = append(*s, *p2pkh...)
+
+	return s, nil
+}
+
+// IsCLTVP2PKH returns true if this is a P2PKH output script
+// prefixed with an OP_CHECKLOCKTIMEVERIFY check.
+func (s *Script) IsCLTVP2PKH() bool {
+	_, _, err := s.timelockP2PKHParts(OpCHECKLOCKTIMEVERIFY)
+	return err == nil
+}
+
+// IsCSVP2PKH returns true if this is a P2PKH output script
+// prefixed with an OP_CHECKSEQUENCEVERIFY check.
+func (s *Script) IsCSVP2PKH() bool {
+	_, _, err := s.timelockP2PKHParts(OpCHECKSEQUENCEVERIFY)
+	return err == nil
+}
+
+// CLTVP2PKHParts returns the lock time and public key hash
+// committed to by a CLTV P2PKH script.
+func (s *Script) CLTVP2PKHParts() (lockTime uint32, pubKeyHash []byte, err error) {
+	return s.timelockP2PKHParts(OpCHECKLOCKTIMEVERIFY)
+}
+
+// CSVP2PKHParts returns the relative lock time sequence and public
+// key hash committed to by a CSV P2PKH script.
+func (s *Script) CSVP2PKHParts() (sequence uint32, pubKeyHash []byte, err error) {
+	return s.timelockP2PKHParts(OpCHECKSEQUENCEVERIFY)
+}
+
+// timelockP2PKHParts returns the lock value and public key hash of a P2PKH
+// script prefixed with a lock check using the provided opcode.
+func (s *Script) timelockP2PKHParts(op byte) (uint32, []byte, error) {
+	if s == nil || len(*s) == 0 {
+		return 0, nil, ErrEmptyScript
+	}
+
+	b := []byte(*s)
+	n, l, ok := readPushInt(b, maxLockTimeNumLen)
+	if !ok || n < 0 || n > 0xffffffff {
+		return 0, nil, ErrNotTimelock
+	}
+
+	rest := b[l:]
+	if len(rest) != 27 || !bytes.Equal(rest[:2], []byte{op, OpDROP}) {
+		return 0, nil, ErrNotTimelock
+	}
+
+	p2pkh := Script(rest[2:])
+	if !p2pkh.IsP2PKH() {
+		return 0, nil, ErrNotTimelock
+	}
+
+	return uint32(n), p2pkh[3:23], nil
+}
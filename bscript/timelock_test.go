@@ -0,0 +1,106 @@
+package bscript_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCLTVP2PKH(t *testing.T) {
+	t.Parallel()
+
+	pkh, err := hex.DecodeString("c28f832c3d539933e0c719297340b34eee0f4c34")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		lockTime uint32
+		expASM   string
+	}{
+		"small int lock time": {
+			lockTime: 16,
+			expASM:   "OP_16 OP_NOP2 OP_DROP OP_DUP OP_HASH160 c28f832c3d539933e0c719297340b34eee0f4c34 OP_EQUALVERIFY OP_CHECKSIG",
+		},
+		"block height": {
+			lockTime: 750000,
+			expASM:   "b0710b OP_NOP2 OP_DROP OP_DUP OP_HASH160 c28f832c3d539933e0c719297340b34eee0f4c34 OP_EQUALVERIFY OP_CHECKSIG",
+		},
+		"timestamp with sign byte": {
+			lockTime: 0xffffffff,
+			expASM:   "ffffffff00 OP_NOP2 OP_DROP OP_DUP OP_HASH160 c28f832c3d539933e0c719297340b34eee0f4c34 OP_EQUALVERIFY OP_CHECKSIG",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewCLTVP2PKH(test.lockTime, pkh)
+			assert.NoError(t, err)
+
+			asm, err := s.ToASM()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expASM, asm)
+
+			assert.True(t, s.IsCLTVP2PKH())
+			assert.False(t, s.IsCSVP2PKH())
+			assert.False(t, s.IsP2PKH())
+
+			lockTime, h, err := s.CLTVP2PKHParts()
+			assert.NoError(t, err)
+			assert.Equal(t, test.lockTime, lockTime)
+			assert.Equal(t, pkh, h)
+		})
+	}
+
+	t.Run("invalid public key hash", func(t *testing.T) {
+		_, err := bscript.NewCLTVP2PKH(100, []byte{0x01})
+		assert.ErrorIs(t, err, bscript.ErrInvalidPKHLen)
+	})
+}
+
+func TestNewCSVP2PKH(t *testing.T) {
+	t.Parallel()
+
+	pkh, err := hex.DecodeString("c28f832c3d539933e0c719297340b34eee0f4c34")
+	assert.NoError(t, err)
+
+	s, err := bscript.NewCSVP2PKH(144, pkh)
+	assert.NoError(t, err)
+	assert.Equal(t, "029000b27576a914c28f832c3d539933e0c719297340b34eee0f4c3488ac", s.String())
+	assert.True(t, s.IsCSVP2PKH())
+	assert.False(t, s.IsCLTVP2PKH())
+
+	sequence, h, err := s.CSVP2PKHParts()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(144), sequence)
+	assert.Equal(t, pkh, h)
+
+	p2pkh, err := bscript.NewP2PKHFromPubKeyHash(pkh)
+	assert.NoError(t, err)
+	_, _, err = p2pkh.CSVP2PKHParts()
+	assert.ErrorIs(t, err, bscript.ErrNotTimelock)
+}
+
+func TestScript_AppendPushInt(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int64]string{
+		0:          "00",
+		-1:         "4f",
+		1:          "51",
+		16:         "60",
+		17:         "0111",
+		127:        "017f",
+		128:        "028000",
+		-128:       "028080",
+		255:        "02ff00",
+		256:        "020001",
+		0x7fffffff: "04ffffff7f",
+	}
+
+	for n, exp := range tests {
+		s := &bscript.Script{}
+		assert.NoError(t, s.AppendPushInt(n))
+		assert.Equal(t, exp, s.String(), "push %d", n)
+	}
+}
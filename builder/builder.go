@@ -0,0 +1,210 @@
+// Package builder builds a signed tx from a declarative Spec in one call.
+//
+// Rather than building a tx step by step with the methods of `bt.Tx`, a Spec
+// declares the recipients, data, utxos, change and fee of the tx, which is then
+// validated as a whole before any of it is built:
+//
+//	tx, err := builder.Build(ctx, &builder.Spec{
+//	    Recipients: []builder.Recipient{{Address: "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", Satoshis: 1000}},
+//	    Sources:    []bt.UTXOGetterFunc{account.UTXOGetter()},
+//	    Change:     &builder.ChangePolicy{Address: changeAddress},
+//	    Unlocker:   &unlocker.Getter{PrivateKey: key},
+//	})
+//
+// DryRun builds the tx without signing it, returning a Plan of what Build would do.
+package builder
+
+import (
+	"context"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/pkg/errors"
+)
+
+// Plan is the tx a Spec would build, before it is signed.
+type Plan struct {
+	// Tx is the unsigned tx.
+	Tx *bt.Tx
+	// Inputs are the inputs of the tx.
+	Inputs []*bt.Input
+	// Outputs are the outputs of the tx, including the change.
+	Outputs []*bt.Output
+	// Change are the change outputs of the tx.
+	Change []*bt.Output
+	// Fee is the fee the tx pays.
+	Fee uint64
+	// Size is the estimated size of the tx once signed.
+	Size *bt.TxSize
+}
+
+// Build builds the tx declared by the spec, and signs it. If the spec is invalid a
+// SpecError is returned with each of the problems found.
+//
+// The fee paid by a tx with a single change output is settled to exactly the fee
+// required for the signed tx, as with `Tx.Finalise`, funding the tx further from the
+// utxo sources should the signed tx not pay enough. Otherwise the fee is estimated,
+// as with `Tx.Fund`.
+func Build(ctx context.Context, spec *Spec) (*bt.Tx, error) {
+	pp := spec.problems()
+	if spec.Unlocker == nil {
+		pp = append(pp, bt.ErrNoUnlocker)
+	}
+	if len(pp) > 0 {
+		return nil, SpecError{Problems: pp}
+	}
+
+	tx, err := assemble(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.Change == nil || spec.Change.single() {
+		var change *bscript.Script
+		if spec.Change != nil {
+			ss, err := spec.Change.scripts()
+			if err != nil {
+				return nil, err
+			}
+			change = ss[0]
+		}
+		if err = tx.Finalise(ctx, fees(spec), sources(spec.Sources), change, spec.Unlocker); err != nil {
+			return nil, err
+		}
+
+		return tx, nil
+	}
+
+	if _, err = addChange(tx, spec); err != nil {
+		return nil, err
+	}
+	if err = tx.FillAllInputs(ctx, spec.Unlocker); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// DryRun builds the tx declared by the spec as Build would, without signing it,
+// and returns the Plan of the tx. If the spec is invalid a SpecError is returned
+// with each of the problems found.
+//
+// As the tx is funded as Build would, the utxo sources of the spec are called, so
+// a source which reserves the utxos it returns, such as `bt.UTXOStoreGetter`, will
+// have reserved them. The fee of the plan is estimated.
+func DryRun(ctx context.Context, spec *Spec) (*Plan, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := assemble(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	change, err := addChange(tx, spec)
+	if err != nil {
+		return nil, err
+	}
+	size, err := tx.EstimateSizeWithTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		Tx:      tx,
+		Inputs:  tx.Inputs,
+		Outputs: tx.Outputs,
+		Change:  change,
+		Fee:     tx.TotalInputSatoshis() - tx.TotalOutputSatoshis(),
+		Size:    size,
+	}, nil
+}
+
+// assemble returns the funded tx of the spec, without change.
+func assemble(ctx context.Context, spec *Spec) (*bt.Tx, error) {
+	tx := bt.NewTx()
+	tx.LockTime = spec.LockTime
+
+	for _, r := range spec.Recipients {
+		if r.Script != nil {
+			tx.AddOutput(&bt.Output{Satoshis: r.Satoshis, LockingScript: r.Script})
+			continue
+		}
+		if err := tx.PayToAddress(r.Address, r.Satoshis); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range spec.Data {
+		if err := tx.AddOpReturnPartsOutput(d); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.FromUTXOs(spec.UTXOs...); err != nil {
+		return nil, err
+	}
+	if err := tx.Fund(ctx, fees(spec), sources(spec.Sources)); err != nil {
+		return nil, err
+	}
+
+	if spec.LockTime != 0 {
+		for _, in := range tx.Inputs {
+			in.SequenceNumber = bt.MaxTxInSequenceNum - 1
+		}
+	}
+
+	return tx, nil
+}
+
+// addChange adds the change of the spec to the tx, returning the change outputs.
+func addChange(tx *bt.Tx, spec *Spec) ([]*bt.Output, error) {
+	if spec.Change == nil {
+		return nil, nil
+	}
+	ss, err := spec.Change.scripts()
+	if err != nil {
+		return nil, err
+	}
+
+	n := tx.OutputCount()
+	switch {
+	case len(spec.Change.Denominations) > 0:
+		err = tx.ChangeDenominations(ss, spec.Change.Denominations, fees(spec))
+	case spec.Change.Outputs > 1:
+		err = tx.ChangeSplit(ss, spec.Change.Outputs, fees(spec))
+	default:
+		err = tx.Change(ss[0], fees(spec))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.Outputs[n:], nil
+}
+
+// fees returns the fee model of the spec.
+func fees(spec *Spec) bt.FeeModel {
+	if spec.Fees == nil {
+		return bt.NewFeeQuote()
+	}
+
+	return spec.Fees
+}
+
+// sources returns a UTXOGetterFunc calling each of ss in turn, moving on to the
+// next once one returns bt.ErrNoUTXO.
+func sources(ss []bt.UTXOGetterFunc) bt.UTXOGetterFunc {
+	return func(ctx context.Context, deficit uint64) ([]*bt.UTXO, error) {
+		for len(ss) > 0 {
+			utxos, err := ss[0](ctx, deficit)
+			if errors.Is(err, bt.ErrNoUTXO) {
+				ss = ss[1:]
+				continue
+			}
+
+			return utxos, err
+		}
+
+		return nil, bt.ErrNoUTXO
+	}
+}
@@ -0,0 +1,247 @@
+package builder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libsv/go-bk/wif"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/builder"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	payAddress    = "1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi"
+	changeAddress = "mwV3YgnowbJJB3LcyCuqiKpdivvNNFiK7M"
+)
+
+type testKey struct {
+	lockingScript *bscript.Script
+	unlocker      bt.UnlockerGetter
+}
+
+func newTestKey(t *testing.T) *testKey {
+	t.Helper()
+
+	w, err := wif.DecodeWIF("L3MhnEn1pLWcggeYLk9jdkvA2wUK1iWwwrGkBbgQRqv6HPCdRxuw")
+	assert.NoError(t, err)
+	s, err := bscript.NewP2PKHFromPubKeyEC(w.PrivKey.PubKey())
+	assert.NoError(t, err)
+
+	return &testKey{lockingScript: s, unlocker: &unlocker.Getter{PrivateKey: w.PrivKey}}
+}
+
+// source returns a utxo source providing a utxo of each of satoshis in turn.
+func (k *testKey) source(vout uint32, satoshis ...uint64) bt.UTXOGetterFunc {
+	return func(ctx context.Context, deficit uint64) ([]*bt.UTXO, error) {
+		if len(satoshis) == 0 {
+			return nil, bt.ErrNoUTXO
+		}
+		u := k.utxo(vout, satoshis[0])
+		satoshis = satoshis[1:]
+		vout++
+		return []*bt.UTXO{u}, nil
+	}
+}
+
+func (k *testKey) utxo(vout uint32, satoshis uint64) *bt.UTXO {
+	return &bt.UTXO{
+		TxID:          make([]byte, 32),
+		Vout:          vout,
+		LockingScript: k.lockingScript,
+		Satoshis:      satoshis,
+	}
+}
+
+// paddedUnlocker signs as unlocker, padding the unlocking script so the signed tx
+// is larger than estimated.
+type paddedUnlocker struct {
+	bt.UnlockerGetter
+}
+
+func (p *paddedUnlocker) Unlocker(ctx context.Context, s *bscript.Script) (bt.Unlocker, error) {
+	u, err := p.UnlockerGetter.Unlocker(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return paddedUnlockerFunc(func(ctx context.Context, tx *bt.Tx, up bt.UnlockerParams) (*bscript.Script, error) {
+		us, err := u.UnlockingScript(ctx, tx, up)
+		if err != nil {
+			return nil, err
+		}
+		if err = us.AppendPushData(make([]byte, 2000)); err != nil {
+			return nil, err
+		}
+		return us, us.AppendOpcodes(bscript.OpDROP)
+	}), nil
+}
+
+type paddedUnlockerFunc func(ctx context.Context, tx *bt.Tx, up bt.UnlockerParams) (*bscript.Script, error)
+
+func (f paddedUnlockerFunc) UnlockingScript(ctx context.Context, tx *bt.Tx, up bt.UnlockerParams) (*bscript.Script, error) {
+	return f(ctx, tx, up)
+}
+
+func (k *testKey) spec() *builder.Spec {
+	return &builder.Spec{
+		Recipients: []builder.Recipient{{Address: payAddress, Satoshis: 2000}},
+		Data:       [][][]byte{{[]byte("hello"), []byte("world")}},
+		UTXOs:      bt.UTXOs{k.utxo(0, 500)},
+		Sources: []bt.UTXOGetterFunc{
+			k.source(10, 600),
+			k.source(20, 5000, 5000),
+		},
+		Change:   &builder.ChangePolicy{Address: changeAddress},
+		Unlocker: k.unlocker,
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	k := newTestKey(t)
+	plan, err := builder.DryRun(context.Background(), k.spec())
+	assert.NoError(t, err)
+
+	assert.Len(t, plan.Inputs, 3)
+	for _, in := range plan.Inputs {
+		assert.Nil(t, in.UnlockingScript)
+	}
+	assert.Len(t, plan.Outputs, 3)
+	assert.Equal(t, uint64(2000), plan.Outputs[0].Satoshis)
+	assert.True(t, plan.Outputs[1].LockingScript.IsData())
+	assert.Equal(t, plan.Outputs[2:], plan.Change)
+
+	size, err := plan.Tx.EstimateSizeWithTypes()
+	assert.NoError(t, err)
+	assert.Equal(t, size, plan.Size)
+	assert.Equal(t, size.TotalBytes/2, plan.Fee)
+	assert.Equal(t, uint64(500+600+5000-2000)-plan.Fee, plan.Change[0].Satoshis)
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	k := newTestKey(t)
+
+	t.Run("single change output", func(t *testing.T) {
+		tx, err := builder.Build(context.Background(), k.spec())
+		assert.NoError(t, err)
+
+		assert.Equal(t, 3, tx.InputCount())
+		for _, in := range tx.Inputs {
+			assert.NotEmpty(t, *in.UnlockingScript)
+			assert.Equal(t, bt.DefaultSequenceNumber, in.SequenceNumber)
+		}
+		assert.Equal(t, 3, tx.OutputCount())
+		ok, err := tx.IsFeePaidEnough(bt.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("split change", func(t *testing.T) {
+		spec := k.spec()
+		spec.Change.Outputs = 3
+		tx, err := builder.Build(context.Background(), spec)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 5, tx.OutputCount())
+		for _, in := range tx.Inputs {
+			assert.NotEmpty(t, *in.UnlockingScript)
+		}
+		ok, err := tx.IsFeePaidEnough(bt.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("lock time", func(t *testing.T) {
+		spec := k.spec()
+		spec.LockTime = 800000
+		tx, err := builder.Build(context.Background(), spec)
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint32(800000), tx.LockTime)
+		for _, in := range tx.Inputs {
+			assert.Equal(t, bt.MaxTxInSequenceNum-1, in.SequenceNumber)
+		}
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		spec := k.spec()
+		spec.Change = nil
+		spec.Sources = []bt.UTXOGetterFunc{k.source(10, 2000)}
+		tx, err := builder.Build(context.Background(), spec)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, tx.OutputCount())
+		assert.Equal(t, uint64(500), tx.TotalInputSatoshis()-tx.TotalOutputSatoshis())
+	})
+
+	t.Run("signed tx larger than estimated is funded from sources", func(t *testing.T) {
+		spec := k.spec()
+		spec.Data = nil
+		spec.Change = nil
+		spec.UTXOs = bt.UTXOs{k.utxo(0, 2200)}
+		spec.Sources = []bt.UTXOGetterFunc{k.source(10, 5000)}
+		spec.Unlocker = &paddedUnlocker{UnlockerGetter: k.unlocker}
+		tx, err := builder.Build(context.Background(), spec)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 2, tx.InputCount())
+		ok, err := tx.IsFeePaidEnough(bt.NewFeeQuote())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		spec := k.spec()
+		spec.Sources = spec.Sources[:1]
+		_, err := builder.Build(context.Background(), spec)
+		assert.ErrorIs(t, err, bt.ErrInsufficientFunds)
+	})
+}
+
+func TestSpec_Validate(t *testing.T) {
+	t.Parallel()
+
+	k := newTestKey(t)
+	assert.NoError(t, k.spec().Validate())
+
+	spec := &builder.Spec{
+		Recipients: []builder.Recipient{
+			{Address: payAddress, Script: k.lockingScript, Satoshis: 1000},
+			{Script: k.lockingScript},
+			{Address: "not an address", Satoshis: 1000},
+		},
+		Data:   [][][]byte{{}},
+		Change: &builder.ChangePolicy{Address: changeAddress, Outputs: 2, Denominations: []uint64{1000}},
+	}
+
+	err := spec.Validate()
+	var specErr builder.SpecError
+	assert.True(t, errors.As(err, &specErr))
+	assert.Len(t, specErr.Problems, 6)
+	for _, exp := range []error{
+		builder.ErrInvalidRecipient,
+		builder.ErrNoSatoshis,
+		builder.ErrEmptyData,
+		builder.ErrNoUTXOs,
+		builder.ErrInvalidChange,
+	} {
+		assert.ErrorIs(t, err, exp)
+	}
+	assert.NotErrorIs(t, err, builder.ErrNoOutputs)
+
+	// building also requires an unlocker
+	_, err = builder.Build(context.Background(), spec)
+	assert.ErrorIs(t, err, bt.ErrNoUnlocker)
+	_, err = builder.DryRun(context.Background(), spec)
+	assert.NotErrorIs(t, err, bt.ErrNoUnlocker)
+
+	err = (&builder.Spec{UTXOs: bt.UTXOs{k.utxo(0, 1000)}}).Validate()
+	assert.ErrorIs(t, err, builder.ErrNoOutputs)
+}
@@ -0,0 +1,13 @@
+package builder
+
+import "github.com/pkg/errors"
+
+// Sentinel errors reported for an invalid Spec.
+var (
+	ErrNoOutputs        = errors.New("spec has no recipients or data")
+	ErrInvalidRecipient = errors.New("recipient must have one of an address or a script")
+	ErrNoSatoshis       = errors.New("recipient must be paid satoshis")
+	ErrEmptyData        = errors.New("data output must have at least one part")
+	ErrNoUTXOs          = errors.New("spec has no utxos or utxo sources")
+	ErrInvalidChange    = errors.New("change must have one of an address or scripts, and either outputs or denominations")
+)
@@ -0,0 +1,162 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/pkg/errors"
+)
+
+// Recipient is an output paying Satoshis to either an Address or a locking Script.
+type Recipient struct {
+	Address  string
+	Script   *bscript.Script
+	Satoshis uint64
+}
+
+// ChangePolicy describes the change outputs of a tx. Change pays to the Address, or
+// to the Scripts in turn, and is split equally across Outputs outputs, or into
+// outputs of the Denominations, as with `Tx.ChangeSplit` and `Tx.ChangeDenominations`.
+type ChangePolicy struct {
+	Address       string
+	Scripts       []*bscript.Script
+	Outputs       int
+	Denominations []uint64
+}
+
+// Spec declares a tx to build.
+//
+// The tx pays each of the Recipients, in order, followed by an OP_FALSE OP_RETURN
+// output for each of Data, with the parts of each pushed in order.
+//
+// The tx spends all of UTXOs, and if they do not cover the outputs and fee, is
+// funded from each of Sources in turn, as with `Tx.Fund`. Any change is added as
+// described by Change, or left as fee if Change is nil.
+//
+// The fee is computed by Fees, which defaults to `bt.NewFeeQuote`. If LockTime is
+// set, the inputs have a sequence number of one less than the maximum so that the
+// lock time applies. The inputs are signed with the unlockers from Unlocker.
+type Spec struct {
+	Recipients []Recipient
+	Data       [][][]byte
+	UTXOs      bt.UTXOs
+	Sources    []bt.UTXOGetterFunc
+	Change     *ChangePolicy
+	Fees       bt.FeeModel
+	LockTime   uint32
+	Unlocker   bt.UnlockerGetter
+}
+
+// SpecError is returned for an invalid Spec, with each of the problems found.
+type SpecError struct {
+	Problems []error
+}
+
+// Error returns the problems with the spec.
+func (e SpecError) Error() string {
+	pp := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		pp = append(pp, p.Error())
+	}
+
+	return "invalid spec: " + strings.Join(pp, "; ")
+}
+
+// Is returns true if any of the problems is the target error.
+func (e SpecError) Is(target error) bool {
+	for _, p := range e.Problems {
+		if errors.Is(p, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate returns a SpecError with every problem with the spec, or nil if there
+// are none. The Unlocker is not required for a dry run, so is not validated.
+func (s *Spec) Validate() error {
+	if pp := s.problems(); len(pp) > 0 {
+		return SpecError{Problems: pp}
+	}
+
+	return nil
+}
+
+func (s *Spec) problems() []error {
+	var pp []error
+	if len(s.Recipients) == 0 && len(s.Data) == 0 {
+		pp = append(pp, ErrNoOutputs)
+	}
+	for i, r := range s.Recipients {
+		if err := r.validate(); err != nil {
+			pp = append(pp, errors.Wrapf(err, "recipient %d", i))
+		}
+	}
+	for i, d := range s.Data {
+		if len(d) == 0 {
+			pp = append(pp, errors.Wrapf(ErrEmptyData, "data %d", i))
+		}
+	}
+	if len(s.UTXOs) == 0 && len(s.Sources) == 0 {
+		pp = append(pp, ErrNoUTXOs)
+	}
+	if s.Change != nil {
+		if err := s.Change.validate(); err != nil {
+			pp = append(pp, errors.Wrap(err, "change"))
+		}
+	}
+
+	return pp
+}
+
+func (r Recipient) validate() error {
+	if (r.Address == "") == (r.Script == nil) {
+		return ErrInvalidRecipient
+	}
+	if r.Address != "" {
+		if _, err := bscript.NewP2PKHFromAddress(r.Address); err != nil {
+			return err
+		}
+	}
+	if r.Satoshis == 0 {
+		return ErrNoSatoshis
+	}
+
+	return nil
+}
+
+func (c *ChangePolicy) validate() error {
+	if (c.Address == "") == (len(c.Scripts) == 0) {
+		return ErrInvalidChange
+	}
+	if c.Address != "" {
+		if _, err := bscript.NewP2PKHFromAddress(c.Address); err != nil {
+			return err
+		}
+	}
+	if c.Outputs < 0 || (c.Outputs > 1 && len(c.Denominations) > 0) {
+		return ErrInvalidChange
+	}
+
+	return nil
+}
+
+// scripts returns the scripts the change pays to.
+func (c *ChangePolicy) scripts() ([]*bscript.Script, error) {
+	if c.Address == "" {
+		return c.Scripts, nil
+	}
+	s, err := bscript.NewP2PKHFromAddress(c.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*bscript.Script{s}, nil
+}
+
+// single returns true if the change is a single output.
+func (c *ChangePolicy) single() bool {
+	return c.Outputs <= 1 && len(c.Denominations) == 0
+}
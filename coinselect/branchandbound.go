@@ -0,0 +1,113 @@
+package coinselect
+
+import (
+	"sort"
+
+	"github.com/libsv/go-bt/v2"
+)
+
+// DefaultMaxTries the default number of branches BranchAndBound explores.
+const DefaultMaxTries = 100000
+
+// BranchAndBound searches for a set of candidates which funds the tx with an
+// excess no greater than the cost of a change output, so that no change output
+// is needed and nothing is left to the miner beyond the fee and the dust limit.
+//
+// The search is depth first over the candidates, largest effective value first,
+// pruning branches which overshoot the excess or cannot reach the target. If no
+// exact match is found within MaxTries branches, the Fallback strategy is used if
+// set, otherwise ErrNoExactMatch is returned.
+type BranchAndBound struct {
+	// MaxTries the number of branches to explore, DefaultMaxTries if 0.
+	MaxTries int
+	// Fallback the strategy to use if no exact match is found.
+	Fallback Strategy
+}
+
+// Select implements Strategy.
+func (b BranchAndBound) Select(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel) (*Selection, error) {
+	f, err := newFunder(tx, fm)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := f.candidates(candidates)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cc, func(i, j int) bool {
+		return cc[i].effective > cc[j].effective
+	})
+
+	// remaining[i] is the total effective value of cc[i:].
+	remaining := make([]uint64, len(cc)+1)
+	for i := len(cc) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + cc[i].effective
+	}
+
+	target := f.target()
+	if remaining[0] < target {
+		return nil, bt.ErrInsufficientFunds
+	}
+	costOfChange, err := f.costOfChange()
+	if err != nil {
+		return nil, err
+	}
+	upper := target + costOfChange
+
+	tries := b.MaxTries
+	if tries <= 0 {
+		tries = DefaultMaxTries
+	}
+
+	var match *Selection
+	picked := make([]*candidate, 0, len(cc))
+	var search func(i int, value uint64) (bool, error)
+	search = func(i int, value uint64) (bool, error) {
+		if tries == 0 || value > upper {
+			return false, nil
+		}
+		tries--
+
+		if value >= target {
+			sel, ok, err := f.selection(picked)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				if sel.Change == 0 {
+					match = sel
+					return true, nil
+				}
+				// adding more candidates can only increase the excess
+				return false, nil
+			}
+			// the fee grew beyond the target with the inputs picked, such as by
+			// the input count needing a larger varint, so more may yet fund it
+		}
+		if i == len(cc) || value+remaining[i] < target {
+			return false, nil
+		}
+
+		picked = append(picked, cc[i])
+		if found, err := search(i+1, value+cc[i].effective); found || err != nil {
+			return found, err
+		}
+		picked = picked[:len(picked)-1]
+
+		return search(i+1, value)
+	}
+
+	found, err := search(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return match, nil
+	}
+	if b.Fallback != nil {
+		return b.Fallback.Select(tx, candidates, fm)
+	}
+
+	return nil, ErrNoExactMatch
+}
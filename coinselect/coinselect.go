@@ -0,0 +1,376 @@
+// Package coinselect chooses which utxos to spend when funding a tx.
+//
+// Each Strategy picks from a set of candidate utxos enough to pay the outputs of
+// a tx and the fee of the funded tx, as computed by a `bt.FeeModel`. The fee
+// of each candidate is estimated from the unlocking script length of its template
+// (see `bscript.MatchTemplate`), so candidates with an unrecognised locking script,
+// or which would cost more in fees than they are worth, are never selected.
+//
+// A Strategy plugs into `Tx.Fund` with UTXOGetter:
+//
+//	if err := tx.Fund(ctx, fm, coinselect.UTXOGetter(tx, fm, coinselect.LargestFirst{}, utxos)); err != nil {}
+package coinselect
+
+import (
+	"context"
+	"sort"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+const (
+	// inputOverheadSize the size of an input excluding its unlocking script and
+	// the varint of its length: the previous txid, vout and sequence number.
+	inputOverheadSize = 32 + 4 + 4
+
+	// changeOutputSize the size of a P2PKH change output.
+	changeOutputSize = 8 + 1 + 25
+)
+
+// Strategy selects utxos from candidates to fund a tx.
+type Strategy interface {
+	Select(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel) (*Selection, error)
+}
+
+// Selection the utxos chosen by a Strategy.
+type Selection struct {
+	// UTXOs the selected utxos, in the order they should be added to the tx.
+	UTXOs bt.UTXOs
+	// Fee the estimated fee of the funded tx without a change output.
+	Fee uint64
+	// Change the satoshis left for a change output once its own fee is paid,
+	// or 0 if the excess is not above the dust limit and is left to the miner.
+	Change uint64
+}
+
+// Total returns the total satoshis of the selected utxos.
+func (s *Selection) Total() (total uint64) {
+	for _, u := range s.UTXOs {
+		total += u.Satoshis
+	}
+
+	return total
+}
+
+// Select selects utxos from candidates to fund the tx with the given strategy.
+func Select(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel, s Strategy) (*Selection, error) {
+	return s.Select(tx, candidates, fm)
+}
+
+// UTXOGetter returns a `bt.UTXOGetterFunc` for `Tx.Fund`, which provides the
+// utxos selected by the strategy from candidates for the tx. If no selection can
+// fund the tx, the error of the strategy is returned.
+//
+// Should `Tx.Fund` call again with a deficit, as its fee model expects more of the
+// tx than estimated, the strategy selects again from the candidates not yet provided,
+// for the tx with the utxos already provided. If the strategy selects nothing, the
+// candidates worth the most, less the fee of spending them, are provided until they
+// cover the deficit. `bt.ErrNoUTXO` is returned once no candidates remain.
+func UTXOGetter(tx *bt.Tx, fm bt.FeeModel, s Strategy, candidates bt.UTXOs) bt.UTXOGetterFunc {
+	remaining := append(bt.UTXOs{}, candidates...)
+	return func(ctx context.Context, deficit uint64) ([]*bt.UTXO, error) {
+		if len(remaining) == 0 {
+			return nil, bt.ErrNoUTXO
+		}
+
+		sel, err := s.Select(tx, remaining, fm)
+		if err != nil {
+			return nil, err
+		}
+		uu := sel.UTXOs
+		if len(uu) == 0 {
+			if uu, err = cover(tx, remaining, fm, deficit); err != nil {
+				return nil, err
+			}
+		}
+
+		remaining = without(remaining, uu)
+		return uu, nil
+	}
+}
+
+// cover returns the candidates worth the most, less the fee of spending them,
+// until they cover the deficit.
+func cover(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel, deficit uint64) (bt.UTXOs, error) {
+	f, err := newFunder(tx, fm)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := f.candidates(candidates)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cc, func(i, j int) bool {
+		return cc[i].effective > cc[j].effective
+	})
+
+	var value uint64
+	uu := make(bt.UTXOs, 0, len(cc))
+	for _, c := range cc {
+		if value >= deficit {
+			break
+		}
+		uu = append(uu, c.utxo)
+		value += c.effective
+	}
+	if len(uu) == 0 {
+		return nil, bt.ErrNoUTXO
+	}
+
+	return uu, nil
+}
+
+// without returns the utxos of uu which are not in exclude.
+func without(uu, exclude bt.UTXOs) bt.UTXOs {
+	out := uu[:0]
+	for _, u := range uu {
+		excluded := false
+		for _, e := range exclude {
+			if u == e {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			out = append(out, u)
+		}
+	}
+
+	return out
+}
+
+// candidate is a utxo with the size and fee it adds to the tx when spent.
+type candidate struct {
+	utxo *bt.UTXO
+	size uint64
+	// effective the satoshis of the utxo less the fee of spending it.
+	effective uint64
+}
+
+// funder estimates the fees of the tx being funded as utxos are added to it.
+type funder struct {
+	inputs  uint64
+	outputs uint64
+	nIn     int
+	nOut    int
+
+	fm bt.FeeModel
+	// tx the tx being funded, and changeTx the tx with a P2PKH change output, as
+	// passed to the fee model.
+	tx       *bt.Tx
+	changeTx *bt.Tx
+	// size the estimated size of the tx before any utxos are added.
+	size bt.TxSize
+	// base the fee of the tx before any utxos are added.
+	base      uint64
+	dustLimit uint64
+}
+
+func newFunder(tx *bt.Tx, fm bt.FeeModel) (*funder, error) {
+	if fm == nil {
+		return nil, bt.ErrFeeModelNotInit
+	}
+	size, err := tx.EstimateSizeWithTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := bscript.NewP2PKHFromPubKeyHash(make([]byte, 20))
+	if err != nil {
+		return nil, err
+	}
+	changeTx := tx.Clone()
+	changeTx.AddOutput(&bt.Output{LockingScript: change})
+
+	f := &funder{
+		inputs:    tx.TotalInputSatoshis(),
+		outputs:   tx.TotalOutputSatoshis(),
+		nIn:       tx.InputCount(),
+		nOut:      tx.OutputCount(),
+		fm:        fm,
+		tx:        tx,
+		changeTx:  changeTx,
+		size:      *size,
+		dustLimit: bt.DustLimit,
+	}
+	if f.base, err = f.fee(0, 0, false); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// candidates returns the utxos which can be spent and are worth more than the
+// fee of spending them.
+func (f *funder) candidates(utxos bt.UTXOs) ([]*candidate, error) {
+	cc := make([]*candidate, 0, len(utxos))
+	for _, u := range utxos {
+		if u.LockingScript == nil {
+			continue
+		}
+		t, ok := bscript.MatchTemplate(u.LockingScript)
+		if !ok {
+			continue
+		}
+		unlockLen := t.EstimateUnlockLength(u.LockingScript)
+		size := uint64(inputOverheadSize + bt.VarInt(unlockLen).Length() + unlockLen)
+
+		fee, err := f.marginal(size)
+		if err != nil {
+			return nil, err
+		}
+		if u.Satoshis <= fee {
+			continue
+		}
+		cc = append(cc, &candidate{utxo: u, size: size, effective: u.Satoshis - fee})
+	}
+
+	return cc, nil
+}
+
+// marginal returns the fee of adding size standard bytes to the tx.
+func (f *funder) marginal(size uint64) (uint64, error) {
+	fee, err := f.fee(0, size, false)
+	if err != nil || fee < f.base {
+		return 0, err
+	}
+
+	return fee - f.base, nil
+}
+
+// fee returns the fee of the tx with n inputs of total size added, and a change
+// output if change is true, as computed by the fee model. It matches the estimate
+// used by `Tx.Fund`.
+func (f *funder) fee(n int, size uint64, change bool) (uint64, error) {
+	std := size + uint64(bt.VarInt(f.nIn+n).Length()-bt.VarInt(f.nIn).Length())
+	tx := f.tx
+	if change {
+		std += changeOutputSize
+		std += uint64(bt.VarInt(f.nOut+1).Length() - bt.VarInt(f.nOut).Length())
+		tx = f.changeTx
+	}
+
+	sz := f.size
+	sz.TotalBytes += std
+	sz.TotalStdBytes += std
+
+	return f.fm.ComputeFee(tx, &sz)
+}
+
+// target returns the effective value the selected utxos must cover, being the
+// outputs and fee of the tx before any utxos are added, less its existing inputs.
+func (f *funder) target() uint64 {
+	need := f.outputs + f.base
+	if f.inputs >= need {
+		return 0
+	}
+
+	return need - f.inputs
+}
+
+// costOfChange returns the fee of adding a change output, plus the dust limit
+// which the change must exceed.
+func (f *funder) costOfChange() (uint64, error) {
+	fee, err := f.fee(0, 0, true)
+	if err != nil {
+		return 0, err
+	}
+	if fee < f.base {
+		return f.dustLimit, nil
+	}
+
+	return fee - f.base + f.dustLimit, nil
+}
+
+// selection returns the selection of cc, or false if cc does not fund the tx.
+func (f *funder) selection(cc []*candidate) (*Selection, bool, error) {
+	var total, size uint64
+	for _, c := range cc {
+		total += c.utxo.Satoshis
+		size += c.size
+	}
+	total += f.inputs
+
+	fee, err := f.fee(len(cc), size, false)
+	if err != nil {
+		return nil, false, err
+	}
+	if total < f.outputs+fee {
+		return nil, false, nil
+	}
+
+	sel := &Selection{UTXOs: make(bt.UTXOs, 0, len(cc)), Fee: fee}
+	for _, c := range cc {
+		sel.UTXOs = append(sel.UTXOs, c.utxo)
+	}
+	changeFee, err := f.fee(len(cc), size, true)
+	if err != nil {
+		return nil, false, err
+	}
+	if total > f.outputs+changeFee && total-f.outputs-changeFee > f.dustLimit {
+		sel.Change = total - f.outputs - changeFee
+	}
+
+	return sel, true, nil
+}
+
+// accumulate selects from cc in order until the tx is funded.
+func (f *funder) accumulate(cc []*candidate) (*Selection, error) {
+	for i := 0; i <= len(cc); i++ {
+		sel, ok, err := f.selection(cc[:i])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return sel, nil
+		}
+	}
+
+	return nil, bt.ErrInsufficientFunds
+}
+
+// LargestFirst selects the candidates with the most satoshis first, which
+// minimises the number of inputs and so the fee.
+type LargestFirst struct{}
+
+// Select implements Strategy.
+func (LargestFirst) Select(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel) (*Selection, error) {
+	f, err := newFunder(tx, fm)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := f.candidates(candidates)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cc, func(i, j int) bool {
+		return cc[i].utxo.Satoshis > cc[j].utxo.Satoshis
+	})
+
+	return f.accumulate(cc)
+}
+
+// SmallestFirst selects the candidates with the fewest satoshis first, which
+// consolidates small utxos at the cost of a larger fee.
+type SmallestFirst struct{}
+
+// Select implements Strategy.
+func (SmallestFirst) Select(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel) (*Selection, error) {
+	f, err := newFunder(tx, fm)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, err := f.candidates(candidates)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(cc, func(i, j int) bool {
+		return cc[i].utxo.Satoshis < cc[j].utxo.Satoshis
+	})
+
+	return f.accumulate(cc)
+}
@@ -0,0 +1,304 @@
+package coinselect_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/coinselect"
+	"github.com/stretchr/testify/assert"
+)
+
+const p2pkh = "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac"
+
+func utxos(t *testing.T, satoshis ...uint64) bt.UTXOs {
+	t.Helper()
+
+	s, err := bscript.NewFromHexString(p2pkh)
+	assert.NoError(t, err)
+
+	uu := make(bt.UTXOs, 0, len(satoshis))
+	for i, sats := range satoshis {
+		uu = append(uu, &bt.UTXO{
+			TxID:          make([]byte, 32),
+			Vout:          uint32(i),
+			LockingScript: s,
+			Satoshis:      sats,
+		})
+	}
+
+	return uu
+}
+
+func payment(t *testing.T, satoshis uint64) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", satoshis))
+
+	return tx
+}
+
+func selected(sel *coinselect.Selection) []uint64 {
+	ss := make([]uint64, 0, len(sel.UTXOs))
+	for _, u := range sel.UTXOs {
+		ss = append(ss, u.Satoshis)
+	}
+
+	return ss
+}
+
+func TestStrategies(t *testing.T) {
+	t.Parallel()
+
+	// A P2PKH input costs 74 satoshis at the default 0.5 sat/byte, and the
+	// payment tx 22 satoshis before any inputs are added.
+	tests := map[string]struct {
+		strategy  coinselect.Strategy
+		payment   uint64
+		utxos     []uint64
+		expUTXOs  []uint64
+		expFee    uint64
+		expChange uint64
+		expErr    error
+	}{
+		"largest first": {
+			strategy:  coinselect.LargestFirst{},
+			payment:   1000,
+			utxos:     []uint64{500, 2000, 700, 800},
+			expUTXOs:  []uint64{2000},
+			expFee:    96,
+			expChange: 887,
+		},
+		"largest first with several inputs": {
+			strategy:  coinselect.LargestFirst{},
+			payment:   2500,
+			utxos:     []uint64{500, 2000, 700, 800},
+			expUTXOs:  []uint64{2000, 800},
+			expFee:    170,
+			expChange: 113,
+		},
+		"smallest first": {
+			strategy:  coinselect.SmallestFirst{},
+			payment:   1000,
+			utxos:     []uint64{500, 2000, 700, 800},
+			expUTXOs:  []uint64{500, 700},
+			expFee:    170,
+			expChange: 13,
+		},
+		"utxos worth less than their fee are skipped": {
+			strategy: coinselect.SmallestFirst{},
+			payment:  1000,
+			utxos:    []uint64{74, 60, 2000},
+			expUTXOs: []uint64{2000},
+			expFee:   96,
+			// 2000 - 1000 - 113
+			expChange: 887,
+		},
+		"change at the dust limit is left to the miner": {
+			strategy: coinselect.LargestFirst{},
+			payment:  1000,
+			utxos:    []uint64{1114},
+			expUTXOs: []uint64{1114},
+			expFee:   96,
+		},
+		"insufficient funds": {
+			strategy: coinselect.LargestFirst{},
+			payment:  5000,
+			utxos:    []uint64{500, 2000, 700, 800},
+			expErr:   bt.ErrInsufficientFunds,
+		},
+		"branch and bound finds an exact match": {
+			strategy: coinselect.BranchAndBound{},
+			payment:  1000,
+			// 620 + 550 - 2 * 74 = 1022, between the 1000 + 22 target and
+			// the 17 of a change output plus 1 of dust above it
+			utxos:    []uint64{2000, 550, 3000, 620},
+			expUTXOs: []uint64{620, 550},
+			expFee:   170,
+		},
+		"branch and bound without a match": {
+			strategy: coinselect.BranchAndBound{},
+			payment:  1000,
+			utxos:    []uint64{2000, 3000},
+			expErr:   coinselect.ErrNoExactMatch,
+		},
+		"branch and bound falls back": {
+			strategy:  coinselect.BranchAndBound{Fallback: coinselect.SmallestFirst{}},
+			payment:   1000,
+			utxos:     []uint64{2000, 3000},
+			expUTXOs:  []uint64{2000},
+			expFee:    96,
+			expChange: 887,
+		},
+		"branch and bound with insufficient funds": {
+			strategy: coinselect.BranchAndBound{},
+			payment:  5000,
+			utxos:    []uint64{2000, 3000},
+			expErr:   bt.ErrInsufficientFunds,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sel, err := coinselect.Select(payment(t, test.payment), utxos(t, test.utxos...), bt.NewFeeQuote(), test.strategy)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expUTXOs, selected(sel))
+			assert.Equal(t, test.expFee, sel.Fee)
+			assert.Equal(t, test.expChange, sel.Change)
+		})
+	}
+}
+
+func TestStrategies_FeeModel(t *testing.T) {
+	t.Parallel()
+
+	// a P2PKH input of 148 bytes costs 148 satoshis, on top of the minimum fee
+	fm := bt.MinimumFeeModel{MinimumFee: 500, SatoshisPerKB: 1000}
+
+	sel, err := coinselect.Select(payment(t, 1000), utxos(t, 100, 1500, 2000), fm, coinselect.SmallestFirst{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1500, 2000}, selected(sel))
+
+	tx := payment(t, 1000)
+	assert.NoError(t, tx.Fund(context.Background(), fm, coinselect.UTXOGetter(tx, fm, coinselect.SmallestFirst{}, utxos(t, 100, 1500, 2000))))
+	assert.Equal(t, uint64(3500), tx.TotalInputSatoshis())
+	ok, err := tx.EstimateIsFeePaidEnough(fm)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = coinselect.Select(payment(t, 1000), utxos(t, 2000), nil, coinselect.LargestFirst{})
+	assert.ErrorIs(t, err, bt.ErrFeeModelNotInit)
+}
+
+// steppedFeeModel charges 1 satoshi per 10 bytes and 100 per output, plus 15 once
+// the tx exceeds 300 bytes, so that several inputs cost more than each does alone.
+type steppedFeeModel struct{}
+
+func (steppedFeeModel) ComputeFee(tx *bt.Tx, size *bt.TxSize) (uint64, error) {
+	fee := size.TotalBytes/10 + 100*uint64(tx.OutputCount())
+	if size.TotalBytes > 300 {
+		fee += 15
+	}
+
+	return fee, nil
+}
+
+func TestBranchAndBound_FeeGrowth(t *testing.T) {
+	t.Parallel()
+
+	// Each input is estimated at 15 satoshis, so 615 and 525 are worth 1110, above
+	// the 1104 target, but together cost 149 rather than 134. Only adding 65 funds
+	// the tx, with an excess below the cost of change.
+	sel, err := coinselect.Select(payment(t, 1000), utxos(t, 615, 525, 65), steppedFeeModel{}, coinselect.BranchAndBound{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{615, 525, 65}, selected(sel))
+	assert.Equal(t, uint64(163), sel.Fee)
+	assert.Zero(t, sel.Change)
+}
+
+// inputFeeModel charges 1 satoshi per 10 bytes, plus 50 per input of the tx.
+type inputFeeModel struct{}
+
+func (inputFeeModel) ComputeFee(tx *bt.Tx, size *bt.TxSize) (uint64, error) {
+	return size.TotalBytes/10 + 50*uint64(tx.InputCount()), nil
+}
+
+// unlockFeeModel charges 1 satoshi per 10 bytes, plus 1 per byte of the unlocking
+// scripts of the tx, which are not known until the tx is estimated by Fund.
+type unlockFeeModel struct{}
+
+func (unlockFeeModel) ComputeFee(tx *bt.Tx, size *bt.TxSize) (uint64, error) {
+	fee := size.TotalBytes / 10
+	for _, in := range tx.Inputs {
+		if in.UnlockingScript != nil {
+			fee += uint64(len(*in.UnlockingScript))
+		}
+	}
+
+	return fee, nil
+}
+
+func TestRandomImprove(t *testing.T) {
+	t.Parallel()
+
+	candidates := utxos(t, 100, 200, 300, 400, 500, 600, 700, 800, 900, 1000, 1100, 1200)
+	for seed := int64(0); seed < 20; seed++ {
+		s := coinselect.RandomImprove{Rand: rand.New(rand.NewSource(seed))}
+		sel, err := s.Select(payment(t, 1000), candidates, bt.NewFeeQuote())
+		assert.NoError(t, err)
+
+		// funded, and not beyond three times the target
+		assert.GreaterOrEqual(t, sel.Total(), 1000+sel.Fee)
+		effective := sel.Total() - uint64(74*len(sel.UTXOs))
+		assert.LessOrEqual(t, effective, uint64(3*(1000+22)))
+	}
+
+	_, err := coinselect.RandomImprove{}.Select(payment(t, 10000), candidates, bt.NewFeeQuote())
+	assert.ErrorIs(t, err, bt.ErrInsufficientFunds)
+}
+
+func TestUTXOGetter(t *testing.T) {
+	t.Parallel()
+
+	fq := bt.NewFeeQuote()
+	s, err := bscript.NewFromHexString(p2pkh)
+	assert.NoError(t, err)
+
+	t.Run("funds the tx with the selection", func(t *testing.T) {
+		tx := payment(t, 1000)
+		err := tx.Fund(context.Background(), fq, coinselect.UTXOGetter(tx, fq, coinselect.BranchAndBound{}, utxos(t, 2000, 550, 3000, 620)))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, tx.InputCount())
+		assert.Equal(t, uint64(1170), tx.TotalInputSatoshis())
+
+		// an exact match needs no change
+		assert.NoError(t, tx.Change(s, fq))
+		assert.Equal(t, 1, tx.OutputCount())
+	})
+
+	t.Run("the strategy selects again for a deficit", func(t *testing.T) {
+		// the inputs selected are charged 100 more than estimated, which 200 covers
+		tx := payment(t, 1200)
+		err := tx.Fund(context.Background(), inputFeeModel{}, coinselect.UTXOGetter(tx, inputFeeModel{}, coinselect.LargestFirst{}, utxos(t, 1000, 300, 200)))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, tx.InputCount())
+		assert.Equal(t, uint64(1500), tx.TotalInputSatoshis())
+	})
+
+	t.Run("the deficit is covered when the strategy selects nothing", func(t *testing.T) {
+		// the strategy estimates 1000 and 500 fund the tx, but the 214 bytes of their
+		// unlocking scripts leave a deficit of 48, covered by the largest of the rest
+		tx := payment(t, 1300)
+		err := tx.Fund(context.Background(), unlockFeeModel{}, coinselect.UTXOGetter(tx, unlockFeeModel{}, coinselect.LargestFirst{}, utxos(t, 1000, 500, 300, 400)))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, tx.InputCount())
+		assert.Equal(t, uint64(1900), tx.TotalInputSatoshis())
+	})
+
+	t.Run("strategy errors are returned", func(t *testing.T) {
+		tx := payment(t, 1000)
+		err := tx.Fund(context.Background(), fq, coinselect.UTXOGetter(tx, fq, coinselect.BranchAndBound{}, utxos(t, 2000)))
+		assert.ErrorIs(t, err, coinselect.ErrNoExactMatch)
+	})
+
+	t.Run("unsupported scripts are not selected", func(t *testing.T) {
+		uu := utxos(t, 5000, 2000)
+		uu[0].LockingScript = bscript.NewFromBytes([]byte{bscript.OpTRUE})
+
+		tx := payment(t, 1000)
+		err := tx.Fund(context.Background(), fq, coinselect.UTXOGetter(tx, fq, coinselect.LargestFirst{}, uu))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(2000), tx.TotalInputSatoshis())
+	})
+}
@@ -0,0 +1,91 @@
+package coinselect
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/libsv/go-bt/v2"
+)
+
+// RandomImprove selects candidates at random until the tx is funded, then keeps
+// adding random candidates while doing so brings the selected effective value
+// closer to twice the target, without exceeding three times the target.
+//
+// Aiming for change about the size of the payment keeps the utxo set from
+// filling up with dust, and the randomness makes the utxos spent together
+// harder to link. The target is the outputs and fee of the tx before funding.
+type RandomImprove struct {
+	// Rand the source of randomness, seeded with the current time if nil.
+	Rand *rand.Rand
+}
+
+// Select implements Strategy.
+func (r RandomImprove) Select(tx *bt.Tx, candidates bt.UTXOs, fm bt.FeeModel) (*Selection, error) {
+	f, err := newFunder(tx, fm)
+	if err != nil {
+		return nil, err
+	}
+
+	rnd := r.Rand
+	if rnd == nil {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // not used for security
+	}
+
+	cc, err := f.candidates(candidates)
+	if err != nil {
+		return nil, err
+	}
+	rnd.Shuffle(len(cc), func(i, j int) {
+		cc[i], cc[j] = cc[j], cc[i]
+	})
+
+	// select at random until funded
+	sel, ok, err := f.selection(nil)
+	n := 0
+	for ; err == nil && !ok && n < len(cc); n++ {
+		sel, ok, err = f.selection(cc[:n+1])
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, bt.ErrInsufficientFunds
+	}
+
+	target := f.target()
+	if target == 0 {
+		return sel, nil
+	}
+
+	// improve towards the ideal while it gets closer
+	ideal, limit := 2*target, 3*target
+	var value uint64
+	for _, c := range cc[:n] {
+		value += c.effective
+	}
+	for ; n < len(cc); n++ {
+		next := value + cc[n].effective
+		if next > limit || distance(next, ideal) >= distance(value, ideal) {
+			break
+		}
+		value = next
+	}
+
+	improved, ok, err := f.selection(cc[:n])
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return improved, nil
+	}
+
+	return sel, nil
+}
+
+func distance(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
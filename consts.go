@@ -22,4 +22,9 @@ const (
 	// SequenceLockTimeMask is a mask that extracts the relative locktime
 	// when masked against the transaction input sequence number.
 	SequenceLockTimeMask = 0x0000ffff
+
+	// LockTimeThreshold is the number below which a lock time is
+	// interpreted to be a block number, at or above which it is
+	// interpreted as a unix timestamp.
+	LockTimeThreshold uint32 = 500000000
 )
@@ -9,11 +9,19 @@ var (
 	ErrTxTooShort        = errors.New("too short to be a tx - even an empty tx has 10 bytes")
 	ErrNLockTimeLength   = errors.New("nLockTime length must be 4 bytes long")
 	ErrEmptyValues       = errors.New("empty value or values passed, all arguments are required and cannot be empty")
-	ErrUnsupportedScript = errors.New("non-P2PKH input used in the tx - unsupported")
+	ErrUnsupportedScript = errors.New("input locking script does not match a registered template - unsupported")
 	ErrInvalidScriptType = errors.New("invalid script type")
 	ErrNoUnlocker        = errors.New("unlocker not supplied")
 )
 
+// Sentinel errors reported by strict decoding.
+var (
+	ErrNonMinimalVarInt = errors.New("varint is not minimally encoded")
+	ErrCountTooLarge    = errors.New("count exceeds the bytes remaining")
+	ErrScriptTooLong    = errors.New("script length exceeds the bytes remaining")
+	ErrTrailingBytes    = errors.New("bytes remain after the tx")
+)
+
 // Sentinal errors reported by inputs.
 var (
 	ErrInputNoExist  = errors.New("specified input does not exist")
@@ -28,7 +36,10 @@ var (
 
 // Sentinal errors reported by change.
 var (
-	ErrInsufficientInputs = errors.New("satoshis inputted to the tx are less than the outputted satoshis")
+	ErrInsufficientInputs  = errors.New("satoshis inputted to the tx are less than the outputted satoshis")
+	ErrInvalidChangeSplit  = errors.New("change must be split across at least one output and script")
+	ErrInvalidDenomination = errors.New("change denominations must be above the dust limit")
+	ErrFeeNotSettled       = errors.New("fee of the signed tx did not settle to the required fee")
 )
 
 // Sentinal errors reported by signature hash.
@@ -39,11 +50,23 @@ var (
 
 // Sentinel errors reported by the fees.
 var (
-	ErrFeeQuotesNotInit = errors.New("feeQuotes have not been setup, call NewFeeQuotes")
-	ErrMinerNoQuotes    = errors.New("miner has no quotes stored")
-	ErrFeeTypeNotFound  = errors.New("feetype not found")
-	ErrFeeQuoteNotInit  = errors.New("feeQuote has not been initialised, call NewFeeQuote()")
-	ErrUnknownFeeType   = errors.New("unknown fee type")
+	ErrFeeQuotesNotInit      = errors.New("feeQuotes have not been setup, call NewFeeQuotes")
+	ErrMinerNoQuotes         = errors.New("miner has no quotes stored")
+	ErrFeeTypeNotFound       = errors.New("feetype not found")
+	ErrFeeQuoteNotInit       = errors.New("feeQuote has not been initialised, call NewFeeQuote()")
+	ErrUnknownFeeType        = errors.New("unknown fee type")
+	ErrFeeModelNotInit       = errors.New("fee model has not been supplied")
+	ErrNoValidQuotes         = errors.New("no miner has a quote which has not expired")
+	ErrUnknownFeeCalculation = errors.New("unknown fee calculation")
+	ErrInvalidFeeUnit        = errors.New("fee unit must cover a positive number of bytes for non negative satoshis")
+)
+
+// Sentinel errors reported when decoding fee quotes.
+var (
+	ErrInvalidSignature    = errors.New("envelope signature is invalid")
+	ErrEnvelopeNotSigned   = errors.New("envelope is not signed")
+	ErrUnexpectedMiner     = errors.New("envelope is signed by an unexpected miner")
+	ErrUnsupportedEncoding = errors.New("unsupported envelope encoding")
 )
 
 // Sentinel errors reported by Fund
@@ -54,3 +77,10 @@ var (
 	// ErrInsufficientFunds insufficient funds provided for funding
 	ErrInsufficientFunds = errors.New("insufficient funds provided")
 )
+
+// Sentinel errors reported by a UTXOStore.
+var (
+	ErrUTXONotFound = errors.New("utxo not found")
+	ErrUTXOExists   = errors.New("utxo already exists")
+	ErrUTXOReserved = errors.New("utxo is reserved")
+)
@@ -4,8 +4,8 @@ import (
 	"context"
 	"log"
 
-	"github.com/libsv/go-bk/wif"
 	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/keys"
 	"github.com/libsv/go-bt/v2/unlocker"
 )
 
@@ -23,9 +23,9 @@ func main() {
 
 	_ = tx.AddOpReturnOutput([]byte("You are using go-bt!"))
 
-	decodedWif, _ := wif.DecodeWIF("L3VJH2hcRGYYG6YrbWGmsxQC1zyYixA82YjgEyrEUWDs4ALgk8Vu")
+	privKey, _ := keys.DecodeWIF("L3VJH2hcRGYYG6YrbWGmsxQC1zyYixA82YjgEyrEUWDs4ALgk8Vu")
 
-	err := tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: decodedWif.PrivKey})
+	err := tx.FillAllInputs(context.Background(), &unlocker.Getter{PrivateKey: privKey.PrivateKey})
 	if err != nil {
 		log.Fatal(err.Error())
 	}
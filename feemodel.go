@@ -0,0 +1,116 @@
+package bt
+
+// FeeModel computes the fee a tx must pay to be mined.
+//
+// A `*bt.FeeQuote` is the FeeModel for miners charging a rate per byte for the
+// standard and data parts of a tx. Other models are MinimumFeeModel, for miners
+// charging a minimum fee on top of a rate per kB, and OutputTypeFeeModel, for
+// miners pricing outputs by their script type.
+//
+// Any FeeModel can be passed to Fund, Change and IsFeePaidEnough.
+type FeeModel interface {
+	// ComputeFee returns the fee in satoshis for tx, when it is of the size given,
+	// which may be an estimate of the size of the tx once signed.
+	ComputeFee(tx *Tx, size *TxSize) (uint64, error)
+}
+
+// computeFee returns the fee for tx computed by the fee model, or ErrFeeModelNotInit
+// if there is none.
+func computeFee(f FeeModel, tx *Tx, size *TxSize) (uint64, error) {
+	if f == nil {
+		return 0, ErrFeeModelNotInit
+	}
+
+	return f.ComputeFee(tx, size)
+}
+
+// ComputeFee returns the mining fee for the standard and data bytes of the tx
+// size given, at the rates of the fee quote, calculated as set by UpdateFeeCalculation.
+// The standard and data fees are calculated separately, except by FeeCalculationNode,
+// which prices the total size of the tx at once.
+func (f *FeeQuote) ComputeFee(tx *Tx, size *TxSize) (uint64, error) {
+	fees, err := f.txFees(size)
+	if err != nil {
+		return 0, err
+	}
+
+	return fees.TotalFeePaid, nil
+}
+
+func (f *FeeQuote) txFees(size *TxSize) (*TxFees, error) {
+	stdFee, err := f.Fee(FeeTypeStandard)
+	if err != nil {
+		return nil, err
+	}
+	dataFee, err := f.Fee(FeeTypeData)
+	if err != nil {
+		return nil, err
+	}
+
+	calc := f.FeeCalculation()
+	if calc == FeeCalculationNode {
+		// The data bytes are what is left of the fee of the whole tx, once the
+		// standard bytes are paid for.
+		txFees := &TxFees{
+			StdFeePaid: calc.Fee(stdFee.MiningFee, size.TotalStdBytes),
+			TotalFeePaid: nodeFee(
+				size.TotalStdBytes*feePerK(stdFee.MiningFee)+size.TotalDataBytes*feePerK(dataFee.MiningFee),
+				size.TotalStdBytes+size.TotalDataBytes,
+			),
+		}
+		txFees.DataFeePaid = txFees.TotalFeePaid - txFees.StdFeePaid
+		return txFees, nil
+	}
+
+	txFees := &TxFees{
+		StdFeePaid:  calc.Fee(stdFee.MiningFee, size.TotalStdBytes),
+		DataFeePaid: calc.Fee(dataFee.MiningFee, size.TotalDataBytes),
+	}
+	txFees.TotalFeePaid = txFees.StdFeePaid + txFees.DataFeePaid
+	return txFees, nil
+}
+
+// MinimumFeeModel charges a flat MinimumFee plus SatoshisPerKB for every 1000
+// bytes of the tx, whatever the bytes are used for.
+type MinimumFeeModel struct {
+	MinimumFee    uint64
+	SatoshisPerKB uint64
+}
+
+// ComputeFee returns the minimum fee plus the rate per kB for the total bytes of
+// the tx size given.
+func (m MinimumFeeModel) ComputeFee(tx *Tx, size *TxSize) (uint64, error) {
+	return m.MinimumFee + size.TotalBytes*m.SatoshisPerKB/1000, nil
+}
+
+// OutputTypeFeeModel charges the fee of the Base model, plus a fee for each output
+// by the script type of its locking script, as reported by `bscript.Script.ScriptType`.
+// Outputs of types not in OutputFees are charged nothing on top of the Base fee.
+//
+//	m := bt.OutputTypeFeeModel{
+//	    Base: bt.NewFeeQuote(),
+//	    OutputFees: map[string]uint64{
+//	        bscript.ScriptTypeNullData: 100,
+//	    },
+//	}
+type OutputTypeFeeModel struct {
+	Base       FeeModel
+	OutputFees map[string]uint64
+}
+
+// ComputeFee returns the fee of the base model, plus the fee for each of the
+// outputs of the tx.
+func (m OutputTypeFeeModel) ComputeFee(tx *Tx, size *TxSize) (uint64, error) {
+	fee, err := m.Base.ComputeFee(tx, size)
+	if err != nil {
+		return 0, err
+	}
+	for _, o := range tx.Outputs {
+		if o.LockingScript == nil {
+			continue
+		}
+		fee += m.OutputFees[o.LockingScript.ScriptType()]
+	}
+
+	return fee, nil
+}
@@ -0,0 +1,144 @@
+package bt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeModel_Change(t *testing.T) {
+	t.Parallel()
+
+	// the tx with change is 226 bytes once signed
+	tests := map[string]struct {
+		model     bt.FeeModel
+		expChange uint64
+		err       error
+	}{
+		"fee quote": {
+			model:     bt.NewFeeQuote(),
+			expChange: 10000 - 1000 - 113,
+		},
+		"minimum fee plus per kb": {
+			model:     bt.MinimumFeeModel{MinimumFee: 100, SatoshisPerKB: 500},
+			expChange: 10000 - 1000 - 100 - 113,
+		},
+		"priced by output type": {
+			model: bt.OutputTypeFeeModel{
+				Base: bt.MinimumFeeModel{SatoshisPerKB: 1000},
+				OutputFees: map[string]uint64{
+					bscript.ScriptTypePubKeyHash: 10,
+					bscript.ScriptTypeNullData:   50,
+				},
+			},
+			expChange: 10000 - 1000 - 226 - 20,
+		},
+		"no fee model": {
+			err: bt.ErrFeeModelNotInit,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := bt.NewTx()
+			assert.NoError(t, tx.From("07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b", 0, "76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac", 10000))
+			assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+
+			err := tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", test.model)
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 2, tx.OutputCount())
+			assert.Equal(t, test.expChange, tx.Outputs[1].Satoshis)
+
+			ok, err := tx.EstimateIsFeePaidEnough(test.model)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			// a satoshi less fee is not enough
+			tx.Outputs[1].Satoshis++
+			ok, err = tx.EstimateIsFeePaidEnough(test.model)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFeeModel_Fund(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		model     bt.FeeModel
+		expInputs int
+	}{
+		"fee quote": {
+			model:     bt.NewFeeQuote(),
+			expInputs: 1,
+		},
+		"minimum fee plus per kb": {
+			model:     bt.MinimumFeeModel{MinimumFee: 100, SatoshisPerKB: 500},
+			expInputs: 2,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := bt.NewTx()
+			assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+
+			vout := uint32(0)
+			err := tx.Fund(context.Background(), test.model, func(ctx context.Context, deficit uint64) ([]*bt.UTXO, error) {
+				utxo := &bt.UTXO{
+					TxID:     make([]byte, 32),
+					Vout:     vout,
+					Satoshis: 1150,
+				}
+				utxo.LockingScript, _ = bscript.NewFromHexString("76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac")
+				vout++
+				return []*bt.UTXO{utxo}, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, test.expInputs, tx.InputCount())
+
+			ok, err := tx.EstimateIsFeePaidEnough(test.model)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestFeeCalculation_Change(t *testing.T) {
+	t.Parallel()
+
+	newTx := func() *bt.Tx {
+		tx := bt.NewTx()
+		assert.NoError(t, tx.From("07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b", 0, "76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac", 10000))
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+		return tx
+	}
+	fq := bt.NewFeeQuote()
+	fq.AddQuote(bt.FeeTypeStandard, &bt.Fee{FeeType: bt.FeeTypeStandard, MiningFee: bt.FeeUnit{Satoshis: 1, Bytes: 1000}})
+
+	// the 226 byte tx pays 0.226 sats truncated to nothing, below the 1 satoshi
+	// a node requires of any tx at a non-zero rate
+	tx := newTx()
+	assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", fq))
+	assert.Equal(t, uint64(10000-1000), tx.Outputs[1].Satoshis)
+
+	nodeFq := bt.NewFeeQuote()
+	nodeFq.AddQuote(bt.FeeTypeStandard, &bt.Fee{FeeType: bt.FeeTypeStandard, MiningFee: bt.FeeUnit{Satoshis: 1, Bytes: 1000}})
+	nodeFq.UpdateFeeCalculation(bt.FeeCalculationNode)
+	ok, err := tx.EstimateIsFeePaidEnough(nodeFq)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	tx = newTx()
+	assert.NoError(t, tx.ChangeToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", nodeFq))
+	assert.Equal(t, uint64(10000-1000-1), tx.Outputs[1].Satoshis)
+	ok, err = tx.EstimateIsFeePaidEnough(nodeFq)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -3,6 +3,7 @@ package bt
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -102,6 +103,141 @@ func (f *FeeQuotes) UpdateMinerFees(minerName string, feeType FeeType, fee *Fee)
 	return m.AddQuote(feeType, fee), nil
 }
 
+// ValidMiners will return the names of the miners, in name order, whose quotes have
+// not expired at the time now.
+func (f *FeeQuotes) ValidMiners(now time.Time) []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	miners := make([]string, 0, len(f.quotes))
+	for name, q := range f.quotes {
+		if q.Expiry().Before(now) {
+			continue
+		}
+		miners = append(miners, name)
+	}
+	sort.Strings(miners)
+	return miners
+}
+
+// MinerFee is the fee a tx would pay to a miner, as returned by FeeQuotes.Rank.
+type MinerFee struct {
+	Miner string
+	Quote *FeeQuote
+	Fee   uint64
+}
+
+// Rank will return the miners whose quotes have not expired at the time now, ranked
+// by the total fee the tx would pay them, cheapest first. Miners charging the same
+// fee are ranked by name.
+//
+// The fee is estimated for the tx once signed, as with EstimateFeesPaid.
+func (f *FeeQuotes) Rank(tx *Tx, now time.Time) ([]MinerFee, error) {
+	if f == nil {
+		return nil, ErrFeeQuotesNotInit
+	}
+	size, err := tx.EstimateSizeWithTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	ranked := make([]MinerFee, 0, len(f.quotes))
+	for name, q := range f.quotes {
+		if q.Expiry().Before(now) {
+			continue
+		}
+		fee, err := q.ComputeFee(tx, size)
+		if err != nil {
+			return nil, fmt.Errorf("miner '%s': %w", name, err)
+		}
+		ranked = append(ranked, MinerFee{Miner: name, Quote: q, Fee: fee})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Fee != ranked[j].Fee {
+			return ranked[i].Fee < ranked[j].Fee
+		}
+		return ranked[i].Miner < ranked[j].Miner
+	})
+	return ranked, nil
+}
+
+// Cheapest will return the miner whose quote has not expired that the tx would pay
+// the lowest total fee to. If all quotes have expired an ErrNoValidQuotes error is
+// returned.
+func (f *FeeQuotes) Cheapest(tx *Tx) (*MinerFee, error) {
+	ranked, err := f.Rank(tx, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if len(ranked) == 0 {
+		return nil, ErrNoValidQuotes
+	}
+	return &ranked[0], nil
+}
+
+// minerQuote is the JSON form of a miner's quote within FeeQuotes.
+type minerQuote struct {
+	Expiry         time.Time      `json:"expiry"`
+	Fees           *FeeQuote      `json:"fees"`
+	Policy         *Policy        `json:"policy,omitempty"`
+	FeeCalculation FeeCalculation `json:"feeCalculation,omitempty"`
+}
+
+// MarshalJSON will convert the FeeQuotes to a json object keyed by miner name, with
+// the fees of each miner, in the format of FeeQuote.MarshalJSON, and their expiry:
+//
+//	{
+//	  "taal": {
+//	    "expiry": "2021-11-13T07:48:46Z",
+//	    "fees": {
+//	      "data": {...},
+//	      "standard": {...}
+//	    }
+//	  }
+//	}
+//
+// The policy and fee calculation of quotes which have them are also included.
+func (f *FeeQuotes) MarshalJSON() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	quotes := make(map[string]*minerQuote, len(f.quotes))
+	for name, q := range f.quotes {
+		quotes[name] = &minerQuote{
+			Expiry:         q.Expiry(),
+			Fees:           q,
+			Policy:         q.Policy(),
+			FeeCalculation: q.FeeCalculation(),
+		}
+	}
+	return json.Marshal(quotes)
+}
+
+// UnmarshalJSON will convert a json encoded FeeQuotes back into a FeeQuotes type,
+// replacing any quotes already stored. The expected JSON format is shown above in
+// the MarshalJSON function.
+func (f *FeeQuotes) UnmarshalJSON(body []byte) error {
+	var quotes map[string]*minerQuote
+	if err := json.Unmarshal(body, &quotes); err != nil {
+		return err
+	}
+	fqs := make(map[string]*FeeQuote, len(quotes))
+	for name, q := range quotes {
+		if q == nil || q.Fees == nil {
+			return fmt.Errorf("miner '%s': %w", name, ErrMinerNoQuotes)
+		}
+		q.Fees.expiryTime = q.Expiry
+		q.Fees.policy = q.Policy
+		q.Fees.calc = q.FeeCalculation
+		fqs[name] = q.Fees
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.quotes = fqs
+	return nil
+}
+
 // FeeQuote contains a thread safe map of fees for standard and data
 // fees as well as an expiry time for a specific miner.
 //
@@ -115,11 +251,15 @@ func (f *FeeQuotes) UpdateMinerFees(minerName string, feeType FeeType, fee *Fee)
 // NewFeeQuote() should be called to get a new instance of a FeeQuote.
 //
 // When expiry expires ie Expired() == true then you should fetch
-// new quotes from a MAPI server and call AddQuote with the fee information.
+// new quotes from a MAPI server and call AddQuote with the fee information,
+// or decode the response with NewFeeQuoteFromMAPI, or NewFeeQuoteFromARCPolicy
+// for an ARC server.
 type FeeQuote struct {
 	mu         sync.RWMutex
 	fees       map[FeeType]*Fee
 	expiryTime time.Time
+	policy     *Policy
+	calc       FeeCalculation
 }
 
 // NewFeeQuote will set up and return a new FeeQuotes struct which
@@ -216,6 +356,38 @@ func (f *FeeQuote) UpdateExpiry(exp time.Time) {
 	f.expiryTime = exp
 }
 
+// Policy will return the policy limits of the miner the `bt.FeeQuote` is from, or
+// nil if they are not known.
+func (f *FeeQuote) Policy() *Policy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.policy
+}
+
+// UpdatePolicy will update the policy limits of the miner the `bt.FeeQuote` is from.
+func (f *FeeQuote) UpdatePolicy(p *Policy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.policy = p
+}
+
+// FeeCalculation will return how the fees of the `bt.FeeQuote` are calculated from
+// its fee units, FeeCalculationTruncate unless updated.
+func (f *FeeQuote) FeeCalculation() FeeCalculation {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.calc
+}
+
+// UpdateFeeCalculation will update how the fees of the `bt.FeeQuote` are calculated
+// from its fee units. Use FeeCalculationNode to calculate fees exactly as a node
+// validating them would.
+func (f *FeeQuote) UpdateFeeCalculation(c FeeCalculation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calc = c
+}
+
 // Expired will return true if the expiry time is before UTC now, this
 // means we need to fetch fresh quotes from a MAPI server.
 func (f *FeeQuote) Expired() bool {
@@ -278,6 +450,78 @@ type FeeUnit struct {
 	Bytes    int `json:"bytes"`    // Number of bytes that the Fee covers
 }
 
+// FeeCalculation is how the fee for a number of bytes is calculated from a FeeUnit.
+type FeeCalculation int
+
+const (
+	// FeeCalculationTruncate calculates the fee as bytes * Satoshis / Bytes,
+	// rounded down. This is the default.
+	FeeCalculationTruncate FeeCalculation = iota
+
+	// FeeCalculationNode calculates the fee as a node does, mirroring
+	// CFeeRate::GetFee. The fee unit is first converted to an integer rate in
+	// satoshis per 1000 bytes, rounded down, and the fee is then bytes * rate / 1000,
+	// also rounded down, except that a non-empty tx at a non-zero rate whose fee
+	// rounds down to nothing pays 1 satoshi. A tx is priced on its total size at
+	// once, rather than its standard and data bytes being rounded separately.
+	FeeCalculationNode
+)
+
+// MarshalText will encode the fee calculation as "truncate" or "node".
+func (c FeeCalculation) MarshalText() ([]byte, error) {
+	switch c {
+	case FeeCalculationTruncate:
+		return []byte("truncate"), nil
+	case FeeCalculationNode:
+		return []byte("node"), nil
+	}
+	return nil, fmt.Errorf("%w %d", ErrUnknownFeeCalculation, c)
+}
+
+// UnmarshalText will decode a fee calculation encoded by MarshalText.
+func (c *FeeCalculation) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "truncate":
+		*c = FeeCalculationTruncate
+	case "node":
+		*c = FeeCalculationNode
+	default:
+		return fmt.Errorf("%w '%s'", ErrUnknownFeeCalculation, text)
+	}
+	return nil
+}
+
+// Fee returns the fee in satoshis for n bytes at the rate of the fee unit.
+func (c FeeCalculation) Fee(u FeeUnit, n uint64) uint64 {
+	if c != FeeCalculationNode {
+		return n * uint64(u.Satoshis) / uint64(u.Bytes)
+	}
+
+	return nodeFee(n*feePerK(u), n)
+}
+
+// feePerK returns the rate of the fee unit in satoshis per 1000 bytes, rounded
+// down, as the CFeeRate constructor of a node does.
+func feePerK(u FeeUnit) uint64 {
+	if u.Bytes <= 0 {
+		return 0
+	}
+
+	return uint64(u.Satoshis) * 1000 / uint64(u.Bytes)
+}
+
+// nodeFee returns the fee of n bytes costing milliSats thousandths of a satoshi,
+// rounded down as CFeeRate::GetFee does, with its minimum of 1 satoshi for any
+// bytes at a non-zero rate.
+func nodeFee(milliSats, n uint64) uint64 {
+	fee := milliSats / 1000
+	if fee == 0 && n > 0 && milliSats > 0 {
+		fee = 1
+	}
+
+	return fee
+}
+
 // Fee displays the MiningFee as well as the RelayFee for a specific
 // FeeType, for example 'standard' or 'data'
 // see https://github.com/bitcoin-sv-specs/brfc-misc/tree/master/feespec
@@ -635,4 +635,246 @@ func TestFeeQuote_MarshalUnmarshalJSON(t *testing.T) {
 			assert.Equal(t, test.quote, quote)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestFeeCalculation_Fee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("node vectors", func(t *testing.T) {
+		// GetFeeTest of the node's amount_tests.cpp, for CFeeRate(perK).GetFee(bytes).
+		tests := []struct {
+			perK  int
+			bytes uint64
+			exp   uint64
+		}{
+			{perK: 0, bytes: 0, exp: 0},
+			{perK: 0, bytes: 100000, exp: 0},
+
+			{perK: 1000, bytes: 0, exp: 0},
+			{perK: 1000, bytes: 1, exp: 1},
+			{perK: 1000, bytes: 121, exp: 121},
+			{perK: 1000, bytes: 999, exp: 999},
+			{perK: 1000, bytes: 1000, exp: 1000},
+			{perK: 1000, bytes: 9000, exp: 9000},
+
+			{perK: 123, bytes: 0, exp: 0},
+			{perK: 123, bytes: 8, exp: 1},
+			{perK: 123, bytes: 9, exp: 1},
+			{perK: 123, bytes: 121, exp: 14},
+			{perK: 123, bytes: 122, exp: 15},
+			{perK: 123, bytes: 999, exp: 122},
+			{perK: 123, bytes: 1000, exp: 123},
+			{perK: 123, bytes: 9000, exp: 1107},
+		}
+		for _, test := range tests {
+			assert.Equal(t, test.exp, FeeCalculationNode.Fee(FeeUnit{Satoshis: test.perK, Bytes: 1000}, test.bytes),
+				"CFeeRate(%d).GetFee(%d)", test.perK, test.bytes)
+		}
+	})
+
+	t.Run("node rate per kb", func(t *testing.T) {
+		// CFeeRate(nFeePaid, nBytes) of the node's amount_tests.cpp, which can only
+		// resolve whole satoshis per kB.
+		tests := []struct {
+			unit FeeUnit
+			perK uint64
+		}{
+			{unit: FeeUnit{Satoshis: 0, Bytes: 1000}, perK: 0},
+			{unit: FeeUnit{Satoshis: 1, Bytes: 1000}, perK: 1},
+			{unit: FeeUnit{Satoshis: 1, Bytes: 1001}, perK: 0},
+			{unit: FeeUnit{Satoshis: 2, Bytes: 1001}, perK: 1},
+			{unit: FeeUnit{Satoshis: 26, Bytes: 789}, perK: 32},
+			{unit: FeeUnit{Satoshis: 27, Bytes: 789}, perK: 34},
+		}
+		for _, test := range tests {
+			assert.Equal(t, test.perK, FeeCalculationNode.Fee(test.unit, 1000), "%+v", test.unit)
+		}
+	})
+
+	tests := map[string]struct {
+		unit     FeeUnit
+		bytes    uint64
+		expTrunc uint64
+		expNode  uint64
+	}{
+		"exact rate": {
+			unit:     FeeUnit{Satoshis: 5, Bytes: 10},
+			bytes:    226,
+			expTrunc: 113,
+			expNode:  113,
+		},
+		"fractional fee is rounded down": {
+			unit:     FeeUnit{Satoshis: 500, Bytes: 1000},
+			bytes:    227,
+			expTrunc: 113,
+			expNode:  113,
+		},
+		"fee below a satoshi is a satoshi": {
+			unit:     FeeUnit{Satoshis: 1, Bytes: 1000},
+			bytes:    226,
+			expTrunc: 0,
+			expNode:  1,
+		},
+		"rate per kb rounded down can be lower": {
+			unit:     FeeUnit{Satoshis: 1, Bytes: 3},
+			bytes:    3000,
+			expTrunc: 1000,
+			expNode:  999,
+		},
+		"rate below a satoshi per kb is free": {
+			unit:     FeeUnit{Satoshis: 1, Bytes: 2000},
+			bytes:    500,
+			expTrunc: 0,
+			expNode:  0,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expTrunc, FeeCalculationTruncate.Fee(test.unit, test.bytes))
+			assert.Equal(t, test.expNode, FeeCalculationNode.Fee(test.unit, test.bytes))
+		})
+	}
+}
+
+func TestFeeQuote_UpdateFeeCalculation(t *testing.T) {
+	t.Parallel()
+
+	fq := NewFeeQuote()
+	assert.Equal(t, FeeCalculationTruncate, fq.FeeCalculation())
+
+	fq.AddQuote(FeeTypeStandard, &Fee{FeeType: FeeTypeStandard, MiningFee: FeeUnit{Satoshis: 50, Bytes: 1000}})
+	fees, err := fq.txFees(&TxSize{TotalBytes: 300, TotalStdBytes: 191, TotalDataBytes: 109})
+	assert.NoError(t, err)
+	assert.Equal(t, &TxFees{TotalFeePaid: 63, StdFeePaid: 9, DataFeePaid: 54}, fees)
+
+	// 9.55 sats for the standard bytes and 54.5 for the data bytes are 64.05 sats
+	// for the tx as a whole, rather than 9 and 54 rounded down separately.
+	fq.UpdateFeeCalculation(FeeCalculationNode)
+	assert.Equal(t, FeeCalculationNode, fq.FeeCalculation())
+	fees, err = fq.txFees(&TxSize{TotalBytes: 300, TotalStdBytes: 191, TotalDataBytes: 109})
+	assert.NoError(t, err)
+	assert.Equal(t, &TxFees{TotalFeePaid: 64, StdFeePaid: 9, DataFeePaid: 55}, fees)
+}
+
+func testQuote(satoshis, bytes int, expiry time.Time) *FeeQuote {
+	fq := NewFeeQuote()
+	for _, ft := range []FeeType{FeeTypeStandard, FeeTypeData} {
+		fq.AddQuote(ft, &Fee{
+			FeeType:   ft,
+			MiningFee: FeeUnit{Satoshis: satoshis, Bytes: bytes},
+			RelayFee:  FeeUnit{Satoshis: satoshis, Bytes: bytes},
+		})
+	}
+	fq.UpdateExpiry(expiry)
+	return fq
+}
+
+func TestFeeQuotes_Rank(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now().UTC()
+	gorilla := testQuote(1, 1000, now.Add(time.Hour))
+	gorilla.UpdateFeeCalculation(FeeCalculationNode)
+	fqs := NewFeeQuotes("taal").
+		AddMiner("taal", testQuote(5, 10, now.Add(time.Hour))).
+		AddMiner("mempool", testQuote(50, 100, now.Add(time.Hour))).
+		AddMiner("gorilla", gorilla).
+		AddMiner("cheapo", testQuote(0, 1000, now.Add(-time.Hour)))
+
+	// the tx is estimated at 192 bytes once signed
+	tx := NewTx()
+	assert.NoError(t, tx.From("07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b", 0, "76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac", 10000))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+
+	t.Run("valid miners", func(t *testing.T) {
+		assert.Equal(t, []string{"gorilla", "mempool", "taal"}, fqs.ValidMiners(now))
+		assert.Equal(t, []string{"cheapo", "gorilla", "mempool", "taal"}, fqs.ValidMiners(now.Add(-2*time.Hour)))
+		assert.Empty(t, fqs.ValidMiners(now.Add(2*time.Hour)))
+	})
+
+	t.Run("rank", func(t *testing.T) {
+		ranked, err := fqs.Rank(tx, now)
+		assert.NoError(t, err)
+		miners := make([]string, 0, len(ranked))
+		fees := make([]uint64, 0, len(ranked))
+		for _, r := range ranked {
+			miners = append(miners, r.Miner)
+			fees = append(fees, r.Fee)
+		}
+		assert.Equal(t, []string{"gorilla", "mempool", "taal"}, miners)
+		assert.Equal(t, []uint64{1, 96, 96}, fees)
+		assert.Equal(t, gorilla, ranked[0].Quote)
+
+		ranked, err = fqs.Rank(tx, now.Add(-2*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, "cheapo", ranked[0].Miner)
+		assert.Equal(t, uint64(0), ranked[0].Fee)
+	})
+
+	t.Run("cheapest", func(t *testing.T) {
+		cheapest, err := fqs.Cheapest(tx)
+		assert.NoError(t, err)
+		assert.Equal(t, "gorilla", cheapest.Miner)
+		assert.Equal(t, uint64(1), cheapest.Fee)
+
+		_, err = NewFeeQuotes("expired").Cheapest(tx)
+		assert.ErrorIs(t, err, ErrNoValidQuotes)
+	})
+}
+
+func TestFeeQuotes_MarshalUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	expiry := time.Date(2021, 11, 13, 7, 48, 46, 0, time.UTC)
+	arc := testQuote(1, 1000, expiry)
+	arc.UpdateFeeCalculation(FeeCalculationNode)
+	arc.UpdatePolicy(&Policy{MaxTxSize: 100000000})
+	fqs := NewFeeQuotes("taal").
+		AddMiner("taal", testQuote(5, 10, expiry)).
+		AddMiner("arc", arc)
+
+	bb, err := json.Marshal(fqs)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"arc": {
+			"expiry": "2021-11-13T07:48:46Z",
+			"fees": {
+				"data": {"miningFee": {"satoshis": 1, "bytes": 1000}, "relayFee": {"satoshis": 1, "bytes": 1000}},
+				"standard": {"miningFee": {"satoshis": 1, "bytes": 1000}, "relayFee": {"satoshis": 1, "bytes": 1000}}
+			},
+			"policy": {"maxtxsizepolicy": 100000000},
+			"feeCalculation": "node"
+		},
+		"taal": {
+			"expiry": "2021-11-13T07:48:46Z",
+			"fees": {
+				"data": {"miningFee": {"satoshis": 5, "bytes": 10}, "relayFee": {"satoshis": 5, "bytes": 10}},
+				"standard": {"miningFee": {"satoshis": 5, "bytes": 10}, "relayFee": {"satoshis": 5, "bytes": 10}}
+			}
+		}
+	}`, string(bb))
+
+	var decoded FeeQuotes
+	assert.NoError(t, json.Unmarshal(bb, &decoded))
+	for _, miner := range []string{"arc", "taal"} {
+		exp, err := fqs.Quote(miner)
+		assert.NoError(t, err)
+		q, err := decoded.Quote(miner)
+		assert.NoError(t, err)
+		assert.Equal(t, exp.Expiry(), q.Expiry())
+		assert.Equal(t, exp.Policy(), q.Policy())
+		assert.Equal(t, exp.FeeCalculation(), q.FeeCalculation())
+		for _, ft := range []FeeType{FeeTypeStandard, FeeTypeData} {
+			expFee, err := exp.Fee(ft)
+			assert.NoError(t, err)
+			fee, err := q.Fee(ft)
+			assert.NoError(t, err)
+			assert.Equal(t, expFee, fee)
+		}
+	}
+
+	err = json.Unmarshal([]byte(`{"taal": {"expiry": "2021-11-13T07:48:46Z", "feeCalculation": "exact"}}`), &decoded)
+	assert.ErrorIs(t, err, ErrUnknownFeeCalculation)
+	err = json.Unmarshal([]byte(`{"taal": {"expiry": "2021-11-13T07:48:46Z"}}`), &decoded)
+	assert.ErrorIs(t, err, ErrMinerNoQuotes)
+}
@@ -0,0 +1,230 @@
+package bt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/crypto"
+)
+
+// Policy contains the policy limits of a miner, as returned by MAPI alongside its
+// fees in a policy quote, and by ARC from its policy endpoint. Limits which the
+// miner did not return are zero.
+//
+// See https://github.com/bitcoin-sv-specs/brfc-merchantapi#get-policy-quote
+type Policy struct {
+	MaxTxSize                  uint64 `json:"maxtxsizepolicy,omitempty"`
+	MaxScriptSize              uint64 `json:"maxscriptsizepolicy,omitempty"`
+	MaxTxSigOpsCount           uint64 `json:"maxtxsigopscountspolicy,omitempty"`
+	MaxScriptNumLength         uint64 `json:"maxscriptnumlengthpolicy,omitempty"`
+	MaxStackMemoryUsage        uint64 `json:"maxstackmemoryusagepolicy,omitempty"`
+	DataCarrierSize            uint64 `json:"datacarriersize,omitempty"`
+	LimitAncestorCount         uint64 `json:"limitancestorcount,omitempty"`
+	LimitCPFPGroupMembersCount uint64 `json:"limitcpfpgroupmemberscount,omitempty"`
+}
+
+// MAPIOptionFunc for setting options when decoding a MAPI response.
+type MAPIOptionFunc func(o *mapiOpts)
+
+type mapiOpts struct {
+	minerPubKey []byte
+}
+
+// WithMinerPublicKey configure the public key of the miner the MAPI response is
+// expected from. The response must then be signed with this key.
+func WithMinerPublicKey(pubKey []byte) MAPIOptionFunc {
+	return func(o *mapiOpts) {
+		o.minerPubKey = pubKey
+	}
+}
+
+// jsonEnvelope is the JSONEnvelope MAPI wraps its responses in.
+// See https://github.com/bitcoin-sv-specs/brfc-misc/tree/master/jsonenvelope
+type jsonEnvelope struct {
+	Payload   string  `json:"payload"`
+	Signature *string `json:"signature"`
+	PublicKey *string `json:"publicKey"`
+	Encoding  string  `json:"encoding"`
+	MimeType  string  `json:"mimetype"`
+}
+
+type mapiFee struct {
+	FeeType   FeeType `json:"feeType"`
+	MiningFee FeeUnit `json:"miningFee"`
+	RelayFee  FeeUnit `json:"relayFee"`
+}
+
+type mapiFeeQuote struct {
+	ExpiryTime time.Time  `json:"expiryTime"`
+	Fees       []*mapiFee `json:"fees"`
+	Policies   *Policy    `json:"policies"`
+}
+
+type arcPolicyResponse struct {
+	Policy struct {
+		Policy
+		MiningFee *FeeUnit `json:"miningFee"`
+	} `json:"policy"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewFeeQuoteFromMAPI will decode the response of a MAPI feeQuote or policyQuote
+// request into a new `bt.FeeQuote`, with the fees, expiry time and, for a policy
+// quote, the policy limits returned.
+//
+// The response is a JSONEnvelope, and if it is signed, the signature is verified
+// against the public key in the envelope, or ErrInvalidSignature returned. As
+// anyone can sign an envelope, use WithMinerPublicKey to ensure the response is
+// signed by the expected miner:
+//
+//	fq, err := bt.NewFeeQuoteFromMAPI(body, bt.WithMinerPublicKey(minerID))
+//	if err != nil {}
+//
+// If the fee type supplied is unknown an ErrUnknownFeeType will be returned.
+func NewFeeQuoteFromMAPI(body []byte, opts ...MAPIOptionFunc) (*FeeQuote, error) {
+	o := &mapiOpts{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var env jsonEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+	if err := env.verify(o.minerPubKey); err != nil {
+		return nil, err
+	}
+
+	var q mapiFeeQuote
+	if err := json.Unmarshal([]byte(env.Payload), &q); err != nil {
+		return nil, err
+	}
+
+	fq := &FeeQuote{
+		fees:       map[FeeType]*Fee{},
+		expiryTime: q.ExpiryTime,
+		policy:     q.Policies,
+	}
+	for _, f := range q.Fees {
+		if f.FeeType != FeeTypeData && f.FeeType != FeeTypeStandard {
+			return nil, fmt.Errorf("%w '%s'", ErrUnknownFeeType, f.FeeType)
+		}
+		if err := validFeeUnit(f.MiningFee); err != nil {
+			return nil, fmt.Errorf("%s mining fee: %w", f.FeeType, err)
+		}
+		// the relay fee is not used to calculate fees, so may be omitted
+		if f.RelayFee != (FeeUnit{}) {
+			if err := validFeeUnit(f.RelayFee); err != nil {
+				return nil, fmt.Errorf("%s relay fee: %w", f.FeeType, err)
+			}
+		}
+		fq.AddQuote(f.FeeType, &Fee{
+			FeeType:   f.FeeType,
+			MiningFee: f.MiningFee,
+			RelayFee:  f.RelayFee,
+		})
+	}
+
+	return fq, nil
+}
+
+// NewFeeQuoteFromARCPolicy will decode the response of an ARC /v1/policy request
+// into a new `bt.FeeQuote`, with the policy limits returned.
+//
+// ARC returns a single mining fee, which is used for both the standard and data
+// fee types, and as the relay fee. ARC does not return an expiry, so the quote
+// expires validFor after the timestamp of the response. As ARC validates fees as
+// a node does, fees of the quote are calculated with FeeCalculationNode.
+func NewFeeQuoteFromARCPolicy(body []byte, validFor time.Duration) (*FeeQuote, error) {
+	var resp arcPolicyResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Policy.MiningFee == nil {
+		return nil, ErrFeeTypeNotFound
+	}
+	if err := validFeeUnit(*resp.Policy.MiningFee); err != nil {
+		return nil, fmt.Errorf("mining fee: %w", err)
+	}
+
+	policy := resp.Policy.Policy
+	fq := &FeeQuote{
+		fees:       map[FeeType]*Fee{},
+		expiryTime: resp.Timestamp.Add(validFor),
+		policy:     &policy,
+		calc:       FeeCalculationNode,
+	}
+	for _, ft := range []FeeType{FeeTypeStandard, FeeTypeData} {
+		fq.AddQuote(ft, &Fee{
+			FeeType:   ft,
+			MiningFee: *resp.Policy.MiningFee,
+			RelayFee:  *resp.Policy.MiningFee,
+		})
+	}
+
+	return fq, nil
+}
+
+// validFeeUnit returns ErrInvalidFeeUnit if u, as received from a miner, covers
+// no bytes, which would divide by zero when calculating a fee, or charges negative
+// satoshis.
+func validFeeUnit(u FeeUnit) error {
+	if u.Bytes <= 0 || u.Satoshis < 0 {
+		return fmt.Errorf("%w, got %d satoshis for %d bytes", ErrInvalidFeeUnit, u.Satoshis, u.Bytes)
+	}
+
+	return nil
+}
+
+// verify checks the signature of the envelope, if it is signed, which must be by
+// minerPubKey if provided.
+func (e *jsonEnvelope) verify(minerPubKey []byte) error {
+	if e.Encoding != "" && !strings.EqualFold(e.Encoding, "utf-8") {
+		return fmt.Errorf("%w '%s'", ErrUnsupportedEncoding, e.Encoding)
+	}
+
+	if e.Signature == nil || *e.Signature == "" {
+		if minerPubKey != nil {
+			return ErrEnvelopeNotSigned
+		}
+		return nil
+	}
+
+	pubKey := minerPubKey
+	if e.PublicKey != nil && *e.PublicKey != "" {
+		pk, err := hex.DecodeString(*e.PublicKey)
+		if err != nil {
+			return err
+		}
+		if minerPubKey != nil && !bytes.Equal(pk, minerPubKey) {
+			return ErrUnexpectedMiner
+		}
+		pubKey = pk
+	}
+	if pubKey == nil {
+		return ErrInvalidSignature
+	}
+
+	pk, err := bec.ParsePubKey(pubKey, bec.S256())
+	if err != nil {
+		return err
+	}
+	sigBytes, err := hex.DecodeString(*e.Signature)
+	if err != nil {
+		return err
+	}
+	sig, err := bec.ParseDERSignature(sigBytes, bec.S256())
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if !sig.Verify(crypto.Sha256([]byte(e.Payload)), pk) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
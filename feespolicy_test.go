@@ -0,0 +1,167 @@
+package bt_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const mapiFeeQuotePayload = `{"apiVersion":"1.4.0","timestamp":"2021-11-13T07:38:46.1367659Z","expiryTime":"2021-11-13T07:48:46.1367659Z","minerId":"030d1fe5c1b560efe196ba40540ce9017c20daa9504c4c4cec6184fc702d9f274e","currentHighestBlockHash":"0000000000000000058ac7bfeb4d2ba6e0d7bd2cf47eaf4ee81d4f88c6d8c63d","currentHighestBlockHeight":713467,"fees":[{"feeType":"standard","miningFee":{"satoshis":500,"bytes":1000},"relayFee":{"satoshis":250,"bytes":1000}},{"feeType":"data","miningFee":{"satoshis":250,"bytes":1000},"relayFee":{"satoshis":125,"bytes":1000}}],"policies":{"maxtxsizepolicy":99999,"datacarriersize":100000,"maxscriptsizepolicy":100000,"limitancestorcount":1000,"skipscriptflags":["MINIMALDATA"]}}`
+
+func envelope(t *testing.T, payload string, key *bec.PrivateKey) []byte {
+	t.Helper()
+
+	env := map[string]interface{}{
+		"payload":   payload,
+		"signature": nil,
+		"publicKey": nil,
+		"encoding":  "UTF-8",
+		"mimetype":  "application/json",
+	}
+	if key != nil {
+		sig, err := key.Sign(crypto.Sha256([]byte(payload)))
+		assert.NoError(t, err)
+		env["signature"] = hex.EncodeToString(sig.Serialise())
+		env["publicKey"] = hex.EncodeToString(key.PubKey().SerialiseCompressed())
+	}
+
+	bb, err := json.Marshal(env)
+	assert.NoError(t, err)
+	return bb
+}
+
+func TestNewFeeQuoteFromMAPI(t *testing.T) {
+	t.Parallel()
+
+	miner, err := bec.NewPrivateKey(bec.S256())
+	assert.NoError(t, err)
+	other, err := bec.NewPrivateKey(bec.S256())
+	assert.NoError(t, err)
+	minerPubKey := miner.PubKey().SerialiseCompressed()
+
+	t.Run("signed fee quote", func(t *testing.T) {
+		fq, err := bt.NewFeeQuoteFromMAPI(envelope(t, mapiFeeQuotePayload, miner), bt.WithMinerPublicKey(minerPubKey))
+		assert.NoError(t, err)
+
+		std, err := fq.Fee(bt.FeeTypeStandard)
+		assert.NoError(t, err)
+		assert.Equal(t, &bt.Fee{
+			FeeType:   bt.FeeTypeStandard,
+			MiningFee: bt.FeeUnit{Satoshis: 500, Bytes: 1000},
+			RelayFee:  bt.FeeUnit{Satoshis: 250, Bytes: 1000},
+		}, std)
+		data, err := fq.Fee(bt.FeeTypeData)
+		assert.NoError(t, err)
+		assert.Equal(t, bt.FeeUnit{Satoshis: 250, Bytes: 1000}, data.MiningFee)
+
+		assert.Equal(t, time.Date(2021, 11, 13, 7, 48, 46, 136765900, time.UTC), fq.Expiry())
+		assert.Equal(t, &bt.Policy{
+			MaxTxSize:          99999,
+			MaxScriptSize:      100000,
+			DataCarrierSize:    100000,
+			LimitAncestorCount: 1000,
+		}, fq.Policy())
+	})
+
+	t.Run("unsigned fee quote", func(t *testing.T) {
+		fq, err := bt.NewFeeQuoteFromMAPI(envelope(t, mapiFeeQuotePayload, nil))
+		assert.NoError(t, err)
+		assert.True(t, fq.Expired())
+
+		_, err = bt.NewFeeQuoteFromMAPI(envelope(t, mapiFeeQuotePayload, nil), bt.WithMinerPublicKey(minerPubKey))
+		assert.ErrorIs(t, err, bt.ErrEnvelopeNotSigned)
+	})
+
+	t.Run("fee quote without policies", func(t *testing.T) {
+		fq, err := bt.NewFeeQuoteFromMAPI(envelope(t, `{"expiryTime":"2021-11-13T07:48:46Z","fees":[{"feeType":"standard","miningFee":{"satoshis":1,"bytes":2}}]}`, miner))
+		assert.NoError(t, err)
+		assert.Nil(t, fq.Policy())
+
+		_, err = fq.Fee(bt.FeeTypeData)
+		assert.ErrorIs(t, err, bt.ErrFeeTypeNotFound)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		var env map[string]interface{}
+		assert.NoError(t, json.Unmarshal(envelope(t, mapiFeeQuotePayload, miner), &env))
+		env["payload"] = `{"fees":[{"feeType":"standard","miningFee":{"satoshis":0,"bytes":1000}}]}`
+		bb, err := json.Marshal(env)
+		assert.NoError(t, err)
+
+		_, err = bt.NewFeeQuoteFromMAPI(bb)
+		assert.ErrorIs(t, err, bt.ErrInvalidSignature)
+	})
+
+	t.Run("signed by another miner", func(t *testing.T) {
+		_, err := bt.NewFeeQuoteFromMAPI(envelope(t, mapiFeeQuotePayload, other), bt.WithMinerPublicKey(minerPubKey))
+		assert.ErrorIs(t, err, bt.ErrUnexpectedMiner)
+	})
+
+	t.Run("unknown fee type", func(t *testing.T) {
+		_, err := bt.NewFeeQuoteFromMAPI(envelope(t, `{"fees":[{"feeType":"premium","miningFee":{"satoshis":1,"bytes":1}}]}`, nil))
+		assert.ErrorIs(t, err, bt.ErrUnknownFeeType)
+	})
+
+	t.Run("invalid fee units", func(t *testing.T) {
+		for name, fees := range map[string]string{
+			"zero bytes":        `{"feeType":"standard","miningFee":{"satoshis":1,"bytes":0}}`,
+			"negative bytes":    `{"feeType":"standard","miningFee":{"satoshis":1,"bytes":-1000}}`,
+			"negative satoshis": `{"feeType":"data","miningFee":{"satoshis":-1,"bytes":1000}}`,
+			"invalid relay fee": `{"feeType":"standard","miningFee":{"satoshis":1,"bytes":1000},"relayFee":{"satoshis":1,"bytes":0}}`,
+		} {
+			_, err := bt.NewFeeQuoteFromMAPI(envelope(t, `{"fees":[`+fees+`]}`, nil))
+			assert.ErrorIs(t, err, bt.ErrInvalidFeeUnit, name)
+		}
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		_, err := bt.NewFeeQuoteFromMAPI([]byte(`{"payload":"e30=","encoding":"base64"}`))
+		assert.ErrorIs(t, err, bt.ErrUnsupportedEncoding)
+	})
+}
+
+func TestNewFeeQuoteFromARCPolicy(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{
+		"policy": {
+			"maxscriptsizepolicy": 100000000,
+			"maxtxsigopscountspolicy": 4294967295,
+			"maxtxsizepolicy": 100000000,
+			"miningFee": {"bytes": 1000, "satoshis": 1}
+		},
+		"timestamp": "2023-08-07T12:07:41.394Z"
+	}`)
+
+	fq, err := bt.NewFeeQuoteFromARCPolicy(body, 10*time.Minute)
+	assert.NoError(t, err)
+
+	for _, ft := range []bt.FeeType{bt.FeeTypeStandard, bt.FeeTypeData} {
+		fee, err := fq.Fee(ft)
+		assert.NoError(t, err)
+		assert.Equal(t, ft, fee.FeeType)
+		assert.Equal(t, bt.FeeUnit{Satoshis: 1, Bytes: 1000}, fee.MiningFee)
+		assert.Equal(t, bt.FeeUnit{Satoshis: 1, Bytes: 1000}, fee.RelayFee)
+	}
+	assert.Equal(t, time.Date(2023, 8, 7, 12, 17, 41, 394000000, time.UTC), fq.Expiry())
+	assert.Equal(t, bt.FeeCalculationNode, fq.FeeCalculation())
+	assert.Equal(t, &bt.Policy{
+		MaxTxSize:        100000000,
+		MaxScriptSize:    100000000,
+		MaxTxSigOpsCount: 4294967295,
+	}, fq.Policy())
+
+	_, err = bt.NewFeeQuoteFromARCPolicy([]byte(`{"policy":{}}`), time.Minute)
+	assert.ErrorIs(t, err, bt.ErrFeeTypeNotFound)
+
+	_, err = bt.NewFeeQuoteFromARCPolicy([]byte(`{"policy":{"miningFee":{"bytes":0,"satoshis":1}}}`), time.Minute)
+	assert.ErrorIs(t, err, bt.ErrInvalidFeeUnit)
+	_, err = bt.NewFeeQuoteFromARCPolicy([]byte(`{"policy":{"miningFee":{"bytes":1000,"satoshis":-5}}}`), time.Minute)
+	assert.ErrorIs(t, err, bt.ErrInvalidFeeUnit)
+}
@@ -8,7 +8,6 @@ import (
 	"io"
 
 	"github.com/libsv/go-bt/v2/bscript"
-	"github.com/pkg/errors"
 )
 
 /*
@@ -50,81 +49,57 @@ func (i *Input) ReadFromExtended(r io.Reader) (int64, error) {
 }
 
 func (i *Input) readFrom(r io.Reader, extended bool) (int64, error) {
+	d := &txDecoder{r: r}
+	err := i.decode(d, "", extended)
+
+	return d.n, err
+}
+
+func (i *Input) decode(d *txDecoder, field string, extended bool) error {
 	*i = Input{}
-	var bytesRead int64
 
 	previousTxID := make([]byte, 32)
-	n, err := io.ReadFull(r, previousTxID)
-	bytesRead += int64(n)
-	if err != nil {
-		return bytesRead, errors.Wrapf(err, "previousTxID(32): got %d bytes", n)
+	if err := d.readFull(previousTxID, joinField(field, "previous txid"), "previousTxID(32)"); err != nil {
+		return err
 	}
 
 	prevIndex := make([]byte, 4)
-	n, err = io.ReadFull(r, prevIndex)
-	bytesRead += int64(n)
-	if err != nil {
-		return bytesRead, errors.Wrapf(err, "previousTxID(4): got %d bytes", n)
-	}
-
-	var l VarInt
-	n64, err := l.ReadFrom(r)
-	bytesRead += n64
-	if err != nil {
-		return bytesRead, err
+	if err := d.readFull(prevIndex, joinField(field, "previous output index"), "previousTxID(4)"); err != nil {
+		return err
 	}
 
-	script := make([]byte, l)
-	n, err = io.ReadFull(r, script)
-	bytesRead += int64(n)
+	script, err := d.script(joinField(field, "unlocking script"), "script")
 	if err != nil {
-		return bytesRead, errors.Wrapf(err, "script(%d): got %d bytes", l, n)
+		return err
 	}
 
 	sequence := make([]byte, 4)
-	n, err = io.ReadFull(r, sequence)
-	bytesRead += int64(n)
-	if err != nil {
-		return bytesRead, errors.Wrapf(err, "sequence(4): got %d bytes", n)
+	if err = d.readFull(sequence, joinField(field, "sequence"), "sequence(4)"); err != nil {
+		return err
 	}
 
 	i.previousTxID = ReverseBytes(previousTxID)
 	i.PreviousTxOutIndex = binary.LittleEndian.Uint32(prevIndex)
-	i.UnlockingScript = bscript.NewFromBytes(script)
+	i.UnlockingScript = script
 	i.SequenceNumber = binary.LittleEndian.Uint32(sequence)
 
 	if extended {
 		prevSatoshis := make([]byte, 8)
-		var prevTxLockingScript bscript.Script
-
-		n, err = io.ReadFull(r, prevSatoshis)
-		bytesRead += int64(n)
-		if err != nil {
-			return bytesRead, errors.Wrapf(err, "prevSatoshis(8): got %d bytes", n)
+		if err = d.readFull(prevSatoshis, joinField(field, "previous satoshis"), "prevSatoshis(8)"); err != nil {
+			return err
 		}
 
 		// Read in the prevTxLockingScript
-		var scriptLen VarInt
-		n64, err := scriptLen.ReadFrom(r)
-		bytesRead += n64
+		prevTxLockingScript, err := d.script(joinField(field, "previous locking script"), "script")
 		if err != nil {
-			return bytesRead, err
+			return err
 		}
 
-		script := make([]byte, scriptLen)
-		n, err := io.ReadFull(r, script)
-		bytesRead += int64(n)
-		if err != nil {
-			return bytesRead, errors.Wrapf(err, "script(%d): got %d bytes", scriptLen.Length(), n)
-		}
-
-		prevTxLockingScript = *bscript.NewFromBytes(script)
-
 		i.PreviousTxSatoshis = binary.LittleEndian.Uint64(prevSatoshis)
-		i.PreviousTxScript = bscript.NewFromBytes(prevTxLockingScript)
+		i.PreviousTxScript = prevTxLockingScript
 	}
 
-	return bytesRead, nil
+	return nil
 }
 
 // PreviousTxIDAdd will add the supplied txID bytes to the Input,
@@ -157,6 +132,11 @@ func (i *Input) PreviousTxIDStr() string {
 	return hex.EncodeToString(i.previousTxID)
 }
 
+// Outpoint returns the outpoint spent by the input.
+func (i *Input) Outpoint() Outpoint {
+	return NewOutpoint(i.previousTxID, i.PreviousTxOutIndex)
+}
+
 // String implements the Stringer interface and returns a string
 // representation of a transaction input.
 func (i *Input) String() string {
@@ -0,0 +1,90 @@
+package keys
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+// BRC-42 (Type-42) key derivation lets two parties derive a child key of one of them,
+// unique to an invoice number, without revealing either private key:
+//
+//	shared secret  S = ECDH(senderPriv, recipientPub) = ECDH(recipientPriv, senderPub)
+//	scalar         h = HMAC-SHA256(key: S compressed, message: invoice number)
+//	child public   recipientPub + h*G
+//	child private  recipientPriv + h mod N
+//
+// See https://github.com/bitcoin-sv/BRCs/blob/master/key-derivation/0042.md
+
+// DeriveChildPublicKey derives the BRC-42 child public key of the recipient for the
+// invoice number, from the private key of the counterparty (usually the sender).
+func DeriveChildPublicKey(recipient *bec.PublicKey, counterparty *bec.PrivateKey, invoiceNumber string) (*bec.PublicKey, error) {
+	if recipient == nil || counterparty == nil {
+		return nil, ErrNoKey
+	}
+
+	h := brc42Scalar(counterparty, recipient, invoiceNumber)
+
+	curve := bec.S256()
+	hx, hy := curve.ScalarBaseMult(h)
+	x, y := curve.Add(recipient.X, recipient.Y, hx, hy)
+
+	return &bec.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+// DeriveChildPrivateKey derives the BRC-42 child private key for the invoice number,
+// from the public key of the counterparty (usually the sender).
+func DeriveChildPrivateKey(priv *bec.PrivateKey, counterparty *bec.PublicKey, invoiceNumber string) (*bec.PrivateKey, error) {
+	if priv == nil || counterparty == nil {
+		return nil, ErrNoKey
+	}
+
+	h := new(big.Int).SetBytes(brc42Scalar(priv, counterparty, invoiceNumber))
+	d := h.Add(h, priv.D)
+	d.Mod(d, bec.S256().N)
+	if d.Sign() == 0 {
+		return nil, ErrInvalidDerivedKey
+	}
+
+	b := make([]byte, 32)
+	d.FillBytes(b)
+	child, _ := bec.PrivKeyFromBytes(bec.S256(), b)
+
+	return child, nil
+}
+
+// DeriveChild derives the BRC-42 child of the key for the invoice number, from the public
+// key of the counterparty. The child has the same network and compression as the key.
+func (k *PrivateKey) DeriveChild(counterparty *bec.PublicKey, invoiceNumber string) (*PrivateKey, error) {
+	child, err := DeriveChildPrivateKey(k.PrivateKey, counterparty, invoiceNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{PrivateKey: child, Network: k.Network, Compressed: k.Compressed}, nil
+}
+
+// NewBRC42LockingScript builds a P2PKH locking script paying to the BRC-42 child public
+// key of the recipient for the invoice number, derived using the private key of the sender.
+func NewBRC42LockingScript(recipient *bec.PublicKey, sender *bec.PrivateKey, invoiceNumber string) (*bscript.Script, error) {
+	pub, err := DeriveChildPublicKey(recipient, sender, invoiceNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return bscript.NewP2PKHFromPubKeyEC(pub)
+}
+
+// brc42Scalar returns the HMAC of the invoice number keyed by the ECDH shared secret.
+func brc42Scalar(priv *bec.PrivateKey, pub *bec.PublicKey, invoiceNumber string) []byte {
+	curve := bec.S256()
+	sx, sy := curve.ScalarMult(pub.X, pub.Y, priv.D.Bytes())
+	secret := (&bec.PublicKey{Curve: curve, X: sx, Y: sy}).SerialiseCompressed()
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(invoiceNumber))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,82 @@
+package keys_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2/keys"
+	"github.com/libsv/go-bt/v2/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustPrivKey(t *testing.T, h string) *bec.PrivateKey {
+	b, err := hex.DecodeString(h)
+	assert.NoError(t, err)
+	pk, _ := bec.PrivKeyFromBytes(bec.S256(), b)
+	return pk
+}
+
+func mustPubKey(t *testing.T, h string) *bec.PublicKey {
+	b, err := hex.DecodeString(h)
+	assert.NoError(t, err)
+	pk, err := bec.ParsePubKey(b, bec.S256())
+	assert.NoError(t, err)
+	return pk
+}
+
+func TestDeriveChildPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	// Test vector from BRC-42.
+	child, err := keys.DeriveChildPrivateKey(
+		mustPrivKey(t, "6a1751169c111b4667a6539ee1be6b7cd9f6e9c8fe011a5f2fe31e03a15e0ede"),
+		mustPubKey(t, "033f9160df035156f1c48e75eae99914fa1a1546bec19781e8eddb900200bff9d1"),
+		"f3WCaUmnN9U=",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "761656715bbfa172f8f9f58f5af95d9d0dfd69014cfdcacc9a245a10ff8893ef", hex.EncodeToString(child.Serialise()))
+
+	_, err = keys.DeriveChildPrivateKey(nil, nil, "")
+	assert.ErrorIs(t, err, keys.ErrNoKey)
+}
+
+func TestDeriveChildPublicKey(t *testing.T) {
+	t.Parallel()
+
+	// Test vector from BRC-42.
+	child, err := keys.DeriveChildPublicKey(
+		mustPubKey(t, "02c0c1e1a1f7d247827d1bcf399f0ef2deef7695c322fd91a01a91378f101b6ffc"),
+		mustPrivKey(t, "583755110a8c059de5cd81b8a04e1be884c46083ade3f779c1e022f6f89da94c"),
+		"IBioA4D/OaE=",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "03c1bf5baadee39721ae8c9882b3cf324f0bf3b9eb3fc1b8af8089ca7a7c2e669f", hex.EncodeToString(child.SerialiseCompressed()))
+
+	_, err = keys.DeriveChildPublicKey(nil, nil, "")
+	assert.ErrorIs(t, err, keys.ErrNoKey)
+}
+
+func TestBRC42_SenderAndRecipientAgree(t *testing.T) {
+	t.Parallel()
+
+	sender, err := keys.NewPrivateKey(network.Mainnet)
+	assert.NoError(t, err)
+	recipient, err := keys.NewPrivateKey(network.Mainnet)
+	assert.NoError(t, err)
+
+	s, err := keys.NewBRC42LockingScript(recipient.PubKey(), sender.PrivateKey, "2-3241645161d8-invoice 1")
+	assert.NoError(t, err)
+
+	child, err := recipient.DeriveChild(sender.PubKey(), "2-3241645161d8-invoice 1")
+	assert.NoError(t, err)
+	assert.Equal(t, recipient.Network, child.Network)
+
+	exp, err := child.P2PKHLockingScript()
+	assert.NoError(t, err)
+	assert.Equal(t, exp, s)
+
+	other, err := recipient.DeriveChild(sender.PubKey(), "2-3241645161d8-invoice 2")
+	assert.NoError(t, err)
+	assert.NotEqual(t, child.Serialise(), other.Serialise())
+}
@@ -0,0 +1,11 @@
+package keys
+
+import "github.com/pkg/errors"
+
+// Sentinel errors raised by the package.
+var (
+	ErrNoNetwork         = errors.New("network not supplied")
+	ErrUnknownNetwork    = errors.New("wif is not for a known network")
+	ErrNoKey             = errors.New("key not supplied")
+	ErrInvalidDerivedKey = errors.New("derived key is invalid")
+)
@@ -0,0 +1,104 @@
+// Package keys provides private key handling tied to the network parameters
+// of this module, such as WIF import and export and address derivation.
+package keys
+
+import (
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bk/wif"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
+)
+
+// PrivateKey is a bec private key along with the network it is intended for and
+// whether its public key is serialised compressed, as recorded in its WIF.
+type PrivateKey struct {
+	*bec.PrivateKey
+	Network    *network.Params
+	Compressed bool
+}
+
+// NewPrivateKey generates a new random, compressed, private key for the network provided.
+func NewPrivateKey(net *network.Params) (*PrivateKey, error) {
+	if net == nil {
+		return nil, ErrNoNetwork
+	}
+
+	pk, err := bec.NewPrivateKey(bec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{PrivateKey: pk, Network: net, Compressed: true}, nil
+}
+
+// NewPrivateKeyFromBEC wraps a bec private key, with a compressed public key,
+// for the network provided.
+func NewPrivateKeyFromBEC(pk *bec.PrivateKey, net *network.Params) *PrivateKey {
+	return &PrivateKey{PrivateKey: pk, Network: net, Compressed: true}
+}
+
+// DecodeWIF decodes a private key from its Wallet Import Format string, detecting
+// the network and whether the public key is compressed. Test networks share a WIF
+// version byte, so a test network key is decoded with a Network of network.Testnet.
+func DecodeWIF(s string) (*PrivateKey, error) {
+	w, err := wif.DecodeWIF(s)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, net := range network.All {
+		if w.IsForNet(net.ChainParams()) {
+			return &PrivateKey{PrivateKey: w.PrivKey, Network: net, Compressed: w.CompressPubKey}, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// WIF returns the private key encoded in Wallet Import Format for its network.
+func (k *PrivateKey) WIF() (string, error) {
+	if k.Network == nil {
+		return "", ErrNoNetwork
+	}
+
+	w, err := wif.NewWIF(k.PrivateKey, k.Network.ChainParams(), k.Compressed)
+	if err != nil {
+		return "", err
+	}
+
+	return w.String(), nil
+}
+
+// PubKeyBytes returns the serialised public key, compressed or uncompressed
+// as specified by the key.
+func (k *PrivateKey) PubKeyBytes() []byte {
+	if k.Compressed {
+		return k.PubKey().SerialiseCompressed()
+	}
+
+	return k.PubKey().SerialiseUncompressed()
+}
+
+// PubKeyHash returns the HASH160 of the serialised public key.
+func (k *PrivateKey) PubKeyHash() []byte {
+	return crypto.Hash160(k.PubKeyBytes())
+}
+
+// Address returns the P2PKH address of the key for the network provided. If net
+// is nil, the network of the key is used.
+func (k *PrivateKey) Address(net *network.Params) (*bscript.Address, error) {
+	if net == nil {
+		net = k.Network
+	}
+	if net == nil {
+		return nil, ErrNoNetwork
+	}
+
+	return bscript.NewAddressFromPublicKeyHashForNetwork(k.PubKeyHash(), net)
+}
+
+// P2PKHLockingScript returns a P2PKH locking script paying to the key.
+func (k *PrivateKey) P2PKHLockingScript() (*bscript.Script, error) {
+	return bscript.NewP2PKHFromPubKeyHash(k.PubKeyHash())
+}
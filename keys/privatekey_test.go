@@ -0,0 +1,107 @@
+package keys_test
+
+import (
+	"testing"
+
+	"github.com/libsv/go-bt/v2/keys"
+	"github.com/libsv/go-bt/v2/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeWIF(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		wif           string
+		expNetwork    *network.Params
+		expCompressed bool
+		expAddress    string
+		expScript     string
+		expErr        bool
+	}{
+		"mainnet compressed": {
+			wif:           "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn",
+			expNetwork:    network.Mainnet,
+			expCompressed: true,
+			expAddress:    "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+			expScript:     "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac",
+		},
+		"mainnet uncompressed": {
+			wif:        "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf",
+			expNetwork: network.Mainnet,
+			expAddress: "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm",
+			expScript:  "76a91491b24bf9f5288532960ac687abb035127b1d28a588ac",
+		},
+		"testnet compressed": {
+			wif:           "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA",
+			expNetwork:    network.Testnet,
+			expCompressed: true,
+			expAddress:    "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+			expScript:     "76a914751e76e8199196d454941c45d1b3a323f1433bd688ac",
+		},
+		"bad checksum": {
+			wif:    "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWo",
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := keys.DecodeWIF(test.wif)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expNetwork, k.Network)
+			assert.Equal(t, test.expCompressed, k.Compressed)
+
+			w, err := k.WIF()
+			assert.NoError(t, err)
+			assert.Equal(t, test.wif, w)
+
+			addr, err := k.Address(nil)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expAddress, addr.AddressString)
+
+			s, err := k.P2PKHLockingScript()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expScript, s.String())
+		})
+	}
+}
+
+func TestPrivateKey_Address(t *testing.T) {
+	t.Parallel()
+
+	k, err := keys.DecodeWIF("KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	assert.NoError(t, err)
+
+	addr, err := k.Address(network.STN)
+	assert.NoError(t, err)
+	assert.Equal(t, "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r", addr.AddressString)
+	assert.Equal(t, network.STN, addr.Network)
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	k, err := keys.NewPrivateKey(network.Regtest)
+	assert.NoError(t, err)
+	assert.True(t, k.Compressed)
+
+	w, err := k.WIF()
+	assert.NoError(t, err)
+
+	k2, err := keys.DecodeWIF(w)
+	assert.NoError(t, err)
+	assert.Equal(t, k.Serialise(), k2.Serialise())
+	assert.Equal(t, network.Testnet, k2.Network)
+
+	_, err = keys.NewPrivateKey(nil)
+	assert.ErrorIs(t, err, keys.ErrNoNetwork)
+}
@@ -0,0 +1,13 @@
+package mempool
+
+import "github.com/pkg/errors"
+
+// Sentinel errors raised by the pool.
+var (
+	ErrTxExists              = errors.New("tx is already in the pool")
+	ErrTxNotFound            = errors.New("tx is not in the pool")
+	ErrOutputNotFound        = errors.New("input spends an output its parent does not have")
+	ErrUnknownInputValue     = errors.New("input spends a tx outside the pool without its previous output")
+	ErrAncestorLimit         = errors.New("too many unconfirmed ancestors")
+	ErrConflictsWithAncestor = errors.New("tx conflicts with its own ancestors")
+)
@@ -0,0 +1,325 @@
+// Package mempool provides a local model of a mempool of unconfirmed txs, tracking
+// the parent and child links between them so that chains of txs can be reasoned
+// about before they are broadcast: the ancestors and descendants of each tx, and
+// the size, fee and fee rate of those packages, as a miner evaluating child pays
+// for parent (CPFP) would see them.
+package mempool
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/libsv/go-bt/v2"
+)
+
+// DefaultAncestorLimit the default maximum number of txs in the ancestor package
+// of a tx, including the tx itself, matching the node's default -limitancestorcount.
+const DefaultAncestorLimit = 1000
+
+type entry struct {
+	tx       *bt.Tx
+	txID     string
+	seq      uint64
+	fee      uint64
+	parents  map[string]*entry
+	children map[string]*entry
+}
+
+// Pool is a set of unconfirmed txs. It is safe for concurrent use.
+type Pool struct {
+	mu            sync.RWMutex
+	txs           map[string]*entry
+	spends        map[bt.Outpoint]*entry
+	seq           uint64
+	ancestorLimit int
+}
+
+// New returns an empty pool.
+func New(opts ...OptionFunc) *Pool {
+	o := &poolOpts{
+		ancestorLimit: DefaultAncestorLimit,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Pool{
+		txs:           map[string]*entry{},
+		spends:        map[bt.Outpoint]*entry{},
+		ancestorLimit: o.ancestorLimit,
+	}
+}
+
+// Add adds a tx to the pool, linking it to the txs in the pool whose outputs it
+// spends. Inputs spending txs not in the pool must carry the satoshis and locking
+// script of the output they spend, as in the extended format, so that the fee
+// of the tx is known.
+//
+// Any txs in the pool which spend the same outputs as the tx conflict with it, and
+// are evicted along with their descendants, and returned.
+//
+// ErrAncestorLimit is returned if the ancestor package of the tx would exceed the
+// ancestor limit, in which case the pool is not changed.
+func (p *Pool) Add(tx *bt.Tx) ([]*bt.Tx, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := &entry{
+		tx:       tx,
+		txID:     tx.TxID(),
+		parents:  map[string]*entry{},
+		children: map[string]*entry{},
+	}
+	if _, ok := p.txs[e.txID]; ok {
+		return nil, ErrTxExists
+	}
+
+	// find the parents of the tx, and the txs it conflicts with
+	var inputs uint64
+	evict := map[string]*entry{}
+	for _, in := range tx.Inputs {
+		o := in.Outpoint()
+		if c, ok := p.spends[o]; ok {
+			p.descendants(c, evict)
+			evict[c.txID] = c
+		}
+
+		parent, ok := p.txs[o.TxID]
+		if !ok {
+			if in.PreviousTxScript == nil {
+				return nil, ErrUnknownInputValue
+			}
+			inputs += in.PreviousTxSatoshis
+			continue
+		}
+		if int(o.Vout) >= len(parent.tx.Outputs) {
+			return nil, ErrOutputNotFound
+		}
+		inputs += parent.tx.Outputs[o.Vout].Satoshis
+		e.parents[parent.txID] = parent
+	}
+
+	outputs := tx.TotalOutputSatoshis()
+	if inputs < outputs {
+		return nil, bt.ErrInsufficientInputs
+	}
+	e.fee = inputs - outputs
+
+	ancestors := map[string]*entry{}
+	for _, parent := range e.parents {
+		ancestors[parent.txID] = parent
+		p.ancestors(parent, ancestors)
+	}
+	for id := range ancestors {
+		if _, ok := evict[id]; ok {
+			return nil, ErrConflictsWithAncestor
+		}
+	}
+	if len(ancestors)+1 > p.ancestorLimit {
+		return nil, ErrAncestorLimit
+	}
+
+	evicted := p.remove(sorted(evict))
+
+	p.seq++
+	e.seq = p.seq
+	for _, parent := range e.parents {
+		parent.children[e.txID] = e
+	}
+	for _, in := range tx.Inputs {
+		p.spends[in.Outpoint()] = e
+	}
+	p.txs[e.txID] = e
+
+	return evicted, nil
+}
+
+// Get returns the tx with the txid from the pool.
+func (p *Pool) Get(txID string) (*bt.Tx, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.txs[txID]
+	if !ok {
+		return nil, false
+	}
+
+	return e.tx, true
+}
+
+// Len returns the number of txs in the pool.
+func (p *Pool) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.txs)
+}
+
+// SpentBy returns the tx in the pool spending the outpoint.
+func (p *Pool) SpentBy(o bt.Outpoint) (*bt.Tx, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.spends[o]
+	if !ok {
+		return nil, false
+	}
+
+	return e.tx, true
+}
+
+// Ancestors returns the txs in the pool which the tx depends on, directly or
+// indirectly, parents before children.
+func (p *Pool) Ancestors(txID string) ([]*bt.Tx, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.txs[txID]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	set := map[string]*entry{}
+	p.ancestors(e, set)
+
+	return txs(sorted(set)), nil
+}
+
+// Descendants returns the txs in the pool which depend on the tx, directly or
+// indirectly, parents before children.
+func (p *Pool) Descendants(txID string) ([]*bt.Tx, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.txs[txID]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	set := map[string]*entry{}
+	p.descendants(e, set)
+
+	return txs(sorted(set)), nil
+}
+
+// AncestorPackage returns the package of the tx and its ancestors, which must be
+// mined together with the tx. Its fee rate is the effective fee rate of the tx,
+// with child paying for parent.
+func (p *Pool) AncestorPackage(txID string) (*Package, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.txs[txID]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	set := map[string]*entry{e.txID: e}
+	p.ancestors(e, set)
+
+	return newPackage(sorted(set)), nil
+}
+
+// DescendantPackage returns the package of the tx and its descendants, which are
+// evicted along with the tx if it is replaced.
+func (p *Pool) DescendantPackage(txID string) (*Package, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	e, ok := p.txs[txID]
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	set := map[string]*entry{e.txID: e}
+	p.descendants(e, set)
+
+	return newPackage(sorted(set)), nil
+}
+
+// Remove removes the tx and its descendants from the pool, and returns them.
+func (p *Pool) Remove(txID string) []*bt.Tx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, ok := p.txs[txID]
+	if !ok {
+		return nil
+	}
+	set := map[string]*entry{e.txID: e}
+	p.descendants(e, set)
+
+	return p.remove(sorted(set))
+}
+
+// Mined removes a tx which has been mined from the pool. Its children remain in
+// the pool, no longer depending on it.
+func (p *Pool) Mined(txID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.txs[txID]; ok {
+		p.remove([]*entry{e})
+	}
+}
+
+// ancestors adds the ancestors of e to set.
+func (p *Pool) ancestors(e *entry, set map[string]*entry) {
+	for id, parent := range e.parents {
+		if _, ok := set[id]; ok {
+			continue
+		}
+		set[id] = parent
+		p.ancestors(parent, set)
+	}
+}
+
+// descendants adds the descendants of e to set.
+func (p *Pool) descendants(e *entry, set map[string]*entry) {
+	for id, child := range e.children {
+		if _, ok := set[id]; ok {
+			continue
+		}
+		set[id] = child
+		p.descendants(child, set)
+	}
+}
+
+// remove removes the entries from the pool, unlinking them from their parents
+// and children, and returns their txs.
+func (p *Pool) remove(ee []*entry) []*bt.Tx {
+	for _, e := range ee {
+		for _, parent := range e.parents {
+			delete(parent.children, e.txID)
+		}
+		for _, child := range e.children {
+			delete(child.parents, e.txID)
+		}
+		for _, in := range e.tx.Inputs {
+			if o := in.Outpoint(); p.spends[o] == e {
+				delete(p.spends, o)
+			}
+		}
+		delete(p.txs, e.txID)
+	}
+
+	return txs(ee)
+}
+
+// sorted returns the entries of set in the order they were added to the pool,
+// which puts parents before children.
+func sorted(set map[string]*entry) []*entry {
+	ee := make([]*entry, 0, len(set))
+	for _, e := range set {
+		ee = append(ee, e)
+	}
+	sort.Slice(ee, func(i, j int) bool {
+		return ee[i].seq < ee[j].seq
+	})
+
+	return ee
+}
+
+func txs(ee []*entry) []*bt.Tx {
+	tt := make([]*bt.Tx, 0, len(ee))
+	for _, e := range ee {
+		tt = append(tt, e.tx)
+	}
+
+	return tt
+}
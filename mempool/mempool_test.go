@@ -0,0 +1,270 @@
+package mempool_test
+
+import (
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/mempool"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	fundingTxID = "11b476ad8e0a48fcd40807a111a050af51114877e09283bfa7f3505081a1819d"
+	p2pkh       = "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac"
+)
+
+type prevOut struct {
+	txID     string
+	vout     uint32
+	satoshis uint64
+}
+
+// newTx returns a tx spending the previous outputs, with an output of each of satoshis.
+func newTx(t *testing.T, prevOuts []prevOut, satoshis ...uint64) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	for _, p := range prevOuts {
+		assert.NoError(t, tx.From(p.txID, p.vout, p2pkh, p.satoshis))
+	}
+	for _, s := range satoshis {
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", s))
+	}
+
+	return tx
+}
+
+func txIDs(tt []*bt.Tx) []string {
+	ids := make([]string, 0, len(tt))
+	for _, tx := range tt {
+		ids = append(ids, tx.TxID())
+	}
+
+	return ids
+}
+
+func TestPool_Links(t *testing.T) {
+	t.Parallel()
+
+	// a spends the funding tx, b and c spend a, and d spends b and c
+	a := newTx(t, []prevOut{{fundingTxID, 0, 10000}}, 4000, 5000)
+	b := newTx(t, []prevOut{{a.TxID(), 0, 4000}}, 3900)
+	c := newTx(t, []prevOut{{a.TxID(), 1, 5000}}, 4900)
+	d := newTx(t, []prevOut{{b.TxID(), 0, 3900}, {c.TxID(), 0, 4900}}, 8000)
+
+	pool := mempool.New()
+	for _, tx := range []*bt.Tx{a, b, c, d} {
+		evicted, err := pool.Add(tx)
+		assert.NoError(t, err)
+		assert.Empty(t, evicted)
+	}
+	assert.Equal(t, 4, pool.Len())
+
+	_, err := pool.Add(a)
+	assert.ErrorIs(t, err, mempool.ErrTxExists)
+
+	ancestors, err := pool.Ancestors(d.TxID())
+	assert.NoError(t, err)
+	assert.Equal(t, txIDs([]*bt.Tx{a, b, c}), txIDs(ancestors))
+
+	descendants, err := pool.Descendants(a.TxID())
+	assert.NoError(t, err)
+	assert.Equal(t, txIDs([]*bt.Tx{b, c, d}), txIDs(descendants))
+
+	descendants, err = pool.Descendants(c.TxID())
+	assert.NoError(t, err)
+	assert.Equal(t, txIDs([]*bt.Tx{d}), txIDs(descendants))
+
+	spender, ok := pool.SpentBy(bt.Outpoint{TxID: a.TxID(), Vout: 1})
+	assert.True(t, ok)
+	assert.Equal(t, c.TxID(), spender.TxID())
+
+	_, err = pool.Ancestors("00")
+	assert.ErrorIs(t, err, mempool.ErrTxNotFound)
+
+	t.Run("mined txs are removed leaving their children", func(t *testing.T) {
+		pool := mempool.New()
+		for _, tx := range []*bt.Tx{a, b, c, d} {
+			_, err := pool.Add(tx)
+			assert.NoError(t, err)
+		}
+
+		pool.Mined(a.TxID())
+		assert.Equal(t, 3, pool.Len())
+		ancestors, err := pool.Ancestors(d.TxID())
+		assert.NoError(t, err)
+		assert.Equal(t, txIDs([]*bt.Tx{b, c}), txIDs(ancestors))
+	})
+
+	t.Run("removed txs take their descendants", func(t *testing.T) {
+		pool := mempool.New()
+		for _, tx := range []*bt.Tx{a, b, c, d} {
+			_, err := pool.Add(tx)
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, txIDs([]*bt.Tx{b, d}), txIDs(pool.Remove(b.TxID())))
+		assert.Equal(t, 2, pool.Len())
+		_, ok := pool.SpentBy(bt.Outpoint{TxID: a.TxID(), Vout: 0})
+		assert.False(t, ok)
+	})
+}
+
+func TestPool_Add(t *testing.T) {
+	t.Parallel()
+
+	a := newTx(t, []prevOut{{fundingTxID, 0, 10000}}, 9000)
+
+	t.Run("unknown input values are rejected", func(t *testing.T) {
+		tx := bt.NewTx()
+		assert.NoError(t, tx.From(fundingTxID, 0, p2pkh, 10000))
+		tx.Inputs[0].PreviousTxScript = nil
+
+		_, err := mempool.New().Add(tx)
+		assert.ErrorIs(t, err, mempool.ErrUnknownInputValue)
+	})
+
+	t.Run("inputs must cover outputs", func(t *testing.T) {
+		_, err := mempool.New().Add(newTx(t, []prevOut{{fundingTxID, 0, 1000}}, 2000))
+		assert.ErrorIs(t, err, bt.ErrInsufficientInputs)
+	})
+
+	t.Run("outputs spent must exist", func(t *testing.T) {
+		pool := mempool.New()
+		_, err := pool.Add(a)
+		assert.NoError(t, err)
+
+		_, err = pool.Add(newTx(t, []prevOut{{a.TxID(), 1, 1000}}, 900))
+		assert.ErrorIs(t, err, mempool.ErrOutputNotFound)
+	})
+
+	t.Run("the values of outputs spent in the pool are taken from the parent", func(t *testing.T) {
+		pool := mempool.New()
+		_, err := pool.Add(a)
+		assert.NoError(t, err)
+
+		// the input claims more than the parent output is worth
+		b := newTx(t, []prevOut{{a.TxID(), 0, 100000}}, 9500)
+		_, err = pool.Add(b)
+		assert.ErrorIs(t, err, bt.ErrInsufficientInputs)
+	})
+
+	t.Run("ancestor limit", func(t *testing.T) {
+		pool := mempool.New(mempool.WithAncestorLimit(3))
+
+		prev := prevOut{fundingTxID, 0, 10000}
+		for i := 0; i < 3; i++ {
+			tx := newTx(t, []prevOut{prev}, prev.satoshis-100)
+			_, err := pool.Add(tx)
+			assert.NoError(t, err)
+			prev = prevOut{tx.TxID(), 0, prev.satoshis - 100}
+		}
+
+		_, err := pool.Add(newTx(t, []prevOut{prev}, prev.satoshis-100))
+		assert.ErrorIs(t, err, mempool.ErrAncestorLimit)
+		assert.Equal(t, 3, pool.Len())
+	})
+
+	t.Run("conflicting txs are evicted with their descendants", func(t *testing.T) {
+		pool := mempool.New()
+		_, err := pool.Add(a)
+		assert.NoError(t, err)
+		b := newTx(t, []prevOut{{a.TxID(), 0, 9000}}, 8000)
+		_, err = pool.Add(b)
+		assert.NoError(t, err)
+
+		// double spends the funding output of a
+		replacement := newTx(t, []prevOut{{fundingTxID, 0, 10000}}, 5000)
+		evicted, err := pool.Add(replacement)
+		assert.NoError(t, err)
+		assert.Equal(t, txIDs([]*bt.Tx{a, b}), txIDs(evicted))
+		assert.Equal(t, 1, pool.Len())
+
+		spender, ok := pool.SpentBy(bt.Outpoint{TxID: fundingTxID, Vout: 0})
+		assert.True(t, ok)
+		assert.Equal(t, replacement.TxID(), spender.TxID())
+	})
+
+	t.Run("txs conflicting with their ancestors are rejected", func(t *testing.T) {
+		pool := mempool.New()
+		_, err := pool.Add(a)
+		assert.NoError(t, err)
+
+		tx := newTx(t, []prevOut{{a.TxID(), 0, 9000}, {fundingTxID, 0, 10000}}, 8000)
+		_, err = pool.Add(tx)
+		assert.ErrorIs(t, err, mempool.ErrConflictsWithAncestor)
+		assert.Equal(t, 1, pool.Len())
+	})
+}
+
+func TestPool_Packages(t *testing.T) {
+	t.Parallel()
+
+	fq := bt.NewFeeQuote()
+
+	// the parent pays too little fee, which the child makes up for
+	parent := newTx(t, []prevOut{{fundingTxID, 0, 10000}}, 9990)
+	child := newTx(t, []prevOut{{parent.TxID(), 0, 9990}}, 9000)
+
+	pool := mempool.New()
+	_, err := pool.Add(parent)
+	assert.NoError(t, err)
+	_, err = pool.Add(child)
+	assert.NoError(t, err)
+
+	pkg, err := pool.AncestorPackage(parent.TxID())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), pkg.Fee)
+	ok, err := pkg.IsFeePaidEnough(fq)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	pkg, err = pool.AncestorPackage(child.TxID())
+	assert.NoError(t, err)
+	assert.Equal(t, txIDs([]*bt.Tx{parent, child}), txIDs(pkg.Txs))
+	assert.Equal(t, uint64(1000), pkg.Fee)
+	assert.Equal(t, uint64(parent.Size()+child.Size()), pkg.Size.TotalBytes)
+	assert.InDelta(t, 1000/float64(parent.Size()+child.Size()), pkg.FeeRate(), 1e-9)
+
+	required, err := pkg.RequiredFee(fq)
+	assert.NoError(t, err)
+	assert.Equal(t, pkg.Size.TotalBytes*5/10, required)
+	ok, err = pkg.IsFeePaidEnough(fq)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	pkg, err = pool.DescendantPackage(parent.TxID())
+	assert.NoError(t, err)
+	assert.Equal(t, txIDs([]*bt.Tx{parent, child}), txIDs(pkg.Txs))
+	assert.Equal(t, uint64(1000), pkg.Fee)
+
+	t.Run("data outputs are charged at the data rate", func(t *testing.T) {
+		tx := newTx(t, []prevOut{{fundingTxID, 1, 1000}}, 900)
+		assert.NoError(t, tx.AddOpReturnOutput(make([]byte, 100)))
+
+		pool := mempool.New()
+		_, err := pool.Add(tx)
+		assert.NoError(t, err)
+
+		pkg, err := pool.AncestorPackage(tx.TxID())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(104), pkg.Size.TotalDataBytes)
+		assert.Equal(t, pkg.Size.TotalBytes-104, pkg.Size.TotalStdBytes)
+	})
+
+	t.Run("any fee model prices the package as a single tx", func(t *testing.T) {
+		pkg, err := pool.AncestorPackage(child.TxID())
+		assert.NoError(t, err)
+
+		fm := bt.MinimumFeeModel{MinimumFee: 1000, SatoshisPerKB: 1000}
+		required, err := pkg.RequiredFee(fm)
+		assert.NoError(t, err)
+		assert.Equal(t, 1000+pkg.Size.TotalBytes, required)
+		ok, err := pkg.IsFeePaidEnough(fm)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		_, err = pkg.RequiredFee(nil)
+		assert.ErrorIs(t, err, bt.ErrFeeModelNotInit)
+	})
+}
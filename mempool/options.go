@@ -0,0 +1,16 @@
+package mempool
+
+// OptionFunc for setting pool options.
+type OptionFunc func(o *poolOpts)
+
+type poolOpts struct {
+	ancestorLimit int
+}
+
+// WithAncestorLimit configure the maximum number of txs in the ancestor package of
+// a tx, including the tx itself. Defaults to DefaultAncestorLimit.
+func WithAncestorLimit(n int) OptionFunc {
+	return func(o *poolOpts) {
+		o.ancestorLimit = n
+	}
+}
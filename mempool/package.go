@@ -0,0 +1,66 @@
+package mempool
+
+import (
+	"github.com/libsv/go-bt/v2"
+)
+
+// Package is a set of related txs in the pool, which are evaluated together.
+type Package struct {
+	// Txs the txs of the package, parents before children.
+	Txs []*bt.Tx
+	// Size the total size of the txs.
+	Size bt.TxSize
+	// Fee the total fee paid by the txs.
+	Fee uint64
+}
+
+func newPackage(ee []*entry) *Package {
+	pkg := &Package{Txs: txs(ee)}
+	for _, e := range ee {
+		size := e.tx.SizeWithTypes()
+		pkg.Size.TotalBytes += size.TotalBytes
+		pkg.Size.TotalStdBytes += size.TotalStdBytes
+		pkg.Size.TotalDataBytes += size.TotalDataBytes
+		pkg.Fee += e.fee
+	}
+
+	return pkg
+}
+
+// FeeRate returns the fee paid by the package in satoshis per byte.
+func (p *Package) FeeRate() float64 {
+	if p.Size.TotalBytes == 0 {
+		return 0
+	}
+
+	return float64(p.Fee) / float64(p.Size.TotalBytes)
+}
+
+// RequiredFee returns the fee required for the package to be mined, as computed
+// by the fee model for a single tx of the size of the package, spending the inputs
+// and paying the outputs of all of its txs.
+func (p *Package) RequiredFee(fm bt.FeeModel) (uint64, error) {
+	if fm == nil {
+		return 0, bt.ErrFeeModelNotInit
+	}
+
+	tx := bt.NewTx()
+	for _, t := range p.Txs {
+		tx.Inputs = append(tx.Inputs, t.Inputs...)
+		tx.Outputs = append(tx.Outputs, t.Outputs...)
+	}
+	size := p.Size
+
+	return fm.ComputeFee(tx, &size)
+}
+
+// IsFeePaidEnough returns true if the package pays at least its required fee
+// for the fee model.
+func (p *Package) IsFeePaidEnough(fm bt.FeeModel) (bool, error) {
+	required, err := p.RequiredFee(fm)
+	if err != nil {
+		return false, err
+	}
+
+	return p.Fee >= required, nil
+}
@@ -0,0 +1,8 @@
+package network
+
+import "github.com/pkg/errors"
+
+// Sentinel errors raised by the package.
+var (
+	ErrUnknownNetwork = errors.New("unknown network")
+)
@@ -0,0 +1,194 @@
+// Package network defines the parameters of the bitcoin networks, used when
+// encoding and decoding addresses, keys and other network specific data.
+package network
+
+import (
+	"github.com/libsv/go-bk/chaincfg"
+)
+
+// Params defines a bitcoin network by its parameters.
+type Params struct {
+	// Name a human-readable identifier for the network.
+	Name string
+
+	// Address encoding magics.
+	PubKeyHashAddrID byte // First byte of a P2PKH address
+	ScriptHashAddrID byte // First byte of a P2SH address
+	PrivateKeyID     byte // First byte of a WIF private key
+
+	// BIP32 hierarchical deterministic extended key magics.
+	HDPrivateKeyID [4]byte
+	HDPublicKeyID  [4]byte
+
+	// BIP276Network the network identifier used in BIP276 encoded data.
+	BIP276Network int
+
+	// GenesisHash the hash of the genesis block, hex encoded in display order.
+	GenesisHash string
+	// Magic the message start bytes of the P2P protocol, also used to delimit
+	// blocks stored on disk by the node.
+	Magic [4]byte
+	// DefaultPort the default P2P port of the node.
+	DefaultPort uint16
+}
+
+// Mainnet defines the network parameters for the main bitcoin network.
+var Mainnet = &Params{
+	Name:             "mainnet",
+	PubKeyHashAddrID: 0x00,                            // starts with 1
+	ScriptHashAddrID: 0x05,                            // starts with 3
+	PrivateKeyID:     0x80,                            // starts with 5 (uncompressed) or K/L (compressed)
+	HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4}, // starts with xprv
+	HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e}, // starts with xpub
+	BIP276Network:    1,
+	GenesisHash:      "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f",
+	Magic:            [4]byte{0xe3, 0xe1, 0xf3, 0xe8},
+	DefaultPort:      8333,
+}
+
+// Testnet defines the network parameters for the test bitcoin network (version 3).
+var Testnet = &Params{
+	Name:             "testnet",
+	PubKeyHashAddrID: 0x6f,                            // starts with m or n
+	ScriptHashAddrID: 0xc4,                            // starts with 2
+	PrivateKeyID:     0xef,                            // starts with 9 (uncompressed) or c (compressed)
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+	BIP276Network:    2,
+	GenesisHash:      "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+	Magic:            [4]byte{0xf4, 0xe5, 0xf3, 0xf4},
+	DefaultPort:      18333,
+}
+
+// Regtest defines the network parameters for the regression test bitcoin network.
+// It shares its address and key encodings with Testnet.
+var Regtest = &Params{
+	Name:             "regtest",
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+	BIP276Network:    2,
+	GenesisHash:      "0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206",
+	Magic:            [4]byte{0xda, 0xb5, 0xbf, 0xfa},
+	DefaultPort:      18444,
+}
+
+// STN defines the network parameters for the scaling test network. It shares its
+// address and key encodings, and its genesis block, with Testnet.
+var STN = &Params{
+	Name:             "stn",
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xef,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+	BIP276Network:    2,
+	GenesisHash:      "000000000933ea01ad0ee984209779baaec3ced90fa3f408719526f8d77f4943",
+	Magic:            [4]byte{0xfb, 0xce, 0xc4, 0xf9},
+	DefaultPort:      9333,
+}
+
+// All is every known network. Where networks share an encoding, lookups return
+// the first matching network in this list.
+var All = []*Params{Mainnet, Testnet, Regtest, STN}
+
+// FromMainnet returns Mainnet if mainnet is true, otherwise Testnet. It is
+// used to support functions which select a network with a boolean.
+func FromMainnet(mainnet bool) *Params {
+	if mainnet {
+		return Mainnet
+	}
+
+	return Testnet
+}
+
+// ByName returns the network with the given name.
+func ByName(name string) (*Params, error) {
+	for _, p := range All {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// ByPubKeyHashAddrID returns the first network using the P2PKH address version byte.
+func ByPubKeyHashAddrID(id byte) (*Params, error) {
+	for _, p := range All {
+		if p.PubKeyHashAddrID == id {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// ByScriptHashAddrID returns the first network using the P2SH address version byte.
+func ByScriptHashAddrID(id byte) (*Params, error) {
+	for _, p := range All {
+		if p.ScriptHashAddrID == id {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// ByPrivateKeyID returns the first network using the WIF version byte.
+func ByPrivateKeyID(id byte) (*Params, error) {
+	for _, p := range All {
+		if p.PrivateKeyID == id {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// ByBIP276Network returns the first network using the BIP276 network identifier.
+func ByBIP276Network(id int) (*Params, error) {
+	for _, p := range All {
+		if p.BIP276Network == id {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// ByMagic returns the network using the P2P message start bytes.
+func ByMagic(magic [4]byte) (*Params, error) {
+	for _, p := range All {
+		if p.Magic == magic {
+			return p, nil
+		}
+	}
+
+	return nil, ErrUnknownNetwork
+}
+
+// IsMainnet returns true if these are the main network parameters.
+func (p *Params) IsMainnet() bool {
+	return p.Name == Mainnet.Name
+}
+
+// String returns the name of the network.
+func (p *Params) String() string {
+	return p.Name
+}
+
+// ChainParams returns the network as go-bk chain parameters, for use with
+// the bip32 and wif packages.
+func (p *Params) ChainParams() *chaincfg.Params {
+	return &chaincfg.Params{
+		Name:                   p.Name,
+		LegacyPubKeyHashAddrID: p.PubKeyHashAddrID,
+		LegacyScriptHashAddrID: p.ScriptHashAddrID,
+		PrivateKeyID:           p.PrivateKeyID,
+		HDPrivateKeyID:         p.HDPrivateKeyID,
+		HDPublicKeyID:          p.HDPublicKeyID,
+	}
+}
@@ -0,0 +1,102 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/libsv/go-bk/bip32"
+	"github.com/libsv/go-bt/v2/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByName(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range network.All {
+		n, err := network.ByName(p.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, p, n)
+	}
+
+	_, err := network.ByName("nope")
+	assert.ErrorIs(t, err, network.ErrUnknownNetwork)
+}
+
+func TestLookups(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lookup func() (*network.Params, error)
+		exp    *network.Params
+		expErr error
+	}{
+		"mainnet address": {
+			lookup: func() (*network.Params, error) { return network.ByPubKeyHashAddrID(0x00) },
+			exp:    network.Mainnet,
+		},
+		"shared test address resolves to testnet": {
+			lookup: func() (*network.Params, error) { return network.ByPubKeyHashAddrID(0x6f) },
+			exp:    network.Testnet,
+		},
+		"unknown address": {
+			lookup: func() (*network.Params, error) { return network.ByPubKeyHashAddrID(0x05) },
+			expErr: network.ErrUnknownNetwork,
+		},
+		"mainnet wif": {
+			lookup: func() (*network.Params, error) { return network.ByPrivateKeyID(0x80) },
+			exp:    network.Mainnet,
+		},
+		"testnet bip276": {
+			lookup: func() (*network.Params, error) { return network.ByBIP276Network(2) },
+			exp:    network.Testnet,
+		},
+		"stn magic": {
+			lookup: func() (*network.Params, error) { return network.ByMagic([4]byte{0xfb, 0xce, 0xc4, 0xf9}) },
+			exp:    network.STN,
+		},
+		"regtest magic": {
+			lookup: func() (*network.Params, error) { return network.ByMagic([4]byte{0xda, 0xb5, 0xbf, 0xfa}) },
+			exp:    network.Regtest,
+		},
+		"unknown magic": {
+			lookup: func() (*network.Params, error) { return network.ByMagic([4]byte{}) },
+			expErr: network.ErrUnknownNetwork,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := test.lookup()
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.exp, p)
+		})
+	}
+}
+
+func TestParams_ChainParams(t *testing.T) {
+	t.Parallel()
+
+	seed := make([]byte, 32)
+
+	k, err := bip32.NewMaster(seed, network.Mainnet.ChainParams())
+	assert.NoError(t, err)
+	assert.Equal(t, "xprv", k.String()[:4])
+
+	k, err = bip32.NewMaster(seed, network.Regtest.ChainParams())
+	assert.NoError(t, err)
+	assert.Equal(t, "tprv", k.String()[:4])
+}
+
+func TestFromMainnet(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, network.FromMainnet(true).IsMainnet())
+	assert.Equal(t, network.Testnet, network.FromMainnet(false))
+}
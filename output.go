@@ -7,7 +7,6 @@ import (
 	"io"
 
 	"github.com/libsv/go-bt/v2/bscript"
-	"github.com/pkg/errors"
 )
 
 /*
@@ -30,34 +29,29 @@ type Output struct {
 
 // ReadFrom reads from the `io.Reader` into the `bt.Output`.
 func (o *Output) ReadFrom(r io.Reader) (int64, error) {
+	d := &txDecoder{r: r}
+	err := o.decode(d, "")
+
+	return d.n, err
+}
+
+func (o *Output) decode(d *txDecoder, field string) error {
 	*o = Output{}
-	var bytesRead int64
 
 	satoshis := make([]byte, 8)
-	n, err := io.ReadFull(r, satoshis)
-	bytesRead += int64(n)
-	if err != nil {
-		return bytesRead, errors.Wrapf(err, "satoshis(8): got %d bytes", n)
-	}
-
-	var l VarInt
-	n64, err := l.ReadFrom(r)
-	bytesRead += n64
-	if err != nil {
-		return bytesRead, err
+	if err := d.readFull(satoshis, joinField(field, "satoshis"), "satoshis(8)"); err != nil {
+		return err
 	}
 
-	script := make([]byte, l)
-	n, err = io.ReadFull(r, script)
-	bytesRead += int64(n)
+	script, err := d.script(joinField(field, "locking script"), "lockingScript")
 	if err != nil {
-		return bytesRead, errors.Wrapf(err, "lockingScript(%d): got %d bytes", l, n)
+		return err
 	}
 
 	o.Satoshis = binary.LittleEndian.Uint64(satoshis)
-	o.LockingScript = bscript.NewFromBytes(script)
+	o.LockingScript = script
 
-	return bytesRead, nil
+	return nil
 }
 
 // LockingScriptHexString returns the locking script
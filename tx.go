@@ -10,6 +10,7 @@ import (
 	"github.com/libsv/go-bk/crypto"
 
 	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
 )
 
 /*
@@ -53,25 +54,29 @@ func NewTx() *Tx {
 }
 
 // NewTxFromString takes a toBytesHelper string representation of a bitcoin transaction
-// and returns a Tx object.
-func NewTxFromString(str string) (*Tx, error) {
+// and returns a Tx object. Options can be provided, such as WithStrictDecoding.
+func NewTxFromString(str string, opts ...DecodeOptionFunc) (*Tx, error) {
 	bb, err := hex.DecodeString(str)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewTxFromBytes(bb)
+	return NewTxFromBytes(bb, opts...)
 }
 
 // NewTxFromBytes takes an array of bytes, constructs a Tx and returns it.
 // This function assumes that the byte slice contains exactly 1 transaction.
-func NewTxFromBytes(b []byte) (*Tx, error) {
-	tx, used, err := NewTxFromStream(b)
+// Options can be provided, such as WithStrictDecoding.
+func NewTxFromBytes(b []byte, opts ...DecodeOptionFunc) (*Tx, error) {
+	tx, used, err := NewTxFromStream(b, opts...)
 	if err != nil {
 		return nil, err
 	}
 
 	if used != len(b) {
+		if decodeOptions(opts).strict {
+			return nil, DecodeError{Offset: used, Field: "trailing data", Err: ErrTrailingBytes}
+		}
 		return nil, ErrNLockTimeLength
 	}
 
@@ -80,41 +85,44 @@ func NewTxFromBytes(b []byte) (*Tx, error) {
 
 // NewTxFromStream takes an array of bytes and constructs a Tx from it, returning the Tx and the bytes used.
 // Despite the name, this is not actually reading a stream in the true sense: it is a byte slice that contains
-// many transactions one after another.
-func NewTxFromStream(b []byte) (*Tx, int, error) {
+// many transactions one after another. Options can be provided, such as WithStrictDecoding.
+func NewTxFromStream(b []byte, opts ...DecodeOptionFunc) (*Tx, int, error) {
 	tx := Tx{}
 
-	bytesRead, err := tx.ReadFrom(bytes.NewReader(b))
+	bytesRead, err := tx.ReadFromWithOptions(bytes.NewReader(b), opts...)
 
 	return &tx, int(bytesRead), err
 }
 
 // ReadFrom reads from the `io.Reader` into the `bt.Tx`.
 func (tx *Tx) ReadFrom(r io.Reader) (int64, error) {
-	*tx = Tx{}
-	var bytesRead int64
+	return tx.ReadFromWithOptions(r)
+}
+
+// ReadFromWithOptions reads from the `io.Reader` into the `bt.Tx`, as ReadFrom does,
+// with the options provided, such as WithStrictDecoding.
+func (tx *Tx) ReadFromWithOptions(r io.Reader, opts ...DecodeOptionFunc) (int64, error) {
+	d := newTxDecoder(r, opts)
+	err := tx.decode(d, "")
 
-	// Define n64 and err here to avoid linter complaining about shadowing variables.
-	var n64 int64
-	var err error
+	return d.n, err
+}
+
+func (tx *Tx) decode(d *txDecoder, field string) error {
+	*tx = Tx{}
 
 	version := make([]byte, 4)
-	n, err := io.ReadFull(r, version)
-	bytesRead += int64(n)
-	if err != nil {
-		return bytesRead, err
+	if err := d.readFull(version, joinField(field, "version"), ""); err != nil {
+		return err
 	}
 
 	tx.Version = binary.LittleEndian.Uint32(version)
 
 	extended := false
 
-	var inputCount VarInt
-
-	n64, err = inputCount.ReadFrom(r)
-	bytesRead += n64
+	inputCount, err := d.count(joinField(field, "input count"), minInputSize)
 	if err != nil {
-		return bytesRead, err
+		return err
 	}
 
 	var outputCount VarInt
@@ -125,31 +133,25 @@ func (tx *Tx) ReadFrom(r io.Reader) (int64, error) {
 	// both of these cases without needing to rewind (peek) the incoming stream of bytes.
 	// ----------------------------------------------------------------------------------
 	if inputCount == 0 {
-		n64, err = outputCount.ReadFrom(r)
-		bytesRead += n64
-		if err != nil {
-			return bytesRead, err
+		if outputCount, err = d.count(joinField(field, "output count"), minOutputSize); err != nil {
+			return err
 		}
 
 		if outputCount == 0 {
 			// Read in lock time
-			n, err = io.ReadFull(r, locktime)
-			bytesRead += int64(n)
-			if err != nil {
-				return bytesRead, err
+			if err = d.readFull(locktime, joinField(field, "locktime"), ""); err != nil {
+				return err
 			}
 
 			if binary.BigEndian.Uint32(locktime) != 0xEF {
 				tx.LockTime = binary.LittleEndian.Uint32(locktime)
-				return bytesRead, nil
+				return nil
 			}
 
 			extended = true
 
-			n64, err = inputCount.ReadFrom(r)
-			bytesRead += n64
-			if err != nil {
-				return bytesRead, err
+			if inputCount, err = d.count(joinField(field, "input count"), minExtendedInputSize); err != nil {
+				return err
 			}
 		}
 	}
@@ -162,70 +164,64 @@ func (tx *Tx) ReadFrom(r io.Reader) (int64, error) {
 	// create Inputs
 	for i := uint64(0); i < uint64(inputCount); i++ {
 		input := &Input{}
-		n64, err = input.readFrom(r, extended)
-		bytesRead += n64
-		if err != nil {
-			return bytesRead, err
+		if err = input.decode(d, d.field(field, "input", i), extended); err != nil {
+			return err
 		}
 		tx.Inputs = append(tx.Inputs, input)
 	}
 
 	if inputCount > 0 || extended {
 		// Re-read the actual output count...
-		n64, err = outputCount.ReadFrom(r)
-		bytesRead += n64
-		if err != nil {
-			return bytesRead, err
+		if outputCount, err = d.count(joinField(field, "output count"), minOutputSize); err != nil {
+			return err
 		}
 	}
 
 	for i := uint64(0); i < uint64(outputCount); i++ {
 		output := new(Output)
-		n64, err = output.ReadFrom(r)
-		bytesRead += n64
-		if err != nil {
-			return bytesRead, err
+		if err = output.decode(d, d.field(field, "output", i)); err != nil {
+			return err
 		}
 
 		tx.Outputs = append(tx.Outputs, output)
 	}
 
-	n, err = io.ReadFull(r, locktime)
-	bytesRead += int64(n)
-	if err != nil {
-		return bytesRead, err
+	if err = d.readFull(locktime, joinField(field, "locktime"), ""); err != nil {
+		return err
 	}
 	tx.LockTime = binary.LittleEndian.Uint32(locktime)
 
-	return bytesRead, nil
+	return nil
 }
 
 // ReadFrom txs from a block in a `bt.Txs`. This assumes a preceding varint detailing
 // the total number of txs that the reader will provide.
 func (tt *Txs) ReadFrom(r io.Reader) (int64, error) {
-	var bytesRead int64
+	return tt.ReadFromWithOptions(r)
+}
+
+// ReadFromWithOptions reads txs from a block in a `bt.Txs`, as ReadFrom does, with
+// the options provided, such as WithStrictDecoding.
+func (tt *Txs) ReadFromWithOptions(r io.Reader, opts ...DecodeOptionFunc) (int64, error) {
+	d := newTxDecoder(r, opts)
 
-	var txCount VarInt
-	n, err := txCount.ReadFrom(r)
-	bytesRead += n
+	txCount, err := d.count("tx count", minTxSize)
 	if err != nil {
-		return bytesRead, err
+		return d.n, err
 	}
 
-	*tt = make([]*Tx, txCount)
+	*tt = make([]*Tx, 0, d.capacity(txCount))
 
 	for i := uint64(0); i < uint64(txCount); i++ {
 		tx := new(Tx)
-		n, err := tx.ReadFrom(r)
-		bytesRead += n
-		if err != nil {
-			return bytesRead, err
+		if err = tx.decode(d, d.field("", "tx", i)); err != nil {
+			return d.n, err
 		}
 
-		(*tt)[i] = tx
+		*tt = append(*tt, tx)
 	}
 
-	return bytesRead, nil
+	return d.n, nil
 }
 
 // HasDataOutputs returns true if the transaction has
@@ -281,6 +277,34 @@ func (tx *Tx) IsCoinbase() bool {
 	return false
 }
 
+// IsFinal determines if the transaction is final, and so can be included in a block,
+// at the given block height and median time past.
+//
+// A transaction is final if its LockTime is zero, if its LockTime is less than the
+// height or time (depending on whether LockTime is below LockTimeThreshold), or if
+// all of its inputs have a finalised sequence number.
+func (tx *Tx) IsFinal(height, medianTime uint32) bool {
+	if tx.LockTime == 0 {
+		return true
+	}
+
+	limit := height
+	if tx.LockTime >= LockTimeThreshold {
+		limit = medianTime
+	}
+	if tx.LockTime < limit {
+		return true
+	}
+
+	for _, in := range tx.Inputs {
+		if in.SequenceNumber != MaxTxInSequenceNum {
+			return false
+		}
+	}
+
+	return true
+}
+
 // TxIDBytes returns the transaction ID of the transaction as bytes
 // (which is also the transaction hash).
 func (tx *Tx) TxIDBytes() []byte {
@@ -338,6 +362,10 @@ func (tx *Tx) Clone() *Tx {
 
 // NodeJSON returns a wrapped *bt.Tx for marshalling/unmarshalling into a node tx format.
 //
+// The tx is marshalled as the node outputs it from getrawtransaction with verbose set,
+// being the output of decoderawtransaction followed by the hex of the tx, with the
+// addresses of the outputs on mainnet.
+//
 // Marshalling usage example:
 //
 //	bb, err := json.Marshal(tx.NodeJSON())
@@ -350,6 +378,12 @@ func (tx *Tx) NodeJSON() interface{} {
 	return &nodeTxWrapper{Tx: tx}
 }
 
+// NodeJSONForNetwork returns a wrapped *bt.Tx for marshalling/unmarshalling into a node
+// tx format as NodeJSON, with the addresses of the outputs on the network provided.
+func (tx *Tx) NodeJSONForNetwork(net *network.Params) interface{} {
+	return &nodeTxWrapper{Tx: tx, net: net}
+}
+
 // NodeJSON returns a wrapped bt.Txs for marshalling/unmarshalling into a node tx format.
 //
 // Marshalling usage example:
@@ -418,7 +452,7 @@ type TxSize struct {
 	TotalBytes uint64
 	// TotalStdBytes are the amount of bytes for the tx minus the data bytes.
 	TotalStdBytes uint64
-	// TotalDataBytes is the size in bytes of the op_return / data outputs.
+	// TotalDataBytes is the size in bytes of the op_return / data in outputs.
 	TotalDataBytes uint64
 }
 
@@ -429,14 +463,16 @@ func (tx *Tx) Size() int {
 
 // SizeWithTypes will return the size of tx in bytes
 // and include the different data types (std/data/etc.).
+// The data bytes are those of output scripts from `bscript.Script.DataOffset` on.
+// See SizeBreakdown for the size of each input and output.
 func (tx *Tx) SizeWithTypes() *TxSize {
 	totBytes := tx.Size()
 
 	// calculate data outputs
 	dataLen := 0
 	for _, d := range tx.Outputs {
-		if d.LockingScript.IsData() {
-			dataLen += len(*d.LockingScript)
+		if offset, ok := d.LockingScript.DataOffset(); ok {
+			dataLen += len(*d.LockingScript) - offset
 		}
 	}
 	return &TxSize{
@@ -446,9 +482,10 @@ func (tx *Tx) SizeWithTypes() *TxSize {
 	}
 }
 
-// EstimateSize will return the size of tx in bytes and will add 107 bytes
-// to the unlocking script of any unsigned inputs (only P2PKH for now) found
-// to give a final size estimate of the tx size.
+// EstimateSize will return the size of tx in bytes and will add the estimated
+// unlocking script length (107 bytes for P2PKH) to any unsigned inputs found to
+// give a final size estimate of the tx size. The unlocking script length is taken
+// from the matching bscript.ScriptTemplate in bscript.DefaultTemplates.
 func (tx *Tx) EstimateSize() (int, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
@@ -459,9 +496,9 @@ func (tx *Tx) EstimateSize() (int, error) {
 }
 
 // EstimateSizeWithTypes will return the size of tx in bytes, including the
-// different data types (std/data/etc.), and will add 107 bytes to the unlocking
-// script of any unsigned inputs (only P2PKH for now) found to give a final size
-// estimate of the tx size.
+// different data types (std/data/etc.), and will add the estimated unlocking
+// script length (107 bytes for P2PKH) to any unsigned inputs found to give
+// a final size estimate of the tx size.
 func (tx *Tx) EstimateSizeWithTypes() (*TxSize, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
@@ -475,14 +512,18 @@ func (tx *Tx) estimatedFinalTx() (*Tx, error) {
 	tempTx := tx.Clone()
 
 	for _, in := range tempTx.Inputs {
-		if !in.PreviousTxScript.IsP2PKH() {
+		if in.UnlockingScript != nil && len(*in.UnlockingScript) > 0 {
+			continue
+		}
+		if in.PreviousTxScript == nil {
 			return nil, ErrUnsupportedScript
 		}
-		if in.UnlockingScript == nil || len(*in.UnlockingScript) == 0 {
-			//nolint:lll // insert dummy p2pkh unlocking script (sig + pubkey)
-			dummyUnlockingScript, _ := hex.DecodeString("4830450221009c13cbcbb16f2cfedc7abf3a4af1c3fe77df1180c0e7eee30d9bcc53ebda39da02207b258005f1bc3cf9dffa06edb358d6db2bcfc87f50516fac8e3f4686fc2a03df412103107feff22788a1fc8357240bf450fd7bca4bd45d5f8bac63818c5a7b67b03876")
-			in.UnlockingScript = bscript.NewFromBytes(dummyUnlockingScript)
+		t, ok := bscript.MatchTemplate(in.PreviousTxScript)
+		if !ok {
+			return nil, ErrUnsupportedScript
 		}
+		// insert a dummy unlocking script of the estimated length
+		in.UnlockingScript = bscript.NewFromBytes(make([]byte, t.EstimateUnlockLength(in.PreviousTxScript)))
 	}
 	return tempTx, nil
 }
@@ -500,45 +541,38 @@ type TxFees struct {
 }
 
 // IsFeePaidEnough will calculate the fees that this transaction is paying
-// including the individual fee types (std/data/etc.).
-func (tx *Tx) IsFeePaidEnough(fees *FeeQuote) (bool, error) {
-	expFeesPaid, err := tx.feesPaid(tx.SizeWithTypes(), fees)
-	if err != nil {
-		return false, err
-	}
-	totalInputSatoshis := tx.TotalInputSatoshis()
-	totalOutputSatoshis := tx.TotalOutputSatoshis()
-
-	if totalInputSatoshis < totalOutputSatoshis {
-		return false, nil
-	}
-
-	actualFeePaid := totalInputSatoshis - totalOutputSatoshis
-	return actualFeePaid >= expFeesPaid.TotalFeePaid, nil
+// and return true if they cover the fee required by the fee model.
+func (tx *Tx) IsFeePaidEnough(fees FeeModel) (bool, error) {
+	return tx.isFeePaidEnough(tx.SizeWithTypes(), fees)
 }
 
 // EstimateIsFeePaidEnough will calculate the fees that this transaction is paying
-// including the individual fee types (std/data/etc.), and will add 107 bytes to the unlocking
-// script of any unsigned inputs (only P2PKH for now) found to give a final size
-// estimate of the tx size for fee calculation.
-func (tx *Tx) EstimateIsFeePaidEnough(fees *FeeQuote) (bool, error) {
+// and return true if they cover the fee required by the fee model, and will add
+// the estimated unlocking script length to any unsigned inputs found to give a
+// final size estimate of the tx size for fee calculation.
+func (tx *Tx) EstimateIsFeePaidEnough(fees FeeModel) (bool, error) {
 	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
 		return false, err
 	}
-	expFeesPaid, err := tempTx.feesPaid(tempTx.SizeWithTypes(), fees)
+
+	return tempTx.isFeePaidEnough(tempTx.SizeWithTypes(), fees)
+}
+
+func (tx *Tx) isFeePaidEnough(size *TxSize, fees FeeModel) (bool, error) {
+	expFee, err := computeFee(fees, tx, size)
 	if err != nil {
 		return false, err
 	}
-	totalInputSatoshis := tempTx.TotalInputSatoshis()
-	totalOutputSatoshis := tempTx.TotalOutputSatoshis()
+	totalInputSatoshis := tx.TotalInputSatoshis()
+	totalOutputSatoshis := tx.TotalOutputSatoshis()
 
 	if totalInputSatoshis < totalOutputSatoshis {
 		return false, nil
 	}
 
 	actualFeePaid := totalInputSatoshis - totalOutputSatoshis
-	return actualFeePaid >= expFeesPaid.TotalFeePaid, nil
+	return actualFeePaid >= expFee, nil
 }
 
 // EstimateFeesPaid will estimate how big the tx will be when finalised
@@ -549,41 +583,25 @@ func (tx *Tx) EstimateFeesPaid(fees *FeeQuote) (*TxFees, error) {
 	if err != nil {
 		return nil, err
 	}
-	return tx.feesPaid(size, fees)
+	return fees.txFees(size)
 }
 
-func (tx *Tx) feesPaid(size *TxSize, fees *FeeQuote) (*TxFees, error) {
-	// get fees
-	stdFee, err := fees.Fee(FeeTypeStandard)
-	if err != nil {
-		return nil, err
-	}
-	dataFee, err := fees.Fee(FeeTypeData)
-	if err != nil {
-		return nil, err
-	}
-
-	txFees := &TxFees{
-		StdFeePaid:  size.TotalStdBytes * uint64(stdFee.MiningFee.Satoshis) / uint64(stdFee.MiningFee.Bytes),
-		DataFeePaid: size.TotalDataBytes * uint64(dataFee.MiningFee.Satoshis) / uint64(dataFee.MiningFee.Bytes),
-	}
-	txFees.TotalFeePaid = txFees.StdFeePaid + txFees.DataFeePaid
-	return txFees, nil
-
-}
-
-func (tx *Tx) estimateDeficit(fees *FeeQuote) (uint64, error) {
+func (tx *Tx) estimateDeficit(fees FeeModel) (uint64, error) {
 	totalInputSatoshis := tx.TotalInputSatoshis()
 	totalOutputSatoshis := tx.TotalOutputSatoshis()
 
-	expFeesPaid, err := tx.EstimateFeesPaid(fees)
+	tempTx, err := tx.estimatedFinalTx()
+	if err != nil {
+		return 0, err
+	}
+	expFee, err := computeFee(fees, tempTx, tempTx.SizeWithTypes())
 	if err != nil {
 		return 0, err
 	}
 
-	if totalInputSatoshis > totalOutputSatoshis+expFeesPaid.TotalFeePaid {
+	if totalInputSatoshis > totalOutputSatoshis+expFee {
 		return 0, nil
 	}
 
-	return totalOutputSatoshis + expFeesPaid.TotalFeePaid - totalInputSatoshis, nil
+	return totalOutputSatoshis + expFee - totalInputSatoshis, nil
 }
\ No newline at end of file
@@ -296,8 +296,8 @@ func TestTx_OutputIdx(t *testing.T) {
 			tx: func() *bt.Tx {
 				tx := bt.NewTx()
 				assert.NoError(t, tx.PayToAddress("myUmQeCYxQECGHXbupe539n41u6BTBz1Eh", 1000))
-				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mz", 1000))
-				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mz", 1000))
+				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
 				return tx
 			}(),
 			idx: 0,
@@ -313,15 +313,15 @@ func TestTx_OutputIdx(t *testing.T) {
 			tx: func() *bt.Tx {
 				tx := bt.NewTx()
 				assert.NoError(t, tx.PayToAddress("myUmQeCYxQECGHXbupe539n41u6BTBz1Eh", 1000))
-				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mz", 1000))
-				assert.NoError(t, tx.PayToAddress("mywmGVP89x3DsLNqk3NvctfQy9m9pvt7mz", 1000))
+				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+				assert.NoError(t, tx.PayToAddress("mywmGVP89x3DsLNqk3NvctfQy9m9p9eKy3", 1000))
 				return tx
 			}(),
 			idx: 2,
 			expOutput: &bt.Output{
 				Satoshis: 1000,
 				LockingScript: func() *bscript.Script {
-					s, err := bscript.NewP2PKHFromAddress("mywmGVP89x3DsLNqk3NvctfQy9m9pvt7mz")
+					s, err := bscript.NewP2PKHFromAddress("mywmGVP89x3DsLNqk3NvctfQy9m9p9eKy3")
 					assert.NoError(t, err)
 					return s
 				}(),
@@ -330,8 +330,8 @@ func TestTx_OutputIdx(t *testing.T) {
 			tx: func() *bt.Tx {
 				tx := bt.NewTx()
 				assert.NoError(t, tx.PayToAddress("myUmQeCYxQECGHXbupe539n41u6BTBz1Eh", 1000))
-				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mz", 1000))
-				assert.NoError(t, tx.PayToAddress("mywmGVP89x3DsLNqk3NvctfQy9m9pvt7mz", 1000))
+				assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+				assert.NoError(t, tx.PayToAddress("mywmGVP89x3DsLNqk3NvctfQy9m9p9eKy3", 1000))
 				return tx
 			}(),
 			idx:       5,
@@ -1221,4 +1221,104 @@ func TestFromNodeJS(t *testing.T) {
 	_, err := bt.NewTxFromString("010000000000000000ef01478a4ac0c8e4dae42db983bc720d95ed2099dec4c8c3f2d9eedfbeb74e18cdbb1b0100006b483045022100b05368f9855a28f21d3cb6f3e278752d3c5202f1de927862bbaaf5ef7d67adc50220728d4671cd4c34b1fa28d15d5cd2712b68166ea885522baa35c0b9e399fe9ed74121030d4ad284751daf629af387b1af30e02cf5794139c4e05836b43b1ca376624f7fffffffff10000000000000001976a9140c77a935b45abdcf3e472606d3bc647c5cc0efee88ac01000000000000000070006a0963657274696861736822314c6d763150594d70387339594a556e374d3948565473446b64626155386b514e4a406164386337373536356335363935353261626463636634646362353537376164633936633866613933623332663630373865353664666232326265623766353600000000")
 
 	require.NoError(t, err)
-}
\ No newline at end of file
+}
+
+func TestTx_IsFinal(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lockTime   uint32
+		sequence   uint32
+		height     uint32
+		medianTime uint32
+		exp        bool
+	}{
+		"zero lock time is final": {
+			sequence: 0,
+			height:   100,
+			exp:      true,
+		},
+		"height lock time passed is final": {
+			lockTime: 99,
+			height:   100,
+			exp:      true,
+		},
+		"height lock time not passed is not final": {
+			lockTime: 100,
+			height:   100,
+			exp:      false,
+		},
+		"height lock time not passed with final sequence is final": {
+			lockTime: 100,
+			sequence: bt.MaxTxInSequenceNum,
+			height:   100,
+			exp:      true,
+		},
+		"timestamp lock time uses median time": {
+			lockTime:   1700000000,
+			height:     800000,
+			medianTime: 1700000001,
+			exp:        true,
+		},
+		"timestamp lock time not passed is not final": {
+			lockTime:   1700000000,
+			height:     2000000000,
+			medianTime: 1600000000,
+			exp:        false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := bt.NewTx()
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+			tx.Inputs[0].SequenceNumber = test.sequence
+			tx.LockTime = test.lockTime
+
+			assert.Equal(t, test.exp, tx.IsFinal(test.height, test.medianTime))
+		})
+	}
+}
+
+func TestTx_EstimateSize_Templates(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lockingScript string
+		expSize       int
+		expErr        error
+	}{
+		"p2pkh input": {
+			lockingScript: "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+			expSize:       192,
+		},
+		"cltv p2pkh input": {
+			lockingScript: "03b0710bb17576a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+			expSize:       192,
+		},
+		"2 of 2 multisig input": {
+			lockingScript: "5221023717efaec6761e457f55c8417815505b695209d0bbfed8c3265be425b373c2d62102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d6652ae",
+			expSize:       232,
+		},
+		"non standard input": {
+			lockingScript: "51",
+			expErr:        bt.ErrUnsupportedScript,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := bt.NewTx()
+			assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, test.lockingScript, 1000))
+			assert.NoError(t, tx.PayToAddress("1Jjk1dbo4Yimf1w2NzRqQhGt5CUEST9WZK", 500))
+
+			size, err := tx.EstimateSize()
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expSize, size)
+		})
+	}
+}
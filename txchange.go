@@ -1,6 +1,8 @@
 package bt
 
 import (
+	"sort"
+
 	"github.com/libsv/go-bt/v2/bscript"
 )
 
@@ -11,7 +13,7 @@ const (
 
 // ChangeToAddress calculates the amount of fees needed to cover the transaction
 // and adds the leftover change in a new P2PKH output using the address provided.
-func (tx *Tx) ChangeToAddress(addr string, f *FeeQuote) error {
+func (tx *Tx) ChangeToAddress(addr string, f FeeModel) error {
 	s, err := bscript.NewP2PKHFromAddress(addr)
 	if err != nil {
 		return err
@@ -22,23 +24,105 @@ func (tx *Tx) ChangeToAddress(addr string, f *FeeQuote) error {
 
 // Change calculates the amount of fees needed to cover the transaction
 //  and adds the leftover change in a new output using the script provided.
-func (tx *Tx) Change(s *bscript.Script, f *FeeQuote) error {
-	if _, _, err := tx.change(f, &changeOutput{
-		lockingScript: s,
-		newOutput:     true,
-	}); err != nil {
+func (tx *Tx) Change(s *bscript.Script, f FeeModel) error {
+	available, hasChange, err := tx.change(f, s)
+	if err != nil {
 		return err
 	}
+	if hasChange {
+		tx.AddOutput(&Output{Satoshis: available, LockingScript: s})
+	}
+	return nil
+}
+
+// ChangeSplit calculates the amount of fees needed to cover the transaction and
+// its change outputs, and splits the leftover change equally across n new outputs,
+// paying to the scripts provided in turn. Any remainder of the split is added to the
+// first output.
+//
+// If an equal share of the change would not be above the DustLimit, the change
+// is split across fewer outputs.
+func (tx *Tx) ChangeSplit(scripts []*bscript.Script, n int, f FeeModel) error {
+	if len(scripts) == 0 || n < 1 {
+		return ErrInvalidChangeSplit
+	}
+
+	for ; n > 0; n-- {
+		ss := changeScripts(scripts, n)
+		available, hasChange, err := tx.change(f, ss...)
+		if err != nil {
+			return err
+		}
+		if !hasChange || available/uint64(n) <= DustLimit {
+			continue
+		}
+
+		share := available / uint64(n)
+		for i, s := range ss {
+			satoshis := share
+			if i == 0 {
+				satoshis += available % uint64(n)
+			}
+			tx.AddOutput(&Output{Satoshis: satoshis, LockingScript: s})
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// ChangeDenominations calculates the amount of fees needed to cover the transaction
+// and its change outputs, and splits the leftover change into new outputs of the
+// denominations provided, paying to the scripts provided in turn.
+//
+// As many outputs of each denomination are made as the change allows, largest
+// denomination first, and any remainder above the DustLimit is added in a final
+// output. A remainder not above the DustLimit is left as fee.
+func (tx *Tx) ChangeDenominations(scripts []*bscript.Script, denominations []uint64, f FeeModel) error {
+	if len(scripts) == 0 || len(denominations) == 0 {
+		return ErrInvalidChangeSplit
+	}
+	dd := make([]uint64, 0, len(denominations))
+	for _, d := range denominations {
+		if d <= DustLimit {
+			return ErrInvalidDenomination
+		}
+		dd = append(dd, d)
+	}
+	sort.Slice(dd, func(i, j int) bool { return dd[i] > dd[j] })
+
+	// Each extra output adds to the fee, leaving less change to denominate, so
+	// budget for more outputs until the change denominates into as many.
+	var amounts []uint64
+	for n := 1; ; {
+		available, hasChange, err := tx.change(f, changeScripts(scripts, n)...)
+		if err != nil {
+			return err
+		}
+		if !hasChange {
+			return nil
+		}
+
+		amounts = denominate(available, dd)
+		if len(amounts) <= n {
+			break
+		}
+		n = len(amounts)
+	}
+
+	for i, s := range changeScripts(scripts, len(amounts)) {
+		tx.AddOutput(&Output{Satoshis: amounts[i], LockingScript: s})
+	}
 	return nil
 }
 
 // ChangeToExistingOutput will calculate fees and add them to an output at the index specified (0 based).
 // If an invalid index is supplied and error is returned.
-func (tx *Tx) ChangeToExistingOutput(index uint, f *FeeQuote) error {
+func (tx *Tx) ChangeToExistingOutput(index uint, f FeeModel) error {
 	if int(index) > tx.OutputCount()-1 {
 		return ErrOutputNoExist
 	}
-	available, hasChange, err := tx.change(f, nil)
+	available, hasChange, err := tx.change(f)
 	if err != nil {
 		return err
 	}
@@ -48,14 +132,10 @@ func (tx *Tx) ChangeToExistingOutput(index uint, f *FeeQuote) error {
 	return nil
 }
 
-type changeOutput struct {
-	lockingScript *bscript.Script
-	newOutput     bool
-}
-
-// change will return the amount of satoshis to add to an input after fees are removed.
+// change will return the amount of satoshis available for change after fees are removed,
+// including the fees of new change outputs with the locking scripts provided.
 // True will be returned if change is required for this tx.
-func (tx *Tx) change(f *FeeQuote, output *changeOutput) (uint64, bool, error) {
+func (tx *Tx) change(f FeeModel, outputs ...*bscript.Script) (uint64, bool, error) {
 	inputAmount := tx.TotalInputSatoshis()
 	outputAmount := tx.TotalOutputSatoshis()
 	if inputAmount < outputAmount {
@@ -63,43 +143,48 @@ func (tx *Tx) change(f *FeeQuote, output *changeOutput) (uint64, bool, error) {
 	}
 
 	available := inputAmount - outputAmount
-	size, err := tx.EstimateSizeWithTypes()
+
+	// price the tx with the new outputs added
+	tempTx, err := tx.estimatedFinalTx()
 	if err != nil {
 		return 0, false, err
 	}
-	stdFee, err := f.Fee(FeeTypeStandard)
-	if err != nil {
-		return 0, false, err
+	for _, s := range outputs {
+		tempTx.AddOutput(&Output{LockingScript: s})
 	}
-	dataFee, err := f.Fee(FeeTypeData)
+	txFees, err := computeFee(f, tempTx, tempTx.SizeWithTypes())
 	if err != nil {
 		return 0, false, err
 	}
-	varIntUpper := VarInt(tx.OutputCount()).UpperLimitInc()
-	if varIntUpper == -1 {
-		return 0, false, nil
-	}
-	changeOutputFee := varIntUpper
-	changeP2pkhByteLen := uint64(0)
-	if output != nil && output.newOutput {
-		changeP2pkhByteLen = uint64(8 + 1 + 25)
-	}
-
-	sFees := (size.TotalStdBytes + changeP2pkhByteLen) * uint64(stdFee.MiningFee.Satoshis) / uint64(stdFee.MiningFee.Bytes)
-	dFees := size.TotalDataBytes * uint64(dataFee.MiningFee.Satoshis) / uint64(dataFee.MiningFee.Bytes)
-	txFees := sFees + dFees + uint64(changeOutputFee)
 
 	// not enough to add change, no change to add
 	if available <= txFees || available-txFees <= DustLimit {
 		return 0, false, nil
 	}
 
-	// if we want to add to a new output, set
-	// newOutput to true, this will add the calculated change
-	// into a new output
-	available -= txFees
-	if output != nil && output.newOutput {
-		tx.AddOutput(&Output{Satoshis: available, LockingScript: output.lockingScript})
+	return available - txFees, true, nil
+}
+
+// changeScripts returns n scripts, taking from scripts in turn.
+func changeScripts(scripts []*bscript.Script, n int) []*bscript.Script {
+	ss := make([]*bscript.Script, 0, n)
+	for i := 0; i < n; i++ {
+		ss = append(ss, scripts[i%len(scripts)])
+	}
+	return ss
+}
+
+// denominate splits satoshis into as many of each denomination as possible, largest
+// first, followed by any remainder above the DustLimit.
+func denominate(satoshis uint64, denominations []uint64) []uint64 {
+	var amounts []uint64
+	for _, d := range denominations {
+		for ; satoshis >= d; satoshis -= d {
+			amounts = append(amounts, d)
+		}
+	}
+	if satoshis > DustLimit {
+		amounts = append(amounts, satoshis)
 	}
-	return available, true, nil
+	return amounts
 }
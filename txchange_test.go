@@ -7,6 +7,7 @@ import (
 
 	. "github.com/libsv/go-bk/wif"
 	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
 	"github.com/libsv/go-bt/v2/unlocker"
 	"github.com/stretchr/testify/assert"
 )
@@ -388,4 +389,182 @@ func TestTx_ChangeToOutput(t *testing.T) {
 			assert.Equal(t, test.expChangeOutput, test.tx.Outputs[test.index].Satoshis)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestTx_ChangeSplit(t *testing.T) {
+	t.Parallel()
+
+	s1, err := bscript.NewP2PKHFromAddress("mwV3YgnowbJJB3LcyCuqiKpdivvNNFiK7M")
+	assert.NoError(t, err)
+	s2, err := bscript.NewP2PKHFromAddress("mxAoAyZFXX6LZBWhoam3vjm6xt9NxPQ15f")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input      uint64
+		scripts    []*bscript.Script
+		n          int
+		expOutputs []uint64
+		expScripts []*bscript.Script
+		err        error
+	}{
+		"change is split equally across the scripts in turn": {
+			input:      10000,
+			scripts:    []*bscript.Script{s1, s2},
+			n:          3,
+			expOutputs: []uint64{2951, 2951, 2951},
+			expScripts: []*bscript.Script{s1, s2, s1},
+		},
+		"remainder is added to the first output": {
+			input:      10001,
+			scripts:    []*bscript.Script{s1},
+			n:          2,
+			expOutputs: []uint64{4436, 4435},
+			expScripts: []*bscript.Script{s1, s1},
+		},
+		"fewer outputs are made rather than dust": {
+			input:      1168,
+			scripts:    []*bscript.Script{s1},
+			n:          4,
+			expOutputs: []uint64{7, 7, 7},
+			expScripts: []*bscript.Script{s1, s1, s1},
+		},
+		"no change": {
+			input:   1096,
+			scripts: []*bscript.Script{s1},
+			n:       2,
+		},
+		"no scripts": {
+			input: 10000,
+			n:     2,
+			err:   bt.ErrInvalidChangeSplit,
+		},
+		"no outputs": {
+			input:   10000,
+			scripts: []*bscript.Script{s1},
+			err:     bt.ErrInvalidChangeSplit,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := bt.NewTx()
+			assert.NoError(t, tx.From(
+				"07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b",
+				0,
+				"76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac",
+				test.input))
+			assert.NoError(t, tx.PayToAddress("mxAoAyZFXX6LZBWhoam3vjm6xt9NxPQ15f", 1000))
+
+			err := tx.ChangeSplit(test.scripts, test.n, bt.NewFeeQuote())
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var outputs []uint64
+			var scripts []*bscript.Script
+			for _, o := range tx.Outputs[1:] {
+				outputs = append(outputs, o.Satoshis)
+				scripts = append(scripts, o.LockingScript)
+			}
+			assert.Equal(t, test.expOutputs, outputs)
+			assert.Equal(t, test.expScripts, scripts)
+
+			ok, err := tx.EstimateIsFeePaidEnough(bt.NewFeeQuote())
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestTx_ChangeDenominations(t *testing.T) {
+	t.Parallel()
+
+	s1, err := bscript.NewP2PKHFromAddress("mwV3YgnowbJJB3LcyCuqiKpdivvNNFiK7M")
+	assert.NoError(t, err)
+	s2, err := bscript.NewP2PKHFromAddress("mxAoAyZFXX6LZBWhoam3vjm6xt9NxPQ15f")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		input         uint64
+		scripts       []*bscript.Script
+		denominations []uint64
+		expOutputs    []uint64
+		expScripts    []*bscript.Script
+		err           error
+	}{
+		"change is denominated largest first with a remainder": {
+			input:         10000,
+			scripts:       []*bscript.Script{s1, s2},
+			denominations: []uint64{1000, 5000},
+			expOutputs:    []uint64{5000, 1000, 1000, 1000, 819},
+			expScripts:    []*bscript.Script{s1, s2, s1, s2, s1},
+		},
+		"fees of the extra outputs are accounted for": {
+			// 8113 - 113 of fees for one output would denominate exactly,
+			// but not after the fees for the extra outputs
+			input:         9113,
+			scripts:       []*bscript.Script{s1},
+			denominations: []uint64{2000},
+			expOutputs:    []uint64{2000, 2000, 2000, 1949},
+			expScripts:    []*bscript.Script{s1, s1, s1, s1},
+		},
+		"change smaller than the denominations": {
+			input:         1500,
+			scripts:       []*bscript.Script{s1},
+			denominations: []uint64{1000},
+			expOutputs:    []uint64{387},
+			expScripts:    []*bscript.Script{s1},
+		},
+		"invalid denomination": {
+			input:         10000,
+			scripts:       []*bscript.Script{s1},
+			denominations: []uint64{1000, 1},
+			err:           bt.ErrInvalidDenomination,
+		},
+		"no denominations": {
+			input:   10000,
+			scripts: []*bscript.Script{s1},
+			err:     bt.ErrInvalidChangeSplit,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tx := bt.NewTx()
+			assert.NoError(t, tx.From(
+				"07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b",
+				0,
+				"76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac",
+				test.input))
+			assert.NoError(t, tx.PayToAddress("mxAoAyZFXX6LZBWhoam3vjm6xt9NxPQ15f", 1000))
+
+			err := tx.ChangeDenominations(test.scripts, test.denominations, bt.NewFeeQuote())
+			if test.err != nil {
+				assert.ErrorIs(t, err, test.err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var outputs []uint64
+			var scripts []*bscript.Script
+			for _, o := range tx.Outputs[1:] {
+				outputs = append(outputs, o.Satoshis)
+				scripts = append(scripts, o.LockingScript)
+			}
+			assert.Equal(t, test.expOutputs, outputs)
+			assert.Equal(t, test.expScripts, scripts)
+
+			ok, err := tx.EstimateIsFeePaidEnough(bt.NewFeeQuote())
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package bt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/pkg/errors"
+)
+
+// Minimum sizes in bytes of the elements of a tx, used to reject counts which
+// cannot fit within the bytes remaining before anything is allocated for them.
+const (
+	// minInputSize is an outpoint, an empty script and a sequence number.
+	minInputSize = 32 + 4 + 1 + 4
+	// minExtendedInputSize adds the previous satoshis and an empty previous script.
+	minExtendedInputSize = minInputSize + 8 + 1
+	// minOutputSize is the satoshis and an empty script.
+	minOutputSize = 8 + 1
+	// minTxSize is the version, no inputs, no outputs and the locktime.
+	minTxSize = 4 + 1 + 1 + 4
+)
+
+// DecodeOptionFunc for setting tx decoding options.
+type DecodeOptionFunc func(o *decodeOpts)
+
+type decodeOpts struct {
+	strict bool
+}
+
+// WithStrictDecoding configure decoding to only accept a canonically encoded tx.
+// VarInts must be minimally encoded and, when decoding a single tx from bytes, no
+// bytes may follow the tx.
+//
+// Counts and script lengths must fit within the bytes remaining, which are checked
+// before anything is allocated, when the reader reports its length, as a bytes.Reader
+// does. Otherwise, scripts are read as their bytes arrive rather than allocated up
+// front, so a reader which ends early cannot drive a large allocation.
+//
+// Any error is returned as a DecodeError, holding the byte offset of the field at fault.
+func WithStrictDecoding() DecodeOptionFunc {
+	return func(o *decodeOpts) {
+		o.strict = true
+	}
+}
+
+// DecodeError is returned by strict decoding when a tx is malformed, with the
+// offset in bytes of the field at fault. It matches the error of the fault, such
+// as ErrNonMinimalVarInt or io.ErrUnexpectedEOF, with errors.Is.
+type DecodeError struct {
+	Offset int
+	Field  string
+	Err    error
+}
+
+// Error returns the error message.
+func (e DecodeError) Error() string {
+	return fmt.Sprintf("%s at offset %d: %s", e.Field, e.Offset, e.Err)
+}
+
+// Unwrap returns the error of the fault.
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// txDecoder reads the fields of a tx from r, counting the bytes read. When strict,
+// a field which is not canonically encoded is rejected with a DecodeError. Otherwise
+// the errors of a short read are wrapped with the field size read, msg.
+type txDecoder struct {
+	r      io.Reader
+	n      int64
+	strict bool
+}
+
+func decodeOptions(opts []DecodeOptionFunc) *decodeOpts {
+	o := &decodeOpts{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func newTxDecoder(r io.Reader, opts []DecodeOptionFunc) *txDecoder {
+	return &txDecoder{r: r, strict: decodeOptions(opts).strict}
+}
+
+// remaining returns the bytes remaining in r, and true, if r reports its length.
+func (d *txDecoder) remaining() (uint64, bool) {
+	l, ok := d.r.(interface{ Len() int })
+	if !ok {
+		return 0, false
+	}
+
+	return uint64(l.Len()), true
+}
+
+// fail returns err, raised reading field from offset off, as a DecodeError.
+func (d *txDecoder) fail(off int64, field string, err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return DecodeError{Offset: int(off), Field: field, Err: err}
+}
+
+// field returns the name of the i'th element of parent, such as "input 0", to
+// report in a DecodeError. It is only named when strict.
+func (d *txDecoder) field(parent, name string, i uint64) string {
+	if !d.strict {
+		return ""
+	}
+
+	return joinField(parent, fmt.Sprintf("%s %d", name, i))
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + " " + name
+}
+
+func (d *txDecoder) readFull(bb []byte, field, msg string) error {
+	off := d.n
+	n, err := io.ReadFull(d.r, bb)
+	d.n += int64(n)
+	switch {
+	case err == nil:
+		return nil
+	case d.strict:
+		return d.fail(off, field, err)
+	case msg == "":
+		return err
+	}
+
+	return errors.Wrapf(err, "%s: got %d bytes", msg, n)
+}
+
+// varInt reads a VarInt, rejecting when strict any which could have been encoded
+// in fewer bytes.
+func (d *txDecoder) varInt(field string) (VarInt, error) {
+	off := d.n
+	var v VarInt
+	n, err := v.ReadFrom(d.r)
+	d.n += n
+	if !d.strict {
+		return v, err
+	}
+	if err != nil {
+		return 0, d.fail(off, field, err)
+	}
+	if int(n) != v.Length() {
+		return 0, DecodeError{Offset: int(off), Field: field, Err: ErrNonMinimalVarInt}
+	}
+
+	return v, nil
+}
+
+// count reads a count of elements each of at least size bytes, rejecting when
+// strict counts of more elements than can fit within the bytes remaining.
+func (d *txDecoder) count(field string, size uint64) (VarInt, error) {
+	off := d.n
+	v, err := d.varInt(field)
+	if err != nil || !d.strict {
+		return v, err
+	}
+	if rem, ok := d.remaining(); ok && uint64(v) > rem/size {
+		return 0, DecodeError{Offset: int(off), Field: field, Err: ErrCountTooLarge}
+	}
+
+	return v, nil
+}
+
+// capacity returns the capacity to allocate up front for count elements, which
+// is none when strict and the count could not be checked against the bytes remaining.
+func (d *txDecoder) capacity(count VarInt) uint64 {
+	if _, ok := d.remaining(); d.strict && !ok {
+		return 0
+	}
+
+	return uint64(count)
+}
+
+// script reads a script prefixed by its length, rejecting when strict lengths
+// greater than the bytes remaining.
+func (d *txDecoder) script(field, msg string) (*bscript.Script, error) {
+	off := d.n
+	l, err := d.varInt(joinField(field, "length"))
+	if err != nil {
+		return nil, err
+	}
+
+	if d.strict {
+		rem, ok := d.remaining()
+		if (ok && uint64(l) > rem) || uint64(l) > math.MaxInt64 {
+			return nil, DecodeError{Offset: int(off), Field: joinField(field, "length"), Err: ErrScriptTooLong}
+		}
+		if !ok {
+			start := d.n
+			var buf bytes.Buffer
+			n, err := io.CopyN(&buf, d.r, int64(l))
+			d.n += n
+			if err != nil {
+				return nil, d.fail(start, field, err)
+			}
+
+			return bscript.NewFromBytes(buf.Bytes()), nil
+		}
+	}
+
+	start := d.n
+	script := make([]byte, l)
+	n, err := io.ReadFull(d.r, script)
+	d.n += int64(n)
+	if err != nil {
+		if d.strict {
+			return nil, d.fail(start, field, err)
+		}
+		return nil, errors.Wrapf(err, "%s(%d): got %d bytes", msg, l, n)
+	}
+
+	return bscript.NewFromBytes(script), nil
+}
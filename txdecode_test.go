@@ -0,0 +1,206 @@
+package bt_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTxFromBytes_StrictDecoding(t *testing.T) {
+	t.Parallel()
+
+	const txHex = "0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000"
+	b, err := hex.DecodeString(txHex)
+	assert.NoError(t, err)
+
+	// splice returns b with the bytes from offset to offset+n replaced by r.
+	splice := func(offset, n int, r string) []byte {
+		rb, err := hex.DecodeString(r)
+		assert.NoError(t, err)
+		bb := append([]byte{}, b[:offset]...)
+		bb = append(bb, rb...)
+		return append(bb, b[offset+n:]...)
+	}
+
+	tests := map[string]struct {
+		b         []byte
+		expErr    error
+		expOffset int
+		expField  string
+	}{
+		"canonical tx is decoded": {
+			b: b,
+		},
+		"non minimal input count is rejected": {
+			b:         splice(4, 1, "fd0100"),
+			expErr:    bt.ErrNonMinimalVarInt,
+			expOffset: 4,
+			expField:  "input count",
+		},
+		"non minimal script length is rejected": {
+			b:         splice(41, 1, "fe6a000000"),
+			expErr:    bt.ErrNonMinimalVarInt,
+			expOffset: 41,
+			expField:  "input 0 unlocking script length",
+		},
+		"input count beyond the bytes remaining is rejected": {
+			b:         splice(4, 1, "feffffff7f"),
+			expErr:    bt.ErrCountTooLarge,
+			expOffset: 4,
+			expField:  "input count",
+		},
+		"output count beyond the bytes remaining is rejected": {
+			b:         splice(152, 1, "ff0000000001000000"),
+			expErr:    bt.ErrCountTooLarge,
+			expOffset: 152,
+			expField:  "output count",
+		},
+		"script length beyond the bytes remaining is rejected": {
+			b:         splice(41, 1, "fd0010"),
+			expErr:    bt.ErrScriptTooLong,
+			expOffset: 41,
+			expField:  "input 0 unlocking script length",
+		},
+		"trailing bytes are rejected": {
+			b:         append(append([]byte{}, b...), 0x00),
+			expErr:    bt.ErrTrailingBytes,
+			expOffset: len(b),
+			expField:  "trailing data",
+		},
+		"truncated tx is rejected": {
+			b:         b[:len(b)-2],
+			expErr:    io.ErrUnexpectedEOF,
+			expOffset: len(b) - 4,
+			expField:  "locktime",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx, err := bt.NewTxFromBytes(test.b, bt.WithStrictDecoding())
+			if test.expErr == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, txHex, tx.String())
+				return
+			}
+
+			assert.ErrorIs(t, err, test.expErr)
+			var decodeErr bt.DecodeError
+			assert.True(t, errors.As(err, &decodeErr))
+			assert.Equal(t, test.expOffset, decodeErr.Offset)
+			assert.Equal(t, test.expField, decodeErr.Field)
+		})
+	}
+
+	t.Run("non minimal varints are accepted without strict decoding", func(t *testing.T) {
+		tx, err := bt.NewTxFromBytes(splice(4, 1, "fd0100"))
+		assert.NoError(t, err)
+		assert.Equal(t, txHex, tx.String())
+	})
+}
+
+func TestNewTxFromBytes_StrictDecodingExtended(t *testing.T) {
+	t.Parallel()
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 10000))
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+
+	tx2, err := bt.NewTxFromBytes(tx.ExtendedBytes(), bt.WithStrictDecoding())
+	assert.NoError(t, err)
+	assert.Equal(t, tx.ExtendedBytes(), tx2.ExtendedBytes())
+	assert.Equal(t, uint64(10000), tx2.Inputs[0].PreviousTxSatoshis)
+
+	// the input count follows the extended format marker
+	bb := tx.ExtendedBytes()
+	bb[10] = 0x03
+	_, err = bt.NewTxFromBytes(bb, bt.WithStrictDecoding())
+	assert.ErrorIs(t, err, bt.ErrCountTooLarge)
+}
+
+func TestTx_ReadFromWithOptions_StrictDecoding(t *testing.T) {
+	t.Parallel()
+
+	const txHex = "0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000"
+	b, err := hex.DecodeString(txHex)
+	assert.NoError(t, err)
+
+	// the bufio.Reader does not report the bytes remaining
+	read := func(bb []byte) (*bt.Tx, int64, error) {
+		tx := &bt.Tx{}
+		n, err := tx.ReadFromWithOptions(bufio.NewReader(bytes.NewReader(bb)), bt.WithStrictDecoding())
+		return tx, n, err
+	}
+
+	t.Run("canonical tx is decoded", func(t *testing.T) {
+		tx, n, err := read(b)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(b)), n)
+		assert.Equal(t, txHex, tx.String())
+	})
+
+	t.Run("non minimal varint is rejected", func(t *testing.T) {
+		bb := append([]byte{}, b[:4]...)
+		bb = append(bb, 0xfd, 0x01, 0x00)
+		_, _, err := read(append(bb, b[5:]...))
+		assert.ErrorIs(t, err, bt.ErrNonMinimalVarInt)
+		var decodeErr bt.DecodeError
+		assert.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, 4, decodeErr.Offset)
+	})
+
+	t.Run("script longer than the reader is read until it ends", func(t *testing.T) {
+		bb := append([]byte{}, b[:41]...)
+		bb = append(bb, 0xfe, 0x00, 0x00, 0x00, 0x10)
+		_, _, err := read(append(bb, b[42:]...))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		var decodeErr bt.DecodeError
+		assert.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, 46, decodeErr.Offset)
+		assert.Equal(t, "input 0 unlocking script", decodeErr.Field)
+	})
+}
+
+func TestTxs_ReadFromWithOptions_StrictDecoding(t *testing.T) {
+	t.Parallel()
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.From("3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5", 0, "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac", 10000))
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+
+	block := append([]byte{0x02}, tx.Bytes()...)
+	block = append(block, tx.Bytes()...)
+
+	t.Run("txs are decoded", func(t *testing.T) {
+		txs := bt.Txs{}
+		n, err := txs.ReadFromWithOptions(bytes.NewReader(block), bt.WithStrictDecoding())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(block)), n)
+		assert.Len(t, txs, 2)
+		assert.Equal(t, tx.String(), txs[1].String())
+	})
+
+	t.Run("tx count beyond the bytes remaining is rejected", func(t *testing.T) {
+		bb := append([]byte{0xfe, 0xff, 0xff, 0xff, 0x7f}, block[1:]...)
+		_, err := (&bt.Txs{}).ReadFromWithOptions(bytes.NewReader(bb), bt.WithStrictDecoding())
+		assert.ErrorIs(t, err, bt.ErrCountTooLarge)
+	})
+
+	t.Run("offsets are within the block", func(t *testing.T) {
+		bb := append([]byte{}, block[:1+len(tx.Bytes())+4]...)
+		bb = append(bb, 0xfd, 0x01, 0x00)
+		bb = append(bb, block[1+len(tx.Bytes())+5:]...)
+		_, err := (&bt.Txs{}).ReadFromWithOptions(bytes.NewReader(bb), bt.WithStrictDecoding())
+		assert.ErrorIs(t, err, bt.ErrNonMinimalVarInt)
+		var decodeErr bt.DecodeError
+		assert.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, 1+len(tx.Bytes())+4, decodeErr.Offset)
+		assert.Equal(t, "tx 1 input count", decodeErr.Field)
+	})
+}
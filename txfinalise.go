@@ -0,0 +1,145 @@
+package bt
+
+import (
+	"context"
+
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+// MaxFinaliseRounds is the number of times Finalise will sign the tx while
+// settling its fee, before giving up with ErrFeeNotSettled if no change amount
+// has paid enough fee.
+const MaxFinaliseRounds = 10
+
+// Finalise funds, adds change to, and signs the tx in one call, so that the fee
+// paid is exactly the fee required by the fee model for the signed tx.
+//
+// The tx is funded from next, as with Fund, unless next is nil, and any change is
+// added in an output paying to the change script, as with Change. The fee of both
+// is estimated, so once the inputs have been signed with the unlockers from ug the
+// fee of the signed tx is measured, and the change adjusted to pay exactly that fee.
+// As changing the change output changes the signatures, and so maybe the size of
+// the tx, the inputs are then signed again, until the fee is settled. Should the
+// signed size of the tx mean that no change amount pays the fee exactly, the
+// amount overpaying the least is used.
+//
+// If change is nil, or the surplus would leave change not above the DustLimit,
+// no change output is kept and the surplus is left as fee. If the signed tx does
+// not pay for its outputs and the fee required, it is funded further from next, or,
+// if next is nil, ErrInsufficientInputs returned should the inputs not cover the
+// outputs, and ErrInsufficientFunds should they not cover the fee.
+//
+// Example usage:
+//
+//	if err := tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000); err != nil {
+//	    return err
+//	}
+//	if err := tx.Finalise(ctx, bt.NewFeeQuote(), utxoGetter, changeScript, &unlocker.Getter{PrivateKey: key}); err != nil {
+//	    return err
+//	}
+func (tx *Tx) Finalise(ctx context.Context, fees FeeModel, next UTXOGetterFunc, change *bscript.Script, ug UnlockerGetter) error {
+	if next != nil {
+		if err := tx.Fund(ctx, fees, next); err != nil {
+			return err
+		}
+	}
+	changeIdx, err := tx.finaliseChange(fees, change)
+	if err != nil {
+		return err
+	}
+
+	// The signed size may differ for each change amount, so that no amount pays
+	// exactly the fee required. Once an amount repeats, settle for the amount
+	// overpaying the least.
+	var settled *finaliseState
+	seen := map[uint64]bool{}
+	for i := 0; i < MaxFinaliseRounds; i++ {
+		if err = tx.FillAllInputs(ctx, ug); err != nil {
+			return err
+		}
+
+		inputs, outputs := tx.TotalInputSatoshis(), tx.TotalOutputSatoshis()
+		if inputs < outputs {
+			if next == nil {
+				return ErrInsufficientInputs
+			}
+			if err = tx.Fund(ctx, fees, next); err != nil {
+				return err
+			}
+			continue
+		}
+		required, err := computeFee(fees, tx, tx.SizeWithTypes())
+		if err != nil {
+			return err
+		}
+		paid := inputs - outputs
+		if paid == required {
+			return nil
+		}
+
+		if changeIdx < 0 {
+			if paid < required {
+				if next == nil {
+					return ErrInsufficientFunds
+				}
+				if err = tx.Fund(ctx, fees, next); err != nil {
+					return err
+				}
+			}
+			// the signed tx may now have room for change
+			if changeIdx, err = tx.finaliseChange(fees, change); err != nil {
+				return err
+			}
+			if changeIdx < 0 && paid > required {
+				return nil
+			}
+			continue
+		}
+
+		o := tx.Outputs[changeIdx]
+		if paid > required && (settled == nil || paid-required < settled.overpaid) {
+			settled = &finaliseState{change: o.Satoshis, overpaid: paid - required}
+		}
+		seen[o.Satoshis] = true
+		if o.Satoshis+paid <= required+DustLimit {
+			tx.Outputs = append(tx.Outputs[:changeIdx], tx.Outputs[changeIdx+1:]...)
+			changeIdx = -1
+			settled = nil
+			seen = map[uint64]bool{}
+			continue
+		}
+		o.Satoshis = o.Satoshis + paid - required
+		if seen[o.Satoshis] {
+			break
+		}
+	}
+	if settled == nil {
+		return ErrFeeNotSettled
+	}
+
+	tx.Outputs[changeIdx].Satoshis = settled.change
+	return tx.FillAllInputs(ctx, ug)
+}
+
+// finaliseState is a change amount tried by Finalise, and the fee it overpaid.
+type finaliseState struct {
+	change   uint64
+	overpaid uint64
+}
+
+// finaliseChange adds change to the tx, returning the index of the change output,
+// or -1 if no change was added.
+func (tx *Tx) finaliseChange(fees FeeModel, change *bscript.Script) (int, error) {
+	if change == nil {
+		return -1, nil
+	}
+	n := tx.OutputCount()
+	if err := tx.Change(change, fees); err != nil {
+		return -1, err
+	}
+	if tx.OutputCount() == n {
+		return -1, nil
+	}
+
+	return n, nil
+}
@@ -0,0 +1,145 @@
+package bt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/wif"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_Finalise(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("L3MhnEn1pLWcggeYLk9jdkvA2wUK1iWwwrGkBbgQRqv6HPCdRxuw")
+	assert.NoError(t, err)
+	lockingScript, err := bscript.NewP2PKHFromPubKeyEC(w.PrivKey.PubKey())
+	assert.NoError(t, err)
+	changeScript, err := bscript.NewP2PKHFromAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi")
+	assert.NoError(t, err)
+	ug := &unlocker.Getter{PrivateKey: w.PrivKey}
+
+	nodeFq := bt.NewFeeQuote()
+	for _, ft := range []bt.FeeType{bt.FeeTypeStandard, bt.FeeTypeData} {
+		nodeFq.AddQuote(ft, &bt.Fee{FeeType: ft, MiningFee: bt.FeeUnit{Satoshis: 50, Bytes: 1000}})
+	}
+	nodeFq.UpdateFeeCalculation(bt.FeeCalculationNode)
+
+	utxos := func(satoshis ...uint64) bt.UTXOGetterFunc {
+		var i int
+		return func(ctx context.Context, deficit uint64) ([]*bt.UTXO, error) {
+			if i == len(satoshis) {
+				return nil, bt.ErrNoUTXO
+			}
+			u := &bt.UTXO{
+				TxID:          make([]byte, 32),
+				Vout:          uint32(i),
+				LockingScript: lockingScript,
+				Satoshis:      satoshis[i],
+			}
+			i++
+			return []*bt.UTXO{u}, nil
+		}
+	}
+
+	tests := map[string]struct {
+		fees      bt.FeeModel
+		utxos     bt.UTXOGetterFunc
+		change    *bscript.Script
+		payment   uint64
+		expInputs int
+		expChange bool
+		// the fee overpaid when no change amount gives a signed size paying exactly
+		expOverpaid uint64
+		expErr      error
+	}{
+		"single input with change": {
+			fees:        bt.NewFeeQuote(),
+			utxos:       utxos(10000),
+			change:      changeScript,
+			payment:     1000,
+			expInputs:   1,
+			expChange:   true,
+			expOverpaid: 1,
+		},
+		"many inputs with change": {
+			fees:      bt.NewFeeQuote(),
+			utxos:     utxos(1000, 1000, 1000, 1000, 1000),
+			change:    changeScript,
+			payment:   4000,
+			expInputs: 5,
+			expChange: true,
+		},
+		"node fee calculation": {
+			fees:      nodeFq,
+			utxos:     utxos(1000, 1000, 1000),
+			change:    changeScript,
+			payment:   2500,
+			expInputs: 3,
+			expChange: true,
+		},
+		"no change script": {
+			fees:      bt.NewFeeQuote(),
+			utxos:     utxos(10000),
+			payment:   1000,
+			expInputs: 1,
+		},
+		"change which would be dust is left as fee": {
+			fees:      bt.NewFeeQuote(),
+			utxos:     utxos(1113),
+			change:    changeScript,
+			payment:   1000,
+			expInputs: 1,
+		},
+		"insufficient funds": {
+			fees:    bt.NewFeeQuote(),
+			utxos:   utxos(500, 500),
+			change:  changeScript,
+			payment: 1000,
+			expErr:  bt.ErrInsufficientFunds,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := bt.NewTx()
+			assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", test.payment))
+
+			err := tx.Finalise(context.Background(), test.fees, test.utxos, test.change, ug)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expInputs, tx.InputCount())
+			for _, in := range tx.Inputs {
+				assert.NotEmpty(t, *in.UnlockingScript)
+			}
+
+			paid := tx.TotalInputSatoshis() - tx.TotalOutputSatoshis()
+			required, err := test.fees.ComputeFee(tx, tx.SizeWithTypes())
+			assert.NoError(t, err)
+			if test.expChange {
+				assert.Equal(t, 2, tx.OutputCount())
+				assert.Equal(t, required+test.expOverpaid, paid)
+			} else {
+				assert.Equal(t, 1, tx.OutputCount())
+				assert.GreaterOrEqual(t, paid, required)
+			}
+
+			ok, err := tx.IsFeePaidEnough(test.fees)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+
+	t.Run("without a utxo getter the tx must already be funded", func(t *testing.T) {
+		tx := bt.NewTx()
+		assert.NoError(t, tx.FromUTXOs(&bt.UTXO{TxID: make([]byte, 32), LockingScript: lockingScript, Satoshis: 1090}))
+		assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+
+		assert.ErrorIs(t, tx.Finalise(context.Background(), bt.NewFeeQuote(), nil, changeScript, ug), bt.ErrInsufficientFunds)
+	})
+}
@@ -105,7 +105,8 @@ func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
 }
 
 // Fund continuously calls the provided bt.UTXOGetterFunc, adding each returned input
-// as an input via tx.From(...), until it is estimated that inputs cover the outputs + fees.
+// as an input via tx.From(...), until it is estimated that inputs cover the outputs + fees,
+// as computed by the fee model.
 //
 // After completion, the receiver is ready for `Change(...)` to be called, and then be signed.
 // Note, this function works under the assumption that receiver *bt.Tx already has all the outputs
@@ -134,7 +135,7 @@ func (tx *Tx) FromUTXOs(utxos ...*UTXO) error {
 //	    if errors.Is(err, bt.ErrInsufficientFunds) { /* handle */ }
 //	    return err
 //	}
-func (tx *Tx) Fund(ctx context.Context, fq *FeeQuote, next UTXOGetterFunc) error {
+func (tx *Tx) Fund(ctx context.Context, fq FeeModel, next UTXOGetterFunc) error {
 	deficit, err := tx.estimateDeficit(fq)
 	if err != nil {
 		return err
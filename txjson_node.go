@@ -4,12 +4,21 @@ package bt
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/crypto"
 
 	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
 )
 
 type nodeTxWrapper struct {
 	*Tx
+	net *network.Params
 }
 
 type nodeTxsWrapper Txs
@@ -18,36 +27,85 @@ type nodeOutputWrapper struct {
 	*Output
 }
 
+// nodeTxJSON is a tx as output by the node, with the fields in the node's order.
 type nodeTxJSON struct {
-	Version  uint32            `json:"version"`
-	LockTime uint32            `json:"locktime"`
 	TxID     string            `json:"txid"`
 	Hash     string            `json:"hash"`
+	Version  uint32            `json:"version"`
 	Size     int               `json:"size"`
-	Hex      string            `json:"hex"`
+	LockTime uint32            `json:"locktime"`
 	Inputs   []*nodeInputJSON  `json:"vin"`
 	Outputs  []*nodeOutputJSON `json:"vout"`
+	Hex      string            `json:"hex"`
 }
 
 type nodeInputJSON struct {
-	ScriptSig *struct {
-		Asm string `json:"asm"`
-		Hex string `json:"hex"`
-	} `json:"scriptSig,omitempty"`
-	TxID     string `json:"txid"`
-	Vout     uint32 `json:"vout"`
-	Sequence uint32 `json:"sequence"`
+	Coinbase  string             `json:"coinbase,omitempty"`
+	TxID      string             `json:"txid"`
+	Vout      uint32             `json:"vout"`
+	ScriptSig *nodeScriptSigJSON `json:"scriptSig,omitempty"`
+	Sequence  uint32             `json:"sequence"`
+
+	// coinbase is set if the input is of a coinbase tx, which the node
+	// outputs with only the coinbase and sequence fields.
+	coinbase bool
+}
+
+type nodeScriptSigJSON struct {
+	Asm string `json:"asm"`
+	Hex string `json:"hex"`
 }
 
 type nodeOutputJSON struct {
-	Value        float64 `json:"value"`
-	Index        int     `json:"n"`
-	ScriptPubKey *struct {
-		Asm     string `json:"asm"`
-		Hex     string `json:"hex"`
-		ReqSigs int    `json:"reqSigs,omitempty"`
-		Type    string `json:"type"`
-	} `json:"scriptPubKey,omitempty"`
+	Value        nodeAmount            `json:"value"`
+	Index        int                   `json:"n"`
+	ScriptPubKey *nodeScriptPubKeyJSON `json:"scriptPubKey,omitempty"`
+}
+
+type nodeScriptPubKeyJSON struct {
+	Asm       string   `json:"asm"`
+	Hex       string   `json:"hex"`
+	ReqSigs   int      `json:"reqSigs,omitempty"`
+	Type      string   `json:"type"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// nodeAmount is an amount of satoshis, which the node outputs in BSV to 8 decimal places.
+type nodeAmount uint64
+
+func (a nodeAmount) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%08d", a/1e8, a%1e8)), nil
+}
+
+func (a *nodeAmount) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*a = nodeAmount(math.Round(f * 1e8))
+		return nil
+	}
+
+	// parse the decimal exactly, rather than through a float
+	parts := strings.SplitN(s, ".", 2)
+	bsv, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return err
+	}
+	var sats uint64
+	if len(parts) == 2 {
+		if len(parts[1]) > 8 {
+			return fmt.Errorf("value %s has more than 8 decimal places", s)
+		}
+		if sats, err = strconv.ParseUint(parts[1]+strings.Repeat("0", 8-len(parts[1])), 10, 64); err != nil {
+			return err
+		}
+	}
+	*a = nodeAmount(bsv*1e8 + sats)
+
+	return nil
 }
 
 func (n *nodeTxWrapper) MarshalJSON() ([]byte, error) {
@@ -58,33 +116,42 @@ func (n *nodeTxWrapper) MarshalJSON() ([]byte, error) {
 	oo := make([]*nodeOutputJSON, 0, len(tx.Outputs))
 	for i, o := range tx.Outputs {
 		out := &nodeOutputJSON{}
-		if err := out.fromOutput(o); err != nil {
+		if err := out.fromOutput(o, n.network()); err != nil {
 			return nil, err
 		}
 		out.Index = i
 		oo = append(oo, out)
 	}
+	coinbase := tx.IsCoinbase()
 	ii := make([]*nodeInputJSON, 0, len(tx.Inputs))
 	for _, i := range tx.Inputs {
-		in := &nodeInputJSON{}
+		in := &nodeInputJSON{coinbase: coinbase}
 		if err := in.fromInput(i); err != nil {
 			return nil, err
 		}
 		ii = append(ii, in)
 	}
 	txj := nodeTxJSON{
+		TxID:     tx.TxID(),
+		Hash:     tx.TxID(),
 		Version:  tx.Version,
+		Size:     tx.Size(),
 		LockTime: tx.LockTime,
 		Inputs:   ii,
 		Outputs:  oo,
-		TxID:     tx.TxID(),
-		Hash:     tx.TxID(),
-		Size:     len(tx.Bytes()),
 		Hex:      tx.String(),
 	}
 	return json.Marshal(txj)
 }
 
+// network returns the network the addresses of the outputs are on.
+func (n *nodeTxWrapper) network() *network.Params {
+	if n.net == nil {
+		return network.Mainnet
+	}
+	return n.net
+}
+
 // UnmarshalJSON will unmarshall a transaction that has been marshalled with this library.
 func (n *nodeTxWrapper) UnmarshalJSON(b []byte) error {
 	tx := n.Tx
@@ -125,57 +192,132 @@ func (n *nodeTxWrapper) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (o *nodeOutputJSON) fromOutput(out *Output) error {
-	asm, err := out.LockingScript.ToASM()
-	if err != nil {
-		return err
-	}
-	addresses, err := out.LockingScript.Addresses()
+func (o *nodeOutputJSON) fromOutput(out *Output, net *network.Params) error {
+	reqSigs, addresses, err := nodeDestinations(out.LockingScript, net)
 	if err != nil {
 		return err
 	}
 
 	*o = nodeOutputJSON{
-		Value: float64(out.Satoshis) / 100000000,
+		Value: nodeAmount(out.Satoshis),
 		Index: 0,
-		ScriptPubKey: &struct {
-			Asm     string `json:"asm"`
-			Hex     string `json:"hex"`
-			ReqSigs int    `json:"reqSigs,omitempty"`
-			Type    string `json:"type"`
-		}{
-			Asm:     asm,
-			Hex:     out.LockingScriptHexString(),
-			ReqSigs: len(addresses),
-			Type:    out.LockingScript.ScriptType(),
+		ScriptPubKey: &nodeScriptPubKeyJSON{
+			Asm:       out.LockingScript.ToNodeASM(false),
+			Hex:       out.LockingScriptHexString(),
+			ReqSigs:   reqSigs,
+			Type:      nodeScriptType(out.LockingScript),
+			Addresses: addresses,
 		},
 	}
 
 	return nil
 }
 
+// nodeScriptType returns the type of the locking script as named by the node, which
+// reports the types it has no template for as nonstandard.
+func nodeScriptType(s *bscript.Script) string {
+	switch t := s.ScriptType(); t {
+	case bscript.ScriptTypePubKey, bscript.ScriptTypePubKeyHash, bscript.ScriptTypeMultiSig, bscript.ScriptTypeNullData:
+		return t
+	}
+
+	return bscript.ScriptTypeNonStandard
+}
+
+// nodeDestinations returns the signatures required to spend the locking script and the
+// addresses it pays to, as output by the node for p2pk, p2pkh and multisig scripts.
+// Public keys which do not parse are left out, as by the node.
+func nodeDestinations(s *bscript.Script, net *network.Params) (int, []string, error) {
+	var reqSigs int
+	var pubKeyHashes [][]byte
+	switch nodeScriptType(s) {
+	case bscript.ScriptTypePubKeyHash:
+		pkh, err := s.PublicKeyHash()
+		if err != nil {
+			return 0, nil, err
+		}
+		reqSigs, pubKeyHashes = 1, [][]byte{pkh}
+	case bscript.ScriptTypePubKey:
+		params, err := bscript.P2PKTemplate{}.Extract(s)
+		if err != nil {
+			return 0, nil, err
+		}
+		if _, err = bec.ParsePubKey(params[bscript.ParamPubKey], bec.S256()); err != nil {
+			return 0, nil, nil
+		}
+		reqSigs, pubKeyHashes = 1, [][]byte{crypto.Hash160(params[bscript.ParamPubKey])}
+	case bscript.ScriptTypeMultiSig:
+		params, err := bscript.MultiSigTemplate{}.Extract(s)
+		if err != nil {
+			return 0, nil, err
+		}
+		reqSigs = int(params[bscript.ParamM][0])
+		for i := 0; ; i++ {
+			pk, ok := params[bscript.MultiSigPubKeyParam(i)]
+			if !ok {
+				break
+			}
+			if _, err = bec.ParsePubKey(pk, bec.S256()); err != nil {
+				continue
+			}
+			pubKeyHashes = append(pubKeyHashes, crypto.Hash160(pk))
+		}
+	default:
+		return 0, nil, nil
+	}
+
+	addresses := make([]string, 0, len(pubKeyHashes))
+	for _, pkh := range pubKeyHashes {
+		a, err := bscript.NewAddressFromPublicKeyHashForNetwork(pkh, net)
+		if err != nil {
+			return 0, nil, err
+		}
+		addresses = append(addresses, a.AddressString)
+	}
+
+	return reqSigs, addresses, nil
+}
+
 func (o *nodeOutputJSON) toOutput() (*Output, error) {
 	out := &Output{}
 	s, err := bscript.NewFromHexString(o.ScriptPubKey.Hex)
 	if err != nil {
 		return nil, err
 	}
-	out.Satoshis = uint64(o.Value * 100000000)
+	out.Satoshis = uint64(o.Value)
 	out.LockingScript = s
 	return out, nil
 }
 
 func (i *nodeInputJSON) toInput() (*Input, error) {
 	input := &Input{}
-	s, err := bscript.NewFromHexString(i.ScriptSig.Hex)
-	if err != nil {
-		return nil, err
+	if i.Coinbase != "" {
+		s, err := bscript.NewFromHexString(i.Coinbase)
+		if err != nil {
+			return nil, err
+		}
+		input.UnlockingScript = s
+		input.PreviousTxOutIndex = math.MaxUint32
+		input.SequenceNumber = i.Sequence
+		if err = input.PreviousTxIDAdd(make([]byte, 32)); err != nil {
+			return nil, err
+		}
+
+		return input, nil
+	}
+
+	s := &bscript.Script{}
+	if i.ScriptSig != nil {
+		var err error
+		if s, err = bscript.NewFromHexString(i.ScriptSig.Hex); err != nil {
+			return nil, err
+		}
 	}
 
 	input.UnlockingScript = s
 	input.PreviousTxOutIndex = i.Vout
 	input.SequenceNumber = i.Sequence
-	if err = input.PreviousTxIDAddStr(i.TxID); err != nil {
+	if err := input.PreviousTxIDAddStr(i.TxID); err != nil {
 		return nil, err
 	}
 
@@ -183,26 +325,40 @@ func (i *nodeInputJSON) toInput() (*Input, error) {
 }
 
 func (i *nodeInputJSON) fromInput(input *Input) error {
-	asm, err := input.UnlockingScript.ToASM()
-	if err != nil {
-		return err
+	i.Sequence = input.SequenceNumber
+	if i.coinbase {
+		i.Coinbase = input.UnlockingScript.String()
+		return nil
 	}
 
-	i.ScriptSig = &struct {
-		Asm string `json:"asm"`
-		Hex string `json:"hex"`
-	}{
-		Asm: asm,
+	i.ScriptSig = &nodeScriptSigJSON{
+		Asm: input.UnlockingScript.ToNodeASM(true),
 		Hex: input.UnlockingScript.String(),
 	}
 
 	i.Vout = input.PreviousTxOutIndex
-	i.Sequence = input.SequenceNumber
 	i.TxID = input.PreviousTxIDStr()
 
 	return nil
 }
 
+// MarshalJSON outputs the input of a coinbase tx with only its coinbase and sequence,
+// as the node does.
+func (i *nodeInputJSON) MarshalJSON() ([]byte, error) {
+	if i.coinbase {
+		return json.Marshal(struct {
+			Coinbase string `json:"coinbase"`
+			Sequence uint32 `json:"sequence"`
+		}{
+			Coinbase: i.Coinbase,
+			Sequence: i.Sequence,
+		})
+	}
+
+	type input nodeInputJSON
+	return json.Marshal((*input)(i))
+}
+
 // MarshalJSON will marshal a transaction that has been marshalled with this library.
 func (nn nodeTxsWrapper) MarshalJSON() ([]byte, error) {
 	txs := make([]*nodeTxWrapper, len(nn))
@@ -232,7 +388,7 @@ func (nn *nodeTxsWrapper) UnmarshalJSON(b []byte) error {
 
 func (n *nodeOutputWrapper) MarshalJSON() ([]byte, error) {
 	oj := &nodeOutputJSON{}
-	if err := oj.fromOutput(n.Output); err != nil {
+	if err := oj.fromOutput(n.Output, network.Mainnet); err != nil {
 		return nil, err
 	}
 	return json.Marshal(oj)
@@ -9,6 +9,7 @@ import (
 	"github.com/libsv/go-bk/wif"
 	"github.com/libsv/go-bt/v2"
 	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
 	"github.com/libsv/go-bt/v2/unlocker"
 	"github.com/stretchr/testify/assert"
 )
@@ -88,26 +89,25 @@ func TestTxJSON_Node_MarshallJSON(t *testing.T) {
 				return tx
 			}(),
 			expJSON: `{
-	"version": 1,
-	"locktime": 0,
 	"txid": "aec245f27b7640c8b1865045107731bfb848115c573f7da38166074b1c9e475d",
 	"hash": "aec245f27b7640c8b1865045107731bfb848115c573f7da38166074b1c9e475d",
+	"version": 1,
 	"size": 208,
-	"hex": "0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000",
+	"locktime": 0,
 	"vin": [
 		{
+			"txid": "a2a55ecc61f418e300888b1f82eaf84024496b34e3e538f3d32d342fd753adab",
+			"vout": 1,
 			"scriptSig": {
-				"asm": "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+				"asm": "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[ALL|FORKID] 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
 				"hex": "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8"
 			},
-			"txid": "a2a55ecc61f418e300888b1f82eaf84024496b34e3e538f3d32d342fd753adab",
-			"vout": 1,
 			"sequence": 4294967295
 		}
 	],
 	"vout": [
 		{
-			"value": 0,
+			"value": 0.00000000,
 			"n": 0,
 			"scriptPubKey": {
 				"asm": "0 OP_RETURN 48656c6c6f",
@@ -122,10 +122,14 @@ func TestTxJSON_Node_MarshallJSON(t *testing.T) {
 				"asm": "OP_DUP OP_HASH160 b85524abf8202a961b847a3bd0bc89d3d4d41cc5 OP_EQUALVERIFY OP_CHECKSIG",
 				"hex": "76a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac",
 				"reqSigs": 1,
-				"type": "pubkeyhash"
+				"type": "pubkeyhash",
+				"addresses": [
+					"1HofL9uDSrfgJ6Kxoa8jJG1tWujJFMGH5u"
+				]
 			}
 		}
-	]
+	],
+	"hex": "0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000"
 }`,
 		}, "transaction with multiple Inputs": {
 			tx: func() *bt.Tx {
@@ -158,53 +162,56 @@ func TestTxJSON_Node_MarshallJSON(t *testing.T) {
 				return tx
 			}(),
 			expJSON: `{
-	"version": 1,
-	"locktime": 0,
 	"txid": "41741af6fb64839c69f2385987eb3770c55c42eb6f7900fa2af9d667c42ceb20",
 	"hash": "41741af6fb64839c69f2385987eb3770c55c42eb6f7900fa2af9d667c42ceb20",
+	"version": 1,
 	"size": 486,
-	"hex": "0100000003d5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c000000006b48304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c0200000069463043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c720000006b483045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffff01e8030000000000001976a914eb0bd5edba389198e73f8efabddfc61666969ff788ac00000000",
+	"locktime": 0,
 	"vin": [
 		{
+			"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+			"vout": 0,
 			"scriptSig": {
-				"asm": "304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f48803841 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
+				"asm": "304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038[ALL|FORKID] 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
 				"hex": "48304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66"
 			},
-			"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
-			"vout": 0,
 			"sequence": 4294967295
 		},
 		{
+			"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+			"vout": 2,
 			"scriptSig": {
-				"asm": "3043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a41 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
+				"asm": "3043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a[ALL|FORKID] 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
 				"hex": "463043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66"
 			},
-			"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
-			"vout": 2,
 			"sequence": 4294967295
 		},
 		{
+			"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+			"vout": 114,
 			"scriptSig": {
-				"asm": "3045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e441 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
+				"asm": "3045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4[ALL|FORKID] 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
 				"hex": "483045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66"
 			},
-			"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
-			"vout": 114,
 			"sequence": 4294967295
 		}
 	],
 	"vout": [
 		{
-			"value": 0.00001,
+			"value": 0.00001000,
 			"n": 0,
 			"scriptPubKey": {
 				"asm": "OP_DUP OP_HASH160 eb0bd5edba389198e73f8efabddfc61666969ff7 OP_EQUALVERIFY OP_CHECKSIG",
 				"hex": "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac",
 				"reqSigs": 1,
-				"type": "pubkeyhash"
+				"type": "pubkeyhash",
+				"addresses": [
+					"1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb"
+				]
 			}
 		}
-	]
+	],
+	"hex": "0100000003d5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c000000006b48304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c0200000069463043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c720000006b483045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffff01e8030000000000001976a914eb0bd5edba389198e73f8efabddfc61666969ff788ac00000000"
 }`,
 		},
 	}
@@ -217,6 +224,99 @@ func TestTxJSON_Node_MarshallJSON(t *testing.T) {
 	}
 }
 
+func TestTxJSON_Node_MarshallJSON_Coinbase(t *testing.T) {
+	t.Parallel()
+
+	// the coinbase of the genesis block, as decoded by the node
+	tx, err := bt.NewTxFromString("01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000")
+	assert.NoError(t, err)
+
+	bb, err := json.MarshalIndent(tx.NodeJSON(), "", "  ")
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "txid": "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+  "hash": "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+  "version": 1,
+  "size": 204,
+  "locktime": 0,
+  "vin": [
+    {
+      "coinbase": "04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73",
+      "sequence": 4294967295
+    }
+  ],
+  "vout": [
+    {
+      "value": 50.00000000,
+      "n": 0,
+      "scriptPubKey": {
+        "asm": "04678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5f OP_CHECKSIG",
+        "hex": "4104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac",
+        "reqSigs": 1,
+        "type": "pubkey",
+        "addresses": [
+          "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+        ]
+      }
+    }
+  ],
+  "hex": "01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff4d04ffff001d0104455468652054696d65732030332f4a616e2f32303039204368616e63656c6c6f72206f6e206272696e6b206f66207365636f6e64206261696c6f757420666f722062616e6b73ffffffff0100f2052a01000000434104678afdb0fe5548271967f1a67130b7105cd6a828e03909a67962e0ea1f61deb649f6bc3f4cef38c4f35504e51ec112de5c384df7ba0b8d578a4c702b6bf11d5fac00000000"
+}`, string(bb))
+
+	// without the hex, the tx is built from the fields
+	var txj map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bb, &txj))
+	delete(txj, "hex")
+	bb, err = json.Marshal(txj)
+	assert.NoError(t, err)
+
+	tx2 := bt.NewTx()
+	assert.NoError(t, json.Unmarshal(bb, tx2.NodeJSON()))
+	assert.Equal(t, tx.String(), tx2.String())
+}
+
+func TestTxJSON_NodeJSONForNetwork(t *testing.T) {
+	t.Parallel()
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.PayToAddress("n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk", 1000))
+	ms, err := bscript.NewFromASM("OP_1 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66 OP_2 OP_CHECKMULTISIG")
+	assert.NoError(t, err)
+	tx.AddOutput(&bt.Output{Satoshis: 2000, LockingScript: ms})
+
+	bb, err := json.Marshal(tx.NodeJSONForNetwork(network.Testnet))
+	assert.NoError(t, err)
+
+	var txj struct {
+		Outputs []struct {
+			Value        json.Number `json:"value"`
+			ScriptPubKey struct {
+				Asm       string   `json:"asm"`
+				ReqSigs   int      `json:"reqSigs"`
+				Type      string   `json:"type"`
+				Addresses []string `json:"addresses"`
+			} `json:"scriptPubKey"`
+		} `json:"vout"`
+	}
+	assert.NoError(t, json.Unmarshal(bb, &txj))
+	assert.Len(t, txj.Outputs, 2)
+
+	assert.Equal(t, json.Number("0.00001000"), txj.Outputs[0].Value)
+	assert.Equal(t, []string{"n2wmGVP89x3DsLNqk3NvctfQy9m9pvt7mk"}, txj.Outputs[0].ScriptPubKey.Addresses)
+
+	o := txj.Outputs[1].ScriptPubKey
+	assert.Equal(t, json.Number("0.00002000"), txj.Outputs[1].Value)
+	assert.Equal(t, "1 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66 2 OP_CHECKMULTISIG", o.Asm)
+	assert.Equal(t, 1, o.ReqSigs)
+	assert.Equal(t, "multisig", o.Type)
+	assert.Len(t, o.Addresses, 2)
+	for _, a := range o.Addresses {
+		addr, err := bscript.NewAddressFromString(a)
+		assert.NoError(t, err)
+		assert.Equal(t, network.Testnet, addr.Network)
+	}
+}
+
 func TestTxJSON_Node_UnmarshalJSON(t *testing.T) {
 	t.Parallel()
 	tests := map[string]struct {
@@ -334,26 +434,25 @@ func TestTxsJSON_Node_MarshallJSON(t *testing.T) {
 			}(),
 			expJSON: `[
 	{
-		"version": 1,
-		"locktime": 0,
 		"txid": "aec245f27b7640c8b1865045107731bfb848115c573f7da38166074b1c9e475d",
 		"hash": "aec245f27b7640c8b1865045107731bfb848115c573f7da38166074b1c9e475d",
+		"version": 1,
 		"size": 208,
-		"hex": "0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000",
+		"locktime": 0,
 		"vin": [
 			{
+				"txid": "a2a55ecc61f418e300888b1f82eaf84024496b34e3e538f3d32d342fd753adab",
+				"vout": 1,
 				"scriptSig": {
-					"asm": "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
+					"asm": "30440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf[ALL|FORKID] 0294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8",
 					"hex": "4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8"
 				},
-				"txid": "a2a55ecc61f418e300888b1f82eaf84024496b34e3e538f3d32d342fd753adab",
-				"vout": 1,
 				"sequence": 4294967295
 			}
 		],
 		"vout": [
 			{
-				"value": 0,
+				"value": 0.00000000,
 				"n": 0,
 				"scriptPubKey": {
 					"asm": "0 OP_RETURN 48656c6c6f",
@@ -368,26 +467,29 @@ func TestTxsJSON_Node_MarshallJSON(t *testing.T) {
 					"asm": "OP_DUP OP_HASH160 b85524abf8202a961b847a3bd0bc89d3d4d41cc5 OP_EQUALVERIFY OP_CHECKSIG",
 					"hex": "76a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac",
 					"reqSigs": 1,
-					"type": "pubkeyhash"
+					"type": "pubkeyhash",
+					"addresses": [
+						"1HofL9uDSrfgJ6Kxoa8jJG1tWujJFMGH5u"
+					]
 				}
 			}
-		]
+		],
+		"hex": "0100000001abad53d72f342dd3f338e5e3346b492440f8ea821f8b8800e318f461cc5ea5a2010000006a4730440220042edc1302c5463e8397120a56b28ea381c8f7f6d9bdc1fee5ebca00c84a76e2022077069bbdb7ed701c4977b7db0aba80d41d4e693112256660bb5d674599e390cf41210294639d6e4249ea381c2e077e95c78fc97afe47a52eb24e1b1595cd3fdd0afdf8ffffffff02000000000000000008006a0548656c6c6f7f030000000000001976a914b85524abf8202a961b847a3bd0bc89d3d4d41cc588ac00000000"
 	},
 	{
-		"version": 2,
-		"locktime": 119,
 		"txid": "35d2d1db9bb3d1398faaa5addfc6aeaa6b3f1357d00098660b1554d4466d99b2",
 		"hash": "35d2d1db9bb3d1398faaa5addfc6aeaa6b3f1357d00098660b1554d4466d99b2",
+		"version": 2,
 		"size": 191,
-		"hex": "020000000117d2011c2a3b8a309d481930bae86e88017b0f55845ada17f96c464684b3af520000000048473044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41feffffff0240101024010000001976a9149933e4bad50e7dd4b48c1f0be98436ca7d4392a288ac00e1f505000000001976a914abbe187ad301e4326e59587e43d602edd318364e88ac77000000",
+		"locktime": 119,
 		"vin": [
 			{
+				"txid": "52afb38446466cf917da5a84550f7b01886ee8ba3019489d308a3b2a1c01d217",
+				"vout": 0,
 				"scriptSig": {
-					"asm": "3044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41",
+					"asm": "3044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e[ALL|FORKID]",
 					"hex": "473044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41"
 				},
-				"txid": "52afb38446466cf917da5a84550f7b01886ee8ba3019489d308a3b2a1c01d217",
-				"vout": 0,
 				"sequence": 4294967294
 			}
 		],
@@ -399,20 +501,27 @@ func TestTxsJSON_Node_MarshallJSON(t *testing.T) {
 					"asm": "OP_DUP OP_HASH160 9933e4bad50e7dd4b48c1f0be98436ca7d4392a2 OP_EQUALVERIFY OP_CHECKSIG",
 					"hex": "76a9149933e4bad50e7dd4b48c1f0be98436ca7d4392a288ac",
 					"reqSigs": 1,
-					"type": "pubkeyhash"
+					"type": "pubkeyhash",
+					"addresses": [
+						"1Ey4YyNhDiXo1zeU5BdM93MaGK8vgDTSZX"
+					]
 				}
 			},
 			{
-				"value": 1,
+				"value": 1.00000000,
 				"n": 1,
 				"scriptPubKey": {
 					"asm": "OP_DUP OP_HASH160 abbe187ad301e4326e59587e43d602edd318364e OP_EQUALVERIFY OP_CHECKSIG",
 					"hex": "76a914abbe187ad301e4326e59587e43d602edd318364e88ac",
 					"reqSigs": 1,
-					"type": "pubkeyhash"
+					"type": "pubkeyhash",
+					"addresses": [
+						"1Gf6GsxLwm5aPc9svKs59HoVhUT13LXFWY"
+					]
 				}
 			}
-		]
+		],
+		"hex": "020000000117d2011c2a3b8a309d481930bae86e88017b0f55845ada17f96c464684b3af520000000048473044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41feffffff0240101024010000001976a9149933e4bad50e7dd4b48c1f0be98436ca7d4392a288ac00e1f505000000001976a914abbe187ad301e4326e59587e43d602edd318364e88ac77000000"
 	}
 ]`,
 		}, "transaction with multiple Inputs": {
@@ -451,69 +560,71 @@ func TestTxsJSON_Node_MarshallJSON(t *testing.T) {
 			}(),
 			expJSON: `[
 	{
-		"version": 1,
-		"locktime": 0,
 		"txid": "41741af6fb64839c69f2385987eb3770c55c42eb6f7900fa2af9d667c42ceb20",
 		"hash": "41741af6fb64839c69f2385987eb3770c55c42eb6f7900fa2af9d667c42ceb20",
+		"version": 1,
 		"size": 486,
-		"hex": "0100000003d5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c000000006b48304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c0200000069463043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c720000006b483045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffff01e8030000000000001976a914eb0bd5edba389198e73f8efabddfc61666969ff788ac00000000",
+		"locktime": 0,
 		"vin": [
 			{
+				"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+				"vout": 0,
 				"scriptSig": {
-					"asm": "304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f48803841 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
+					"asm": "304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038[ALL|FORKID] 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
 					"hex": "48304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66"
 				},
-				"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
-				"vout": 0,
 				"sequence": 4294967295
 			},
 			{
+				"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+				"vout": 2,
 				"scriptSig": {
-					"asm": "3043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a41 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
+					"asm": "3043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a[ALL|FORKID] 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
 					"hex": "463043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66"
 				},
-				"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
-				"vout": 2,
 				"sequence": 4294967295
 			},
 			{
+				"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
+				"vout": 114,
 				"scriptSig": {
-					"asm": "3045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e441 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
+					"asm": "3045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4[ALL|FORKID] 02798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66",
 					"hex": "483045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66"
 				},
-				"txid": "3c8edde27cb9a9132c22038dac4391496be9db16fd21351565cc1006966fdad5",
-				"vout": 114,
 				"sequence": 4294967295
 			}
 		],
 		"vout": [
 			{
-				"value": 0.00001,
+				"value": 0.00001000,
 				"n": 0,
 				"scriptPubKey": {
 					"asm": "OP_DUP OP_HASH160 eb0bd5edba389198e73f8efabddfc61666969ff7 OP_EQUALVERIFY OP_CHECKSIG",
 					"hex": "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac",
 					"reqSigs": 1,
-					"type": "pubkeyhash"
+					"type": "pubkeyhash",
+					"addresses": [
+						"1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb"
+					]
 				}
 			}
-		]
+		],
+		"hex": "0100000003d5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c000000006b48304502210081214df575da1e9378f1d5a29dfd6811e93466a7222fb010b7c50dd2d44d7f2e0220399bb396336d2e294049e7db009926b1b30018ac834ee0cbca20b9d99f488038412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c0200000069463043021f7059426d6aeb7d74275e52819a309b2bf903bd18b2b4d942d0e8e037681df702203f851f8a45aabfefdca5822f457609600f5d12a173adc09c6e7e2d4fdff7620a412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffffd5da6f960610cc65153521fd16dbe96b499143ac8d03222c13a9b97ce2dd8e3c720000006b483045022100e7b3837f2818fe00a05293e0f90e9005d59b0c5c8890f22bd31c36190a9b55e9022027de4b77b78139ea21b9fd30876a447bbf29662bd19d7914028c607bccd772e4412102798913bc057b344de675dac34faafe3dc2f312c758cd9068209f810877306d66ffffffff01e8030000000000001976a914eb0bd5edba389198e73f8efabddfc61666969ff788ac00000000"
 	},
 	{
-		"version": 2,
-		"locktime": 119,
 		"txid": "35d2d1db9bb3d1398faaa5addfc6aeaa6b3f1357d00098660b1554d4466d99b2",
 		"hash": "35d2d1db9bb3d1398faaa5addfc6aeaa6b3f1357d00098660b1554d4466d99b2",
+		"version": 2,
 		"size": 191,
-		"hex": "020000000117d2011c2a3b8a309d481930bae86e88017b0f55845ada17f96c464684b3af520000000048473044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41feffffff0240101024010000001976a9149933e4bad50e7dd4b48c1f0be98436ca7d4392a288ac00e1f505000000001976a914abbe187ad301e4326e59587e43d602edd318364e88ac77000000",
+		"locktime": 119,
 		"vin": [
 			{
+				"txid": "52afb38446466cf917da5a84550f7b01886ee8ba3019489d308a3b2a1c01d217",
+				"vout": 0,
 				"scriptSig": {
-					"asm": "3044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41",
+					"asm": "3044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e[ALL|FORKID]",
 					"hex": "473044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41"
 				},
-				"txid": "52afb38446466cf917da5a84550f7b01886ee8ba3019489d308a3b2a1c01d217",
-				"vout": 0,
 				"sequence": 4294967294
 			}
 		],
@@ -525,20 +636,27 @@ func TestTxsJSON_Node_MarshallJSON(t *testing.T) {
 					"asm": "OP_DUP OP_HASH160 9933e4bad50e7dd4b48c1f0be98436ca7d4392a2 OP_EQUALVERIFY OP_CHECKSIG",
 					"hex": "76a9149933e4bad50e7dd4b48c1f0be98436ca7d4392a288ac",
 					"reqSigs": 1,
-					"type": "pubkeyhash"
+					"type": "pubkeyhash",
+					"addresses": [
+						"1Ey4YyNhDiXo1zeU5BdM93MaGK8vgDTSZX"
+					]
 				}
 			},
 			{
-				"value": 1,
+				"value": 1.00000000,
 				"n": 1,
 				"scriptPubKey": {
 					"asm": "OP_DUP OP_HASH160 abbe187ad301e4326e59587e43d602edd318364e OP_EQUALVERIFY OP_CHECKSIG",
 					"hex": "76a914abbe187ad301e4326e59587e43d602edd318364e88ac",
 					"reqSigs": 1,
-					"type": "pubkeyhash"
+					"type": "pubkeyhash",
+					"addresses": [
+						"1Gf6GsxLwm5aPc9svKs59HoVhUT13LXFWY"
+					]
 				}
 			}
-		]
+		],
+		"hex": "020000000117d2011c2a3b8a309d481930bae86e88017b0f55845ada17f96c464684b3af520000000048473044022014a60c3e84cf0160cb7e4ee7d87a3b78c5efb6dd3b66c76970b680affdb95e8f02207f6d9e3268a934e5e278ae513a3bc6dee3bec7bae37204574480305bfb5dea0e41feffffff0240101024010000001976a9149933e4bad50e7dd4b48c1f0be98436ca7d4392a288ac00e1f505000000001976a914abbe187ad301e4326e59587e43d602edd318364e88ac77000000"
 	}
 ]`,
 		},
@@ -646,10 +764,10 @@ func TestOutput_Node_JSON(t *testing.T) {
 				}(),
 			},
 			expJSON: `{
-	"value": 0.0001,
+	"value": 0.00010000,
 	"n": 0,
 	"scriptPubKey": {
-		"asm": "OP_4 OP_2 OP_2 OP_ADD OP_EQUAL",
+		"asm": "4 2 2 OP_ADD OP_EQUAL",
 		"hex": "5452529387",
 		"type": "nonstandard"
 	}
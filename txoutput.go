@@ -7,7 +7,6 @@ import (
 	"fmt"
 
 	"github.com/libsv/go-bk/bip32"
-	"github.com/libsv/go-bk/crypto"
 	"github.com/libsv/go-bt/v2/bscript"
 	"github.com/pkg/errors"
 )
@@ -134,20 +133,25 @@ func (tx *Tx) AddHashPuzzleOutput(secret, publicKeyHash string, satoshis uint64)
 		return err
 	}
 
-	s := &bscript.Script{}
-
-	_ = s.AppendOpcodes(bscript.OpHASH160)
-	secretBytesHash := crypto.Hash160([]byte(secret))
-
-	if err = s.AppendPushData(secretBytesHash); err != nil {
+	s, err := bscript.NewHashPuzzleFromSecret([]byte(secret), publicKeyHashBytes)
+	if err != nil {
 		return err
 	}
-	_ = s.AppendOpcodes(bscript.OpEQUALVERIFY, bscript.OpDUP, bscript.OpHASH160)
 
-	if err = s.AppendPushData(publicKeyHashBytes); err != nil {
+	tx.AddOutput(&Output{
+		Satoshis:      satoshis,
+		LockingScript: s,
+	})
+	return nil
+}
+
+// AddRPuzzleOutput makes an output to an R-puzzle with a value. The R value
+// should be provided as it is encoded within a DER signature.
+func (tx *Tx) AddRPuzzleOutput(r []byte, satoshis uint64) error {
+	s, err := bscript.NewRPuzzle(r)
+	if err != nil {
 		return err
 	}
-	_ = s.AppendOpcodes(bscript.OpEQUALVERIFY, bscript.OpCHECKSIG)
 
 	tx.AddOutput(&Output{
 		Satoshis:      satoshis,
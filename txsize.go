@@ -0,0 +1,148 @@
+package bt
+
+// InputSize is the size in bytes of each part of an input.
+type InputSize struct {
+	// OutpointBytes are the bytes of the previous txid and output index.
+	OutpointBytes uint64
+	// ScriptLenBytes are the bytes of the varint of the unlocking script length.
+	ScriptLenBytes uint64
+	// ScriptBytes are the bytes of the unlocking script.
+	ScriptBytes uint64
+	// SequenceBytes are the bytes of the sequence number.
+	SequenceBytes uint64
+	// ScriptType is the type of the locking script spent, as reported by
+	// `bscript.Script.ScriptType`, or empty if the locking script is unknown.
+	ScriptType string
+}
+
+// TotalBytes returns the size in bytes of the input.
+func (s InputSize) TotalBytes() uint64 {
+	return s.OutpointBytes + s.ScriptLenBytes + s.ScriptBytes + s.SequenceBytes
+}
+
+// OutputSize is the size in bytes of each part of an output.
+type OutputSize struct {
+	// SatoshisBytes are the bytes of the output value.
+	SatoshisBytes uint64
+	// ScriptLenBytes are the bytes of the varint of the locking script length.
+	ScriptLenBytes uint64
+	// ScriptBytes are the bytes of the locking script.
+	ScriptBytes uint64
+	// DataBytes are the bytes of the locking script which are data, as found by
+	// `bscript.Script.DataOffset`, and are included in ScriptBytes.
+	DataBytes uint64
+	// ScriptType is the type of the locking script, as reported by
+	// `bscript.Script.ScriptType`.
+	ScriptType string
+}
+
+// TotalBytes returns the size in bytes of the output.
+func (s OutputSize) TotalBytes() uint64 {
+	return s.SatoshisBytes + s.ScriptLenBytes + s.ScriptBytes
+}
+
+// TxSizeBreakdown contains the size of each part of a transaction, down to each
+// input and output.
+type TxSizeBreakdown struct {
+	// VersionBytes are the bytes of the tx version.
+	VersionBytes uint64
+	// InputCountBytes are the bytes of the varint of the input count.
+	InputCountBytes uint64
+	// Inputs are the sizes of each input, in order.
+	Inputs []InputSize
+	// OutputCountBytes are the bytes of the varint of the output count.
+	OutputCountBytes uint64
+	// Outputs are the sizes of each output, in order.
+	Outputs []OutputSize
+	// LockTimeBytes are the bytes of the tx lock time.
+	LockTimeBytes uint64
+}
+
+// TxSize returns the totals of the breakdown, as returned by `Tx.SizeWithTypes`.
+func (b *TxSizeBreakdown) TxSize() *TxSize {
+	size := &TxSize{
+		TotalBytes: b.VersionBytes + b.InputCountBytes + b.OutputCountBytes + b.LockTimeBytes,
+	}
+	for _, in := range b.Inputs {
+		size.TotalBytes += in.TotalBytes()
+	}
+	for _, o := range b.Outputs {
+		size.TotalBytes += o.TotalBytes()
+		size.TotalDataBytes += o.DataBytes
+	}
+	size.TotalStdBytes = size.TotalBytes - size.TotalDataBytes
+
+	return size
+}
+
+// ByScriptType returns the total bytes of the inputs and outputs by script type.
+// Inputs are counted under the type of the locking script they spend, and those
+// spending unknown locking scripts under the empty type.
+func (b *TxSizeBreakdown) ByScriptType() map[string]uint64 {
+	types := map[string]uint64{}
+	for _, in := range b.Inputs {
+		types[in.ScriptType] += in.TotalBytes()
+	}
+	for _, o := range b.Outputs {
+		types[o.ScriptType] += o.TotalBytes()
+	}
+
+	return types
+}
+
+// SizeBreakdown will return the size of each part of the tx in bytes, down to each
+// input and output, with the type of the scripts of each.
+func (tx *Tx) SizeBreakdown() *TxSizeBreakdown {
+	b := &TxSizeBreakdown{
+		VersionBytes:     4,
+		InputCountBytes:  uint64(VarInt(len(tx.Inputs)).Length()),
+		Inputs:           make([]InputSize, 0, len(tx.Inputs)),
+		OutputCountBytes: uint64(VarInt(len(tx.Outputs)).Length()),
+		Outputs:          make([]OutputSize, 0, len(tx.Outputs)),
+		LockTimeBytes:    4,
+	}
+	for _, in := range tx.Inputs {
+		var scriptLen int
+		if in.UnlockingScript != nil {
+			scriptLen = len(*in.UnlockingScript)
+		}
+		var scriptType string
+		if in.PreviousTxScript != nil {
+			scriptType = in.PreviousTxScript.ScriptType()
+		}
+		b.Inputs = append(b.Inputs, InputSize{
+			OutpointBytes:  36,
+			ScriptLenBytes: uint64(VarInt(scriptLen).Length()),
+			ScriptBytes:    uint64(scriptLen),
+			SequenceBytes:  4,
+			ScriptType:     scriptType,
+		})
+	}
+	for _, o := range tx.Outputs {
+		scriptLen := len(*o.LockingScript)
+		size := OutputSize{
+			SatoshisBytes:  8,
+			ScriptLenBytes: uint64(VarInt(scriptLen).Length()),
+			ScriptBytes:    uint64(scriptLen),
+			ScriptType:     o.LockingScript.ScriptType(),
+		}
+		if offset, ok := o.LockingScript.DataOffset(); ok {
+			size.DataBytes = uint64(scriptLen - offset)
+		}
+		b.Outputs = append(b.Outputs, size)
+	}
+
+	return b
+}
+
+// EstimateSizeBreakdown will return the size of each part of the tx in bytes, as
+// SizeBreakdown, and will add the estimated unlocking script length to any unsigned
+// inputs found to give a breakdown of the final tx.
+func (tx *Tx) EstimateSizeBreakdown() (*TxSizeBreakdown, error) {
+	tempTx, err := tx.estimatedFinalTx()
+	if err != nil {
+		return nil, err
+	}
+
+	return tempTx.SizeBreakdown(), nil
+}
@@ -0,0 +1,84 @@
+package bt_test
+
+import (
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTx_SizeBreakdown(t *testing.T) {
+	t.Parallel()
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.From("07912972e42095fe58daaf09161c5a5da57be47c2054dc2aaa52b30fefa1940b", 0, "76a914af2590a45ae401651fdbdf59a76ad43d1862534088ac", 10000))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 1000))
+	assert.NoError(t, tx.AddOpReturnOutput(make([]byte, 100)))
+
+	// an inscription, with data following a p2pkh locking script
+	inscription, err := bscript.NewFromHexString("76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac006a0401020304")
+	assert.NoError(t, err)
+	tx.AddOutput(&bt.Output{Satoshis: 1, LockingScript: inscription})
+
+	t.Run("unsigned", func(t *testing.T) {
+		b := tx.SizeBreakdown()
+		assert.Equal(t, []bt.InputSize{{
+			OutpointBytes:  36,
+			ScriptLenBytes: 1,
+			SequenceBytes:  4,
+			ScriptType:     bscript.ScriptTypePubKeyHash,
+		}}, b.Inputs)
+		assert.Equal(t, tx.SizeWithTypes(), b.TxSize())
+	})
+
+	t.Run("estimated", func(t *testing.T) {
+		b, err := tx.EstimateSizeBreakdown()
+		assert.NoError(t, err)
+
+		assert.Equal(t, &bt.TxSizeBreakdown{
+			VersionBytes:    4,
+			InputCountBytes: 1,
+			Inputs: []bt.InputSize{{
+				OutpointBytes:  36,
+				ScriptLenBytes: 1,
+				ScriptBytes:    107,
+				SequenceBytes:  4,
+				ScriptType:     bscript.ScriptTypePubKeyHash,
+			}},
+			OutputCountBytes: 1,
+			Outputs: []bt.OutputSize{{
+				SatoshisBytes:  8,
+				ScriptLenBytes: 1,
+				ScriptBytes:    25,
+				ScriptType:     bscript.ScriptTypePubKeyHash,
+			}, {
+				SatoshisBytes:  8,
+				ScriptLenBytes: 1,
+				ScriptBytes:    104,
+				DataBytes:      104,
+				ScriptType:     bscript.ScriptTypeNullData,
+			}, {
+				SatoshisBytes:  8,
+				ScriptLenBytes: 1,
+				ScriptBytes:    32,
+				DataBytes:      7,
+				ScriptType:     bscript.ScriptTypeNonStandard,
+			}},
+			LockTimeBytes: 4,
+		}, b)
+		assert.Equal(t, uint64(148), b.Inputs[0].TotalBytes())
+		assert.Equal(t, uint64(113), b.Outputs[1].TotalBytes())
+
+		size, err := tx.EstimateSizeWithTypes()
+		assert.NoError(t, err)
+		assert.Equal(t, &bt.TxSize{TotalBytes: 346, TotalStdBytes: 235, TotalDataBytes: 111}, size)
+		assert.Equal(t, size, b.TxSize())
+
+		assert.Equal(t, map[string]uint64{
+			bscript.ScriptTypePubKeyHash:  148 + 34,
+			bscript.ScriptTypeNullData:    113,
+			bscript.ScriptTypeNonStandard: 41,
+		}, b.ByScriptType())
+	})
+}
@@ -3,6 +3,8 @@ package bt
 import (
 	"context"
 
+	"github.com/libsv/go-bk/bec"
+
 	"github.com/libsv/go-bt/v2/bscript"
 	"github.com/libsv/go-bt/v2/sighash"
 )
@@ -25,3 +27,11 @@ type Unlocker interface {
 type UnlockerGetter interface {
 	Unlocker(ctx context.Context, lockingScript *bscript.Script) (Unlocker, error)
 }
+
+// UnlockerTemplate interface for a `bscript.ScriptTemplate` which can also provide
+// an Unlocker for the locking scripts it matches. Registering an UnlockerTemplate with
+// `bscript.RegisterTemplate` allows `unlocker.Getter` to dispatch to it.
+type UnlockerTemplate interface {
+	bscript.ScriptTemplate
+	Unlocker(ctx context.Context, privKey *bec.PrivateKey) (Unlocker, error)
+}
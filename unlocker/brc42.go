@@ -0,0 +1,77 @@
+package unlocker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/keys"
+)
+
+// BRC42Invoice is the metadata a BRC-42 locking script was derived with, stored
+// alongside the utxo so the signing key can later be re-derived.
+type BRC42Invoice struct {
+	Counterparty  *bec.PublicKey
+	InvoiceNumber string
+}
+
+// BRC42Getter implements the `bt.UnlockerGetter` interface for utxos paying to BRC-42
+// child keys of PrivateKey. The signing key for a locking script is re-derived from the
+// invoice metadata added with its utxo.
+type BRC42Getter struct {
+	PrivateKey *bec.PrivateKey
+
+	mu       sync.RWMutex
+	invoices map[string]BRC42Invoice
+}
+
+// NewBRC42Getter returns a BRC42Getter for the private key provided.
+func NewBRC42Getter(privKey *bec.PrivateKey) *BRC42Getter {
+	return &BRC42Getter{
+		PrivateKey: privKey,
+		invoices:   map[string]BRC42Invoice{},
+	}
+}
+
+// AddUTXO stores the invoice metadata for the utxo. ErrInvoiceMismatch is returned if
+// the locking script of the utxo is not a P2PKH paying to the key derived from the invoice.
+func (g *BRC42Getter) AddUTXO(u *bt.UTXO, inv BRC42Invoice) error {
+	child, err := keys.DeriveChildPrivateKey(g.PrivateKey, inv.Counterparty, inv.InvoiceNumber)
+	if err != nil {
+		return err
+	}
+
+	s, err := bscript.NewP2PKHFromPubKeyEC(child.PubKey())
+	if err != nil {
+		return err
+	}
+	if u.LockingScript == nil || !u.LockingScript.Equals(s) {
+		return ErrInvoiceMismatch
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.invoices[s.String()] = inv
+	return nil
+}
+
+// Unlocker builds a new `bt.Unlocker` for the locking script, as by `*unlocker.Getter`
+// using the child private key re-derived from the invoice metadata of the script.
+// ErrNoMatchingKey is returned if no utxo with the locking script has been added.
+func (g *BRC42Getter) Unlocker(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	g.mu.RLock()
+	inv, ok := g.invoices[lockingScript.String()]
+	g.mu.RUnlock()
+	if !ok {
+		return nil, ErrNoMatchingKey
+	}
+
+	child, err := keys.DeriveChildPrivateKey(g.PrivateKey, inv.Counterparty, inv.InvoiceNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&Getter{PrivateKey: child}).Unlocker(ctx, lockingScript)
+}
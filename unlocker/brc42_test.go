@@ -0,0 +1,48 @@
+package unlocker_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/keys"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBRC42Getter(t *testing.T) {
+	t.Parallel()
+
+	sender, err := bec.NewPrivateKey(bec.S256())
+	assert.NoError(t, err)
+	recipient, err := bec.NewPrivateKey(bec.S256())
+	assert.NoError(t, err)
+
+	inv := unlocker.BRC42Invoice{Counterparty: sender.PubKey(), InvoiceNumber: "2-payment-1"}
+	s, err := keys.NewBRC42LockingScript(recipient.PubKey(), sender, inv.InvoiceNumber)
+	assert.NoError(t, err)
+
+	u := &bt.UTXO{TxID: bytes.Repeat([]byte{0x01}, 32), LockingScript: s, Satoshis: 1000}
+
+	g := unlocker.NewBRC42Getter(recipient)
+	assert.ErrorIs(t, g.AddUTXO(u, unlocker.BRC42Invoice{Counterparty: sender.PubKey(), InvoiceNumber: "2-payment-2"}), unlocker.ErrInvoiceMismatch)
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.FromUTXOs(u))
+	assert.NoError(t, tx.PayToAddress("1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb", 900))
+
+	_, err = g.Unlocker(context.Background(), s)
+	assert.ErrorIs(t, err, unlocker.ErrNoMatchingKey)
+
+	assert.NoError(t, g.AddUTXO(u, inv))
+	assert.NoError(t, tx.FillAllInputs(context.Background(), g))
+
+	assert.NoError(t, interpreter.NewEngine().Execute(
+		interpreter.WithTx(tx, 0, &bt.Output{LockingScript: s, Satoshis: 1000}),
+		interpreter.WithForkID(),
+		interpreter.WithAfterGenesis(),
+	))
+}
@@ -0,0 +1,14 @@
+package unlocker
+
+import "github.com/pkg/errors"
+
+// Sentinel errors raised by the unlockers.
+var (
+	ErrInvalidK            = errors.New("k must be in the range [1, N-1]")
+	ErrNoPrivateKey        = errors.New("private key not supplied")
+	ErrUnsupportedLocking  = errors.New("locking script not supported by unlocker")
+	ErrLockTimeMismatch    = errors.New("tx lock time and locking script lock time are of different types")
+	ErrTimelockAfterUnlock = errors.New("timelock requires changes to the tx after other inputs were unlocked")
+	ErrNoMatchingKey       = errors.New("no key matches the locking script")
+	ErrInvoiceMismatch     = errors.New("locking script was not derived from the invoice")
+)
@@ -0,0 +1,83 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/wif"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+// opTrueTemplate is a custom template for `OP_DROP OP_TRUE` scripts, unlocked by pushing any value.
+type opTrueTemplate struct{}
+
+func (opTrueTemplate) Name() string { return "droptrue" }
+
+func (opTrueTemplate) Match(s *bscript.Script) bool {
+	return s.EqualsBytes([]byte{bscript.OpDROP, bscript.OpTRUE})
+}
+
+func (opTrueTemplate) Extract(*bscript.Script) (bscript.TemplateParams, error) {
+	return bscript.TemplateParams{}, nil
+}
+
+func (opTrueTemplate) Build(bscript.TemplateParams) (*bscript.Script, error) {
+	return bscript.NewFromBytes([]byte{bscript.OpDROP, bscript.OpTRUE}), nil
+}
+
+func (opTrueTemplate) EstimateUnlockLength(*bscript.Script) int { return 1 }
+
+func (opTrueTemplate) Unlocker(context.Context, *bec.PrivateKey) (bt.Unlocker, error) {
+	return &mockUnlocker{script: "00"}, nil
+}
+
+func TestGetter_Unlocker(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	templates := bscript.NewTemplateRegistry(bscript.DefaultTemplates.Templates()...)
+	assert.NoError(t, templates.Register(opTrueTemplate{}))
+	g := &unlocker.Getter{PrivateKey: w.PrivKey, Templates: templates}
+
+	tests := map[string]struct {
+		lockingScript string
+		expUnlocker   bt.Unlocker
+	}{
+		"p2pkh uses simple": {
+			lockingScript: "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+			expUnlocker:   &unlocker.Simple{PrivateKey: w.PrivKey},
+		},
+		"cltv p2pkh uses cltv": {
+			lockingScript: "03b0710bb17576a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+			expUnlocker:   &unlocker.CLTV{PrivateKey: w.PrivKey},
+		},
+		"csv p2pkh uses csv": {
+			lockingScript: "029000b27576a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac",
+			expUnlocker:   &unlocker.CSV{PrivateKey: w.PrivKey},
+		},
+		"registered unlocker template is used": {
+			lockingScript: "7551",
+			expUnlocker:   &mockUnlocker{script: "00"},
+		},
+		"non standard defaults to simple": {
+			lockingScript: "51",
+			expUnlocker:   &unlocker.Simple{PrivateKey: w.PrivKey},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewFromHexString(test.lockingScript)
+			assert.NoError(t, err)
+
+			u, err := g.Unlocker(context.Background(), s)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expUnlocker, u)
+		})
+	}
+}
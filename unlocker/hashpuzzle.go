@@ -0,0 +1,52 @@
+package unlocker
+
+import (
+	"context"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/sighash"
+)
+
+// HashPuzzle implements the `bt.Unlocker` interface. It is used to build an unlocking
+// script for a hash puzzle + P2PKH locking script, as created by `Tx.AddHashPuzzleOutput`,
+// using the secret preimage and a bec Private Key.
+type HashPuzzle struct {
+	PrivateKey *bec.PrivateKey
+	Secret     []byte
+}
+
+// UnlockingScript create the unlocking script for a given input using the PrivateKey and
+// Secret passed in through the `unlocker.HashPuzzle` struct.
+//
+// The produced unlocking script is of the form `<sig> <pubKey> <secret>`.
+func (h *HashPuzzle) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	if h.PrivateKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if params.SigHashFlags == 0 {
+		params.SigHashFlags = sighash.AllForkID
+	}
+
+	if !tx.Inputs[params.InputIdx].PreviousTxScript.IsHashPuzzle() {
+		return nil, ErrUnsupportedLocking
+	}
+
+	sh, err := tx.CalcInputSignatureHash(params.InputIdx, params.SigHashFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := h.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
+
+	return bscript.NewHashPuzzleUnlockingScript(
+		h.PrivateKey.PubKey().SerialiseCompressed(),
+		sig.Serialise(),
+		params.SigHashFlags,
+		h.Secret,
+	)
+}
@@ -0,0 +1,78 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/wif"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPuzzle_UnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		secret []byte
+		expErr bool
+	}{
+		"correct secret unlocks": {
+			secret: []byte("secret1"),
+		},
+		"wrong secret fails": {
+			secret: []byte("secret2"),
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			prevTx := bt.NewTx()
+			assert.NoError(t, prevTx.AddHashPuzzleOutput("secret1", "c0a3c167a28cabb9fbb495affa0761e6e74ac60d", 10000))
+
+			tx := bt.NewTx()
+			assert.NoError(t, tx.FromUTXOs(&bt.UTXO{
+				TxID:          prevTx.TxIDBytes(),
+				Vout:          0,
+				LockingScript: prevTx.Outputs[0].LockingScript,
+				Satoshis:      prevTx.Outputs[0].Satoshis,
+			}))
+			assert.NoError(t, tx.AddOpReturnOutput([]byte("hash puzzle")))
+
+			u := &unlocker.HashPuzzle{PrivateKey: w.PrivKey, Secret: test.secret}
+			assert.NoError(t, tx.FillInput(context.Background(), u, bt.UnlockerParams{}))
+
+			err := interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, prevTx.Outputs[0]),
+				interpreter.WithForkID(),
+				interpreter.WithAfterGenesis(),
+			)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestHashPuzzle_UnlockingScript_Errors(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+
+	_, err = (&unlocker.HashPuzzle{PrivateKey: w.PrivKey}).UnlockingScript(context.Background(), tx, bt.UnlockerParams{})
+	assert.ErrorIs(t, err, unlocker.ErrUnsupportedLocking)
+
+	_, err = (&unlocker.HashPuzzle{}).UnlockingScript(context.Background(), tx, bt.UnlockerParams{})
+	assert.ErrorIs(t, err, unlocker.ErrNoPrivateKey)
+}
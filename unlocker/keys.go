@@ -0,0 +1,73 @@
+package unlocker
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/keys"
+)
+
+// KeysGetter implements the `bt.UnlockerGetter` interface for a set of private keys,
+// selecting the key which a locking script pays to.
+//
+// Keys are matched against the public key, or public key hash, extracted from the
+// locking script by its template in `bscript.DefaultTemplates`, in either their
+// compressed or uncompressed serialisation. The unlockers sign with the public key
+// serialisation the locking script pays to.
+type KeysGetter struct {
+	Keys []*keys.PrivateKey
+}
+
+// NewKeysGetterFromWIFs returns a KeysGetter for the WIF encoded private keys provided.
+func NewKeysGetterFromWIFs(wifs ...string) (*KeysGetter, error) {
+	kk := make([]*keys.PrivateKey, 0, len(wifs))
+	for _, w := range wifs {
+		k, err := keys.DecodeWIF(w)
+		if err != nil {
+			return nil, err
+		}
+		kk = append(kk, k)
+	}
+
+	return &KeysGetter{Keys: kk}, nil
+}
+
+// Unlocker returns the `bt.Unlocker` for the locking script, built as by `*unlocker.Getter`
+// using the private key the locking script pays to. ErrNoMatchingKey is returned if
+// none of the keys match.
+func (g *KeysGetter) Unlocker(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	k, err := g.Key(lockingScript)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&Getter{PrivateKey: k.PrivateKey}).Unlocker(ctx, lockingScript)
+}
+
+// Key returns the private key the locking script pays to.
+func (g *KeysGetter) Key(lockingScript *bscript.Script) (*keys.PrivateKey, error) {
+	t, ok := bscript.MatchTemplate(lockingScript)
+	if !ok {
+		return nil, ErrNoMatchingKey
+	}
+
+	params, err := t.Extract(lockingScript)
+	if err != nil {
+		return nil, err
+	}
+
+	pkh, hasPKH := params[bscript.ParamPubKeyHash]
+	pk, hasPK := params[bscript.ParamPubKey]
+	for _, k := range g.Keys {
+		for _, compressed := range []bool{true, false} {
+			c := &keys.PrivateKey{PrivateKey: k.PrivateKey, Compressed: compressed}
+			if (hasPKH && bytes.Equal(pkh, c.PubKeyHash())) || (hasPK && bytes.Equal(pk, c.PubKeyBytes())) {
+				return k, nil
+			}
+		}
+	}
+
+	return nil, ErrNoMatchingKey
+}
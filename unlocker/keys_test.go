@@ -0,0 +1,160 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/keys"
+	"github.com/libsv/go-bt/v2/network"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysGetter_Unlocker(t *testing.T) {
+	t.Parallel()
+
+	uncompressed, err := keys.NewPrivateKey(network.Mainnet)
+	assert.NoError(t, err)
+	uncompressed.Compressed = false
+	uncompressedWIF, err := uncompressed.WIF()
+	assert.NoError(t, err)
+
+	g, err := unlocker.NewKeysGetterFromWIFs(
+		"KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn",
+		"cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq",
+		uncompressedWIF,
+	)
+	assert.NoError(t, err)
+	k1, k2, k3 := g.Keys[0], g.Keys[1], g.Keys[2]
+
+	other, err := keys.NewPrivateKey(network.Mainnet)
+	assert.NoError(t, err)
+
+	p2pkh := func(k *keys.PrivateKey) *bscript.Script {
+		s, err := k.P2PKHLockingScript()
+		assert.NoError(t, err)
+		return s
+	}
+
+	tests := map[string]struct {
+		lockingScript *bscript.Script
+		expKey        *keys.PrivateKey
+		expType       interface{}
+		expErr        error
+	}{
+		"p2pkh of first key": {
+			lockingScript: p2pkh(k1),
+			expKey:        k1,
+			expType:       &unlocker.Simple{},
+		},
+		"p2pkh of second key": {
+			lockingScript: p2pkh(k2),
+			expKey:        k2,
+			expType:       &unlocker.Simple{},
+		},
+		"p2pkh of uncompressed key": {
+			lockingScript: p2pkh(k3),
+			expKey:        k3,
+			expType:       &unlocker.Simple{},
+		},
+		"compressed p2pkh of uncompressed key": {
+			lockingScript: p2pkh(&keys.PrivateKey{PrivateKey: k3.PrivateKey, Compressed: true}),
+			expKey:        k3,
+			expType:       &unlocker.Simple{},
+		},
+		"uncompressed p2pkh of compressed key": {
+			lockingScript: p2pkh(&keys.PrivateKey{PrivateKey: k2.PrivateKey}),
+			expKey:        k2,
+			expType:       &unlocker.Simple{},
+		},
+		"p2pk of second key": {
+			lockingScript: func() *bscript.Script {
+				s, err := bscript.P2PKTemplate{}.Build(bscript.TemplateParams{bscript.ParamPubKey: k2.PubKeyBytes()})
+				assert.NoError(t, err)
+				return s
+			}(),
+			expKey:  k2,
+			expType: &unlocker.Simple{},
+		},
+		"cltv of first key": {
+			lockingScript: func() *bscript.Script {
+				s, err := bscript.NewCLTVP2PKH(750000, k1.PubKeyHash())
+				assert.NoError(t, err)
+				return s
+			}(),
+			expKey:  k1,
+			expType: &unlocker.CLTV{},
+		},
+		"p2pkh of unknown key": {
+			lockingScript: p2pkh(other),
+			expErr:        unlocker.ErrNoMatchingKey,
+		},
+		"non standard script": {
+			lockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE}),
+			expErr:        unlocker.ErrNoMatchingKey,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := g.Unlocker(context.Background(), test.lockingScript)
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.IsType(t, test.expType, u)
+
+			k, err := g.Key(test.lockingScript)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expKey, k)
+		})
+	}
+}
+
+func TestKeysGetter_SignsWithMatchingPubKey(t *testing.T) {
+	t.Parallel()
+
+	k, err := keys.NewPrivateKey(network.Mainnet)
+	assert.NoError(t, err)
+	g := &unlocker.KeysGetter{Keys: []*keys.PrivateKey{k}}
+
+	for _, compressed := range []bool{true, false} {
+		pk := &keys.PrivateKey{PrivateKey: k.PrivateKey, Compressed: compressed}
+		lockingScript, err := pk.P2PKHLockingScript()
+		assert.NoError(t, err)
+		prevOutput := &bt.Output{Satoshis: 10000, LockingScript: lockingScript}
+
+		tx := bt.NewTx()
+		assert.NoError(t, tx.FromUTXOs(&bt.UTXO{
+			TxID:          crypto.Sha256d([]byte("keys")),
+			LockingScript: lockingScript,
+			Satoshis:      prevOutput.Satoshis,
+		}))
+		assert.NoError(t, tx.AddOpReturnOutput([]byte("keys")))
+		assert.NoError(t, tx.FillAllInputs(context.Background(), g))
+
+		parts, err := bscript.DecodeParts(*tx.Inputs[0].UnlockingScript)
+		assert.NoError(t, err)
+		assert.Equal(t, pk.PubKeyBytes(), parts[1])
+		assert.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, 0, prevOutput),
+			interpreter.WithForkID(),
+		))
+	}
+}
+
+func TestNewKeysGetterFromWIFs_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := unlocker.NewKeysGetterFromWIFs("invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package unlocker
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/sighash"
+)
+
+// RPuzzle implements the `bt.Unlocker` interface. It is used to build an unlocking
+// script for an R-puzzle locking script by signing with the chosen ephemeral key K,
+// so that the signature carries the R value committed to by the locking script.
+//
+// Any PrivateKey can be used to sign, as an R-puzzle does not commit to a public key.
+// Knowledge of K allows anyone to derive the signing key from the signature, so an
+// ephemeral PrivateKey should be used.
+type RPuzzle struct {
+	PrivateKey *bec.PrivateKey
+	K          *big.Int
+}
+
+// R returns the R value produced by signing with K, encoded as it
+// appears within a DER signature. This can be passed to `bscript.NewRPuzzle`
+// or `Tx.AddRPuzzleOutput` to build the matching locking script.
+func (r *RPuzzle) R() ([]byte, error) {
+	if err := validK(r.K); err != nil {
+		return nil, err
+	}
+
+	return canonicalR(rFromK(r.K)), nil
+}
+
+// UnlockingScript create the unlocking script for a given input using the PrivateKey and K
+// passed in through the `unlocker.RPuzzle` struct.
+//
+// The produced unlocking script is of the form `<sig> <pubKey>`.
+func (r *RPuzzle) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	if r.PrivateKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if err := validK(r.K); err != nil {
+		return nil, err
+	}
+	if params.SigHashFlags == 0 {
+		params.SigHashFlags = sighash.AllForkID
+	}
+
+	if !tx.Inputs[params.InputIdx].PreviousTxScript.IsRPuzzle() {
+		return nil, ErrUnsupportedLocking
+	}
+
+	sh, err := tx.CalcInputSignatureHash(params.InputIdx, params.SigHashFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := signWithK(r.PrivateKey, r.K, sh)
+	if err != nil {
+		return nil, err
+	}
+
+	return bscript.NewRPuzzleUnlockingScript(
+		r.PrivateKey.PubKey().SerialiseCompressed(),
+		sig.Serialise(),
+		params.SigHashFlags,
+	)
+}
+
+func validK(k *big.Int) error {
+	if k == nil || k.Sign() <= 0 || k.Cmp(bec.S256().N) >= 0 {
+		return ErrInvalidK
+	}
+
+	return nil
+}
+
+func rFromK(k *big.Int) *big.Int {
+	r, _ := bec.S256().ScalarBaseMult(k.Bytes())
+	return r.Mod(r, bec.S256().N)
+}
+
+// canonicalR encodes r as a DER integer value, prefixing a zero byte
+// when the high bit is set so it is not interpreted as negative.
+func canonicalR(r *big.Int) []byte {
+	b := r.Bytes()
+	if len(b) == 0 {
+		return []byte{0x00}
+	}
+	if b[0]&0x80 != 0 {
+		return append([]byte{0x00}, b...)
+	}
+
+	return b
+}
+
+// signWithK produces an ECDSA signature of hash using the provided k rather
+// than one derived through RFC6979. As with signing through bec, S is normalised
+// to the lower half of the curve order so the signature meets the LOW_S policy.
+func signWithK(privateKey *bec.PrivateKey, k *big.Int, hash []byte) (*bec.Signature, error) {
+	N := bec.S256().N
+
+	r := rFromK(k)
+	if r.Sign() == 0 {
+		return nil, ErrInvalidK
+	}
+
+	e := new(big.Int).SetBytes(hash)
+	s := new(big.Int).Mul(privateKey.D, r)
+	s.Add(s, e)
+	s.Mul(s, new(big.Int).ModInverse(k, N))
+	s.Mod(s, N)
+	if s.Sign() == 0 {
+		return nil, ErrInvalidK
+	}
+	if s.Cmp(new(big.Int).Rsh(N, 1)) > 0 {
+		s.Sub(N, s)
+	}
+
+	return &bec.Signature{R: r, S: s}, nil
+}
@@ -0,0 +1,136 @@
+package unlocker_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/bscript/interpreter/scriptflag"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPuzzle_UnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lockK  *big.Int
+		signK  *big.Int
+		expErr bool
+	}{
+		"matching k unlocks": {
+			lockK: big.NewInt(123456789),
+			signK: big.NewInt(123456789),
+		},
+		"high bit r unlocks": {
+			lockK: big.NewInt(3),
+			signK: big.NewInt(3),
+		},
+		"different k fails": {
+			lockK:  big.NewInt(123456789),
+			signK:  big.NewInt(987654321),
+			expErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := (&unlocker.RPuzzle{K: test.lockK}).R()
+			assert.NoError(t, err)
+
+			prevTx := bt.NewTx()
+			assert.NoError(t, prevTx.AddRPuzzleOutput(r, 10000))
+			assert.True(t, prevTx.Outputs[0].LockingScript.IsRPuzzle())
+
+			tx := bt.NewTx()
+			assert.NoError(t, tx.FromUTXOs(&bt.UTXO{
+				TxID:          prevTx.TxIDBytes(),
+				Vout:          0,
+				LockingScript: prevTx.Outputs[0].LockingScript,
+				Satoshis:      prevTx.Outputs[0].Satoshis,
+			}))
+			assert.NoError(t, tx.AddOpReturnOutput([]byte("r puzzle")))
+
+			pk, err := bec.NewPrivateKey(bec.S256())
+			assert.NoError(t, err)
+
+			u := &unlocker.RPuzzle{PrivateKey: pk, K: test.signK}
+			assert.NoError(t, tx.FillInput(context.Background(), u, bt.UnlockerParams{}))
+
+			err = interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, prevTx.Outputs[0]),
+				interpreter.WithForkID(),
+				interpreter.WithAfterGenesis(),
+			)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRPuzzle_UnlockingScript_LowS(t *testing.T) {
+	t.Parallel()
+
+	halfOrder := new(big.Int).Rsh(bec.S256().N, 1)
+
+	for i := 0; i < 8; i++ {
+		pk, err := bec.NewPrivateKey(bec.S256())
+		assert.NoError(t, err)
+
+		for _, k := range []*big.Int{big.NewInt(3), big.NewInt(123456789), big.NewInt(987654321), pk.D} {
+			r, err := (&unlocker.RPuzzle{K: k}).R()
+			assert.NoError(t, err)
+
+			prevTx := bt.NewTx()
+			assert.NoError(t, prevTx.AddRPuzzleOutput(r, 10000))
+
+			tx := bt.NewTx()
+			assert.NoError(t, tx.FromUTXOs(&bt.UTXO{
+				TxID:          prevTx.TxIDBytes(),
+				Vout:          0,
+				LockingScript: prevTx.Outputs[0].LockingScript,
+				Satoshis:      prevTx.Outputs[0].Satoshis,
+			}))
+			assert.NoError(t, tx.AddOpReturnOutput([]byte("r puzzle")))
+			assert.NoError(t, tx.FillInput(context.Background(), &unlocker.RPuzzle{PrivateKey: pk, K: k}, bt.UnlockerParams{}))
+
+			parts, err := bscript.DecodeParts(*tx.Inputs[0].UnlockingScript)
+			assert.NoError(t, err)
+			sig, err := bec.ParseDERSignature(parts[0][:len(parts[0])-1], bec.S256())
+			assert.NoError(t, err)
+			assert.True(t, sig.S.Cmp(halfOrder) <= 0, "S must be in the lower half of the curve order")
+
+			assert.NoError(t, interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, prevTx.Outputs[0]),
+				interpreter.WithForkID(),
+				interpreter.WithAfterGenesis(),
+				interpreter.WithFlags(scriptflag.VerifyLowS),
+			))
+		}
+	}
+}
+
+func TestRPuzzle_InvalidK(t *testing.T) {
+	t.Parallel()
+
+	pk, err := bec.NewPrivateKey(bec.S256())
+	assert.NoError(t, err)
+
+	for name, k := range map[string]*big.Int{
+		"nil k":  nil,
+		"zero k": big.NewInt(0),
+		"k >= N": bec.S256().N,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&unlocker.RPuzzle{PrivateKey: pk, K: k}).R()
+			assert.ErrorIs(t, err, unlocker.ErrInvalidK)
+		})
+	}
+}
@@ -2,10 +2,12 @@
 package unlocker
 
 import (
+	"bytes"
 	"context"
 	"errors"
 
 	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/crypto"
 	"github.com/libsv/go-bt/v2"
 	"github.com/libsv/go-bt/v2/bscript"
 	"github.com/libsv/go-bt/v2/sighash"
@@ -15,13 +17,39 @@ import (
 // using a bec PrivateKey.
 type Getter struct {
 	PrivateKey *bec.PrivateKey
+	// Templates the locking scripts are matched against, `bscript.DefaultTemplates` if nil.
+	Templates *bscript.TemplateRegistry
 }
 
-// Unlocker builds a new `*unlocker.Local` with the same private key
-// as the calling `*local.Getter`.
+// Unlocker builds a new `bt.Unlocker` for the locking script, using the same private key
+// as the calling `*unlocker.Getter`.
+//
+// The locking script is matched against the Templates of the getter. If the matching template
+// implements `bt.UnlockerTemplate` its unlocker is used, otherwise the unlocker for the
+// standard template is used, defaulting to `*unlocker.Simple`.
 //
 // For an example implementation, see `examples/unlocker_getter/`.
 func (g *Getter) Unlocker(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	templates := g.Templates
+	if templates == nil {
+		templates = bscript.DefaultTemplates
+	}
+
+	t, ok := templates.Match(lockingScript)
+	if !ok {
+		return &Simple{PrivateKey: g.PrivateKey}, nil
+	}
+	if ut, ok := t.(bt.UnlockerTemplate); ok {
+		return ut.Unlocker(ctx, g.PrivateKey)
+	}
+
+	switch t.Name() {
+	case bscript.ScriptTypeCLTVP2PKH:
+		return &CLTV{PrivateKey: g.PrivateKey}, nil
+	case bscript.ScriptTypeCSVP2PKH:
+		return &CSV{PrivateKey: g.PrivateKey}, nil
+	}
+
 	return &Simple{PrivateKey: g.PrivateKey}, nil
 }
 
@@ -41,32 +69,54 @@ type Simple struct {
 //
 // For example usage, see `examples/create_tx/create_tx.go`
 func (l *Simple) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	t, _ := bscript.MatchTemplate(tx.Inputs[params.InputIdx].PreviousTxScript)
+	switch t.(type) {
+	case bscript.P2PKHTemplate:
+		return l.sign(tx, params)
+	}
+
+	return nil, errors.New("currently only p2pkh supported")
+}
+
+// sign builds a `<sig> <pubKey>` unlocking script for the input, as required by a P2PKH
+// locking script, whether or not it is guarded by a timelock. The public key is pushed
+// in the serialisation the locking script pays to.
+func (l *Simple) sign(tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
 	if params.SigHashFlags == 0 {
 		params.SigHashFlags = sighash.AllForkID
 	}
 
-	switch tx.Inputs[params.InputIdx].PreviousTxScript.ScriptType() {
-	case bscript.ScriptTypePubKeyHash:
-		sh, err := tx.CalcInputSignatureHash(params.InputIdx, params.SigHashFlags)
-		if err != nil {
-			return nil, err
-		}
+	sh, err := tx.CalcInputSignatureHash(params.InputIdx, params.SigHashFlags)
+	if err != nil {
+		return nil, err
+	}
 
-		sig, err := l.PrivateKey.Sign(sh)
-		if err != nil {
-			return nil, err
-		}
+	sig, err := l.PrivateKey.Sign(sh)
+	if err != nil {
+		return nil, err
+	}
 
-		pubKey := l.PrivateKey.PubKey().SerialiseCompressed()
-		signature := sig.Serialise()
+	pubKey := l.pubKey(tx.Inputs[params.InputIdx].PreviousTxScript)
+	signature := sig.Serialise()
 
-		uscript, err := bscript.NewP2PKHUnlockingScript(pubKey, signature, params.SigHashFlags)
-		if err != nil {
-			return nil, err
-		}
+	uscript, err := bscript.NewP2PKHUnlockingScript(pubKey, signature, params.SigHashFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	return uscript, nil
+}
 
-		return uscript, nil
+// pubKey returns the public key serialisation which the locking script pays to: uncompressed
+// if the script pays to the hash of the uncompressed public key, otherwise compressed.
+func (l *Simple) pubKey(lockingScript *bscript.Script) []byte {
+	pk := l.PrivateKey.PubKey()
+	if t, ok := bscript.MatchTemplate(lockingScript); ok {
+		params, err := t.Extract(lockingScript)
+		if err == nil && bytes.Equal(params[bscript.ParamPubKeyHash], crypto.Hash160(pk.SerialiseUncompressed())) {
+			return pk.SerialiseUncompressed()
+		}
 	}
 
-	return nil, errors.New("currently only p2pkh supported")
+	return pk.SerialiseCompressed()
 }
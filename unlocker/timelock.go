@@ -0,0 +1,118 @@
+package unlocker
+
+import (
+	"context"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+// CLTV implements the `bt.Unlocker` interface. It is used to build an unlocking script for
+// a P2PKH locking script guarded by OP_CHECKLOCKTIMEVERIFY, as created by `bscript.NewCLTVP2PKH`.
+//
+// Before signing, the Tx LockTime is raised to the lock time committed to by the locking script
+// and the input is given a non-final sequence number, if required. As this alters data covered
+// by the signatures of other inputs, an error is returned if any other input has already been
+// unlocked and a change is required.
+type CLTV struct {
+	PrivateKey *bec.PrivateKey
+}
+
+// UnlockingScript create the unlocking script for a given input using the PrivateKey passed in
+// through the `unlocker.CLTV` struct, setting the Tx LockTime and input SequenceNumber as needed.
+func (c *CLTV) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	if c.PrivateKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+
+	in := tx.Inputs[params.InputIdx]
+	lockTime, _, err := in.PreviousTxScript.CLTVP2PKHParts()
+	if err != nil {
+		return nil, ErrUnsupportedLocking
+	}
+
+	if (tx.LockTime < bt.LockTimeThreshold) != (lockTime < bt.LockTimeThreshold) && tx.LockTime != 0 {
+		return nil, ErrLockTimeMismatch
+	}
+
+	lockTimeRequired := tx.LockTime < lockTime
+	sequenceRequired := in.SequenceNumber == bt.MaxTxInSequenceNum
+	if lockTimeRequired || sequenceRequired {
+		if otherInputUnlocked(tx, params.InputIdx) {
+			return nil, ErrTimelockAfterUnlock
+		}
+		if lockTimeRequired {
+			tx.LockTime = lockTime
+		}
+		if sequenceRequired {
+			in.SequenceNumber = bt.MaxTxInSequenceNum - 1
+		}
+	}
+
+	return (&Simple{PrivateKey: c.PrivateKey}).sign(tx, params)
+}
+
+// CSV implements the `bt.Unlocker` interface. It is used to build an unlocking script for
+// a P2PKH locking script guarded by OP_CHECKSEQUENCEVERIFY, as created by `bscript.NewCSVP2PKH`.
+//
+// Before signing, the Tx Version is raised to 2 and the input SequenceNumber is set to the
+// relative lock time committed to by the locking script, if required. As this alters data covered
+// by the signatures of other inputs, an error is returned if any other input has already been
+// unlocked and a change is required.
+type CSV struct {
+	PrivateKey *bec.PrivateKey
+}
+
+// UnlockingScript create the unlocking script for a given input using the PrivateKey passed in
+// through the `unlocker.CSV` struct, setting the Tx Version and input SequenceNumber as needed.
+func (c *CSV) UnlockingScript(ctx context.Context, tx *bt.Tx, params bt.UnlockerParams) (*bscript.Script, error) {
+	if c.PrivateKey == nil {
+		return nil, ErrNoPrivateKey
+	}
+
+	in := tx.Inputs[params.InputIdx]
+	sequence, _, err := in.PreviousTxScript.CSVP2PKHParts()
+	if err != nil {
+		return nil, ErrUnsupportedLocking
+	}
+
+	// A disabled relative lock time is treated as a NOP by the interpreter.
+	if sequence&bt.SequenceLockTimeDisabled != 0 {
+		return (&Simple{PrivateKey: c.PrivateKey}).sign(tx, params)
+	}
+
+	const mask = bt.SequenceLockTimeIsSeconds | bt.SequenceLockTimeMask
+	required := sequence & mask
+	current := in.SequenceNumber
+
+	versionRequired := tx.Version < 2
+	sequenceRequired := current&bt.SequenceLockTimeDisabled != 0 ||
+		current&bt.SequenceLockTimeIsSeconds != required&bt.SequenceLockTimeIsSeconds ||
+		current&bt.SequenceLockTimeMask < required&bt.SequenceLockTimeMask
+	if versionRequired || sequenceRequired {
+		if otherInputUnlocked(tx, params.InputIdx) {
+			return nil, ErrTimelockAfterUnlock
+		}
+		if versionRequired {
+			tx.Version = 2
+		}
+		if sequenceRequired {
+			in.SequenceNumber = required
+		}
+	}
+
+	return (&Simple{PrivateKey: c.PrivateKey}).sign(tx, params)
+}
+
+// otherInputUnlocked returns true if any input other than idx
+// already has an unlocking script.
+func otherInputUnlocked(tx *bt.Tx, idx uint32) bool {
+	for i, in := range tx.Inputs {
+		if uint32(i) != idx && in.UnlockingScript != nil && len(*in.UnlockingScript) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+package unlocker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/crypto"
+	"github.com/libsv/go-bk/wif"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/bscript/interpreter/scriptflag"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/stretchr/testify/assert"
+)
+
+func timelockTx(t *testing.T, lockingScript *bscript.Script) (*bt.Tx, *bt.Output) {
+	prevOutput := &bt.Output{Satoshis: 10000, LockingScript: lockingScript}
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.FromUTXOs(&bt.UTXO{
+		TxID:          crypto.Sha256d([]byte("timelock")),
+		Vout:          0,
+		LockingScript: prevOutput.LockingScript,
+		Satoshis:      prevOutput.Satoshis,
+	}))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("timelock")))
+
+	return tx, prevOutput
+}
+
+func TestCLTV_UnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	pkh := crypto.Hash160(w.SerialisePubKey())
+
+	tests := map[string]struct {
+		lockTime    uint32
+		txLockTime  uint32
+		expLockTime uint32
+		expErr      error
+	}{
+		"lock time is set on tx": {
+			lockTime:    750000,
+			expLockTime: 750000,
+		},
+		"higher tx lock time is kept": {
+			lockTime:    750000,
+			txLockTime:  760000,
+			expLockTime: 760000,
+		},
+		"timestamp lock time is set on tx": {
+			lockTime:    1700000000,
+			expLockTime: 1700000000,
+		},
+		"mismatched lock time type errors": {
+			lockTime:   1700000000,
+			txLockTime: 760000,
+			expErr:     unlocker.ErrLockTimeMismatch,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewCLTVP2PKH(test.lockTime, pkh)
+			assert.NoError(t, err)
+
+			tx, prevOutput := timelockTx(t, s)
+			tx.LockTime = test.txLockTime
+
+			err = tx.FillInput(context.Background(), &unlocker.CLTV{PrivateKey: w.PrivKey}, bt.UnlockerParams{})
+			if test.expErr != nil {
+				assert.ErrorIs(t, err, test.expErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expLockTime, tx.LockTime)
+			assert.Equal(t, bt.MaxTxInSequenceNum-1, tx.Inputs[0].SequenceNumber)
+
+			assert.NoError(t, interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, prevOutput),
+				interpreter.WithForkID(),
+				interpreter.WithFlags(scriptflag.VerifyCheckLockTimeVerify),
+			))
+		})
+	}
+}
+
+func TestCSV_UnlockingScript(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+	pkh := crypto.Hash160(w.SerialisePubKey())
+
+	tests := map[string]struct {
+		sequence    uint32
+		txSequence  uint32
+		expSequence uint32
+	}{
+		"block based sequence is set": {
+			sequence:    144,
+			txSequence:  bt.MaxTxInSequenceNum,
+			expSequence: 144,
+		},
+		"time based sequence is set": {
+			sequence:    bt.SequenceLockTimeIsSeconds | 10,
+			txSequence:  bt.MaxTxInSequenceNum,
+			expSequence: bt.SequenceLockTimeIsSeconds | 10,
+		},
+		"higher sequence is kept": {
+			sequence:    144,
+			txSequence:  200,
+			expSequence: 200,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := bscript.NewCSVP2PKH(test.sequence, pkh)
+			assert.NoError(t, err)
+
+			tx, prevOutput := timelockTx(t, s)
+			tx.Inputs[0].SequenceNumber = test.txSequence
+
+			assert.NoError(t, tx.FillInput(context.Background(), &unlocker.CSV{PrivateKey: w.PrivKey}, bt.UnlockerParams{}))
+			assert.Equal(t, uint32(2), tx.Version)
+			assert.Equal(t, test.expSequence, tx.Inputs[0].SequenceNumber)
+
+			assert.NoError(t, interpreter.NewEngine().Execute(
+				interpreter.WithTx(tx, 0, prevOutput),
+				interpreter.WithForkID(),
+				interpreter.WithFlags(scriptflag.VerifyCheckSequenceVerify),
+			))
+		})
+	}
+}
+
+func TestTimelock_AfterOtherInputUnlocked(t *testing.T) {
+	t.Parallel()
+
+	w, err := wif.DecodeWIF("cNGwGSc7KRrTmdLUZ54fiSXWbhLNDc2Eg5zNucgQxyQCzuQ5YRDq")
+	assert.NoError(t, err)
+
+	s, err := bscript.NewCLTVP2PKH(750000, crypto.Hash160(w.SerialisePubKey()))
+	assert.NoError(t, err)
+
+	tx, _ := timelockTx(t, s)
+	assert.NoError(t, tx.From("45be95d2f2c64e99518ffbbce03fb15a7758f20ee5eecf0df07938d977add71d", 0, "76a914c7c6987b6e2345a6b138e3384141520a0fbc18c588ac", 1000))
+	tx.Inputs[1].UnlockingScript = bscript.NewFromBytes([]byte{bscript.OpTRUE})
+
+	err = tx.FillInput(context.Background(), &unlocker.CLTV{PrivateKey: w.PrivKey}, bt.UnlockerParams{})
+	assert.ErrorIs(t, err, unlocker.ErrTimelockAfterUnlock)
+}
@@ -51,4 +51,9 @@ func (u *UTXO) TxIDStr() string {
 // LockingScriptHexString retur nthe locking script in hex format.
 func (u *UTXO) LockingScriptHexString() string {
 	return u.LockingScript.String()
-}
\ No newline at end of file
+}
+
+// Outpoint returns the outpoint of the utxo.
+func (u *UTXO) Outpoint() Outpoint {
+	return NewOutpoint(u.TxID, u.Vout)
+}
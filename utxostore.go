@@ -0,0 +1,97 @@
+package bt
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+// Outpoint identifies a tx output by the id of its tx, hex encoded in display
+// order, and its index.
+type Outpoint struct {
+	TxID string `json:"txid"`
+	Vout uint32 `json:"vout"`
+}
+
+// NewOutpoint returns the outpoint of a txid, in display order, and index.
+func NewOutpoint(txID []byte, vout uint32) Outpoint {
+	return Outpoint{TxID: hex.EncodeToString(txID), Vout: vout}
+}
+
+// String returns the outpoint as txid:vout.
+func (o Outpoint) String() string {
+	return fmt.Sprintf("%s:%d", o.TxID, o.Vout)
+}
+
+// UTXOStore is a set of utxos.
+//
+// Utxos can be reserved while a tx spending them is in flight, so that they are
+// not used to fund another tx. Reserved utxos are still in the set until they
+// are spent, or are released if the tx is abandoned.
+//
+// Implementations must be safe for concurrent use, and apply each call atomically,
+// so that if an error is returned none of the utxos or outpoints passed are changed.
+type UTXOStore interface {
+	// Add adds utxos to the set, returning ErrUTXOExists if any are already in it.
+	Add(ctx context.Context, utxos ...*UTXO) error
+	// Spend removes utxos from the set, reserved or not, returning ErrUTXONotFound
+	// if any are not in it.
+	Spend(ctx context.Context, outpoints ...Outpoint) error
+	// Get returns the utxo at the outpoint, or ErrUTXONotFound.
+	Get(ctx context.Context, outpoint Outpoint) (*UTXO, error)
+	// ByLockingScript returns the utxos locked by the script, reserved or not.
+	ByLockingScript(ctx context.Context, s *bscript.Script) (UTXOs, error)
+	// Available returns the utxos which are not reserved.
+	Available(ctx context.Context) (UTXOs, error)
+	// Reserve reserves utxos, returning ErrUTXONotFound if any are not in the
+	// set, or ErrUTXOReserved if any are already reserved.
+	Reserve(ctx context.Context, outpoints ...Outpoint) error
+	// Release releases reserved utxos. Outpoints which are not reserved are ignored.
+	Release(ctx context.Context, outpoints ...Outpoint) error
+}
+
+// UTXOStoreGetter returns a UTXOGetterFunc for Tx.Fund which provides the available
+// utxos of the store, in the order the store lists them, reserving each before
+// it is provided. If the tx is not broadcast, the caller is responsible for
+// releasing the utxos it was funded with.
+//
+// Example usage:
+//
+//	if err := tx.Fund(ctx, fq, bt.UTXOStoreGetter(store)); err != nil {
+//	    return err
+//	}
+//	// sign and broadcast, then
+//	if err := store.Spend(ctx, outpoints...); err != nil {}
+func UTXOStoreGetter(store UTXOStore) UTXOGetterFunc {
+	return func(ctx context.Context, deficit uint64) ([]*UTXO, error) {
+		available, err := store.Available(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(available) == 0 {
+			return nil, ErrNoUTXO
+		}
+
+		var total uint64
+		utxos := available
+		for i, u := range available {
+			total += u.Satoshis
+			if total >= deficit {
+				utxos = available[:i+1]
+				break
+			}
+		}
+
+		outpoints := make([]Outpoint, 0, len(utxos))
+		for _, u := range utxos {
+			outpoints = append(outpoints, u.Outpoint())
+		}
+		if err = store.Reserve(ctx, outpoints...); err != nil {
+			return nil, err
+		}
+
+		return utxos, nil
+	}
+}
@@ -0,0 +1,20 @@
+package utxostore
+
+import (
+	"github.com/libsv/go-bt/v2"
+	"github.com/pkg/errors"
+)
+
+// Sentinel errors raised by the package.
+var (
+	ErrInvalidRecord   = errors.New("invalid utxo store record")
+	ErrDoubleSpend     = errors.New("double spend")
+	ErrNotApplied      = errors.New("tx has not been applied")
+	ErrOutputsSpent    = errors.New("outputs of the tx have been spent")
+	ErrNoLockingScript = errors.New("output has no locking script")
+)
+
+// outpointError wraps err, one of the `bt.UTXOStore` sentinel errors, with the outpoint it concerns.
+func outpointError(err error, o bt.Outpoint) error {
+	return errors.Wrap(err, o.String())
+}
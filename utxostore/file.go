@@ -0,0 +1,237 @@
+package utxostore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/pkg/errors"
+)
+
+// Operations recorded in a File.
+const (
+	opAdd     = "add"
+	opSpend   = "spend"
+	opReserve = "reserve"
+	opRelease = "release"
+)
+
+// record is a line of a File, recording one change to the set.
+type record struct {
+	Op        string        `json:"op"`
+	UTXOs     bt.UTXOs      `json:"utxos,omitempty"`
+	Outpoints []bt.Outpoint `json:"outpoints,omitempty"`
+}
+
+// File is a `bt.UTXOStore` held in memory and persisted to an append-only file,
+// with one JSON record per line for each change to the set. Reservations are
+// persisted too, so utxos in flight when the process stops remain reserved
+// when the file is reopened.
+//
+// The file is synced after every change. A partially written final record,
+// left by a crash while writing, is discarded when the file is opened.
+type File struct {
+	mem *Memory
+	f   *os.File
+}
+
+// OpenFile opens the utxo set stored at path, creating it if it does not exist.
+func OpenFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600) //nolint:gosec // path is provided by the caller
+	if err != nil {
+		return nil, err
+	}
+
+	s := &File{mem: NewMemory(), f: f}
+	if err = s.replay(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the file. The store must not be used afterwards.
+func (s *File) Close() error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+	return s.f.Close()
+}
+
+// Add implements `bt.UTXOStore`.
+func (s *File) Add(ctx context.Context, utxos ...*bt.UTXO) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if err := s.mem.checkAdd(utxos); err != nil {
+		return err
+	}
+	if err := s.write(&record{Op: opAdd, UTXOs: utxos}); err != nil {
+		return err
+	}
+	s.mem.add(utxos)
+
+	return nil
+}
+
+// Spend implements `bt.UTXOStore`.
+func (s *File) Spend(ctx context.Context, outpoints ...bt.Outpoint) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if err := s.mem.checkExist(outpoints); err != nil {
+		return err
+	}
+	if err := s.write(&record{Op: opSpend, Outpoints: outpoints}); err != nil {
+		return err
+	}
+	s.mem.spend(outpoints)
+
+	return nil
+}
+
+// Get implements `bt.UTXOStore`.
+func (s *File) Get(ctx context.Context, outpoint bt.Outpoint) (*bt.UTXO, error) {
+	return s.mem.Get(ctx, outpoint)
+}
+
+// ByLockingScript implements `bt.UTXOStore`.
+func (s *File) ByLockingScript(ctx context.Context, script *bscript.Script) (bt.UTXOs, error) {
+	return s.mem.ByLockingScript(ctx, script)
+}
+
+// Available implements `bt.UTXOStore`.
+func (s *File) Available(ctx context.Context) (bt.UTXOs, error) {
+	return s.mem.Available(ctx)
+}
+
+// Reserve implements `bt.UTXOStore`.
+func (s *File) Reserve(ctx context.Context, outpoints ...bt.Outpoint) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if err := s.mem.checkReserve(outpoints); err != nil {
+		return err
+	}
+	if err := s.write(&record{Op: opReserve, Outpoints: outpoints}); err != nil {
+		return err
+	}
+	s.mem.reserve(outpoints, true)
+
+	return nil
+}
+
+// Release implements `bt.UTXOStore`.
+func (s *File) Release(ctx context.Context, outpoints ...bt.Outpoint) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	if err := s.write(&record{Op: opRelease, Outpoints: outpoints}); err != nil {
+		return err
+	}
+	s.mem.reserve(outpoints, false)
+
+	return nil
+}
+
+// Len returns the number of utxos in the set.
+func (s *File) Len() int {
+	return s.mem.Len()
+}
+
+// write appends r to the file. Should the record not be written and synced in
+// full, the file is truncated back to where it was, so that no partial record is
+// left for the next record to be appended after.
+func (s *File) write(r *record) error {
+	bb, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	offset, err := s.f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = s.f.Write(append(bb, '\n')); err == nil {
+		if err = s.f.Sync(); err == nil {
+			return nil
+		}
+	}
+
+	if terr := s.f.Truncate(offset); terr != nil {
+		return errors.Wrapf(err, "truncating partial record: %s", terr)
+	}
+	if _, serr := s.f.Seek(offset, io.SeekStart); serr != nil {
+		return errors.Wrapf(err, "seeking past partial record: %s", serr)
+	}
+
+	return err
+}
+
+// replay applies the records of the file to the in-memory set, truncating a
+// partially written final record, and leaves the file positioned for appending.
+func (s *File) replay() error {
+	r := bufio.NewReader(s.f)
+
+	var offset int64
+	for line := 1; ; line++ {
+		bb, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(bb) > 0 {
+				if err = s.f.Truncate(offset); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		offset += int64(len(bb))
+
+		if len(bytes.TrimSpace(bb)) == 0 {
+			continue
+		}
+		if err = s.apply(bb); err != nil {
+			return errors.Wrapf(ErrInvalidRecord, "line %d: %s", line, err)
+		}
+	}
+
+	_, err := s.f.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (s *File) apply(bb []byte) error {
+	var r record
+	if err := json.Unmarshal(bb, &r); err != nil {
+		return err
+	}
+
+	switch r.Op {
+	case opAdd:
+		if err := s.mem.checkAdd(r.UTXOs); err != nil {
+			return err
+		}
+		s.mem.add(r.UTXOs)
+	case opSpend:
+		if err := s.mem.checkExist(r.Outpoints); err != nil {
+			return err
+		}
+		s.mem.spend(r.Outpoints)
+	case opReserve:
+		if err := s.mem.checkReserve(r.Outpoints); err != nil {
+			return err
+		}
+		s.mem.reserve(r.Outpoints, true)
+	case opRelease:
+		s.mem.reserve(r.Outpoints, false)
+	default:
+		return errors.Errorf("unknown op '%s'", r.Op)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package utxostore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/pkg/errors"
+)
+
+// ConflictError is returned when a tx spends an outpoint already spent by a
+// different tx applied to the Ledger.
+type ConflictError struct {
+	// Outpoint the outpoint spent twice.
+	Outpoint bt.Outpoint
+	// TxID the id of the tx being applied.
+	TxID string
+	// SpentBy the id of the tx which already spent the outpoint.
+	SpentBy string
+}
+
+// Error returns the error message.
+func (e ConflictError) Error() string {
+	return fmt.Sprintf("%s: tx %s spends outpoint %s already spent by tx %s", ErrDoubleSpend, e.TxID, e.Outpoint, e.SpentBy)
+}
+
+// Is returns true if target is ErrDoubleSpend.
+func (e ConflictError) Is(target error) bool {
+	return target == ErrDoubleSpend
+}
+
+// applied is what is needed to undo a tx applied to the Ledger.
+type applied struct {
+	spent   bt.UTXOs
+	created []bt.Outpoint
+}
+
+// Ledger applies txs to a `bt.UTXOStore`, removing the utxos they spend and adding
+// their outputs, while recording which tx spent each outpoint so that double spends
+// are detected, and what is needed to undo each tx should it be reorged out.
+//
+// The record of an applied tx is kept until it is undone or forgotten, so once a
+// tx is buried deep enough that it will not be reorged, call Forget.
+type Ledger struct {
+	mu      sync.Mutex
+	store   bt.UTXOStore
+	spentBy map[bt.Outpoint]string
+	applied map[string]*applied
+}
+
+// NewLedger returns a ledger applying txs to the store.
+func NewLedger(store bt.UTXOStore) *Ledger {
+	return &Ledger{
+		store:   store,
+		spentBy: map[bt.Outpoint]string{},
+		applied: map[string]*applied{},
+	}
+}
+
+// Apply applies the tx to the utxo set, spending the utxos of its inputs and
+// adding its outputs, except for unspendable data outputs. The inputs of a
+// coinbase tx are not spent. A tx with an output without a locking script is
+// rejected with ErrNoLockingScript.
+//
+// If an input spends an outpoint already spent by another applied tx a ConflictError
+// is returned, which matches ErrDoubleSpend, and if it spends an outpoint which
+// is not in the set `bt.ErrUTXONotFound` is returned. Applying a tx which has
+// already been applied does nothing.
+func (l *Ledger) Apply(ctx context.Context, tx *bt.Tx) error {
+	for i, o := range tx.Outputs {
+		if o.LockingScript == nil {
+			return errors.Wrapf(ErrNoLockingScript, "output %d", i)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	txID := tx.TxID()
+	if _, ok := l.applied[txID]; ok {
+		return nil
+	}
+
+	var outpoints []bt.Outpoint
+	a := &applied{}
+	if !tx.IsCoinbase() {
+		outpoints = make([]bt.Outpoint, 0, len(tx.Inputs))
+		seen := make(map[bt.Outpoint]struct{}, len(tx.Inputs))
+		for _, in := range tx.Inputs {
+			o := in.Outpoint()
+			if spender, ok := l.spentBy[o]; ok {
+				return ConflictError{Outpoint: o, TxID: txID, SpentBy: spender}
+			}
+			if _, ok := seen[o]; ok {
+				return ConflictError{Outpoint: o, TxID: txID, SpentBy: txID}
+			}
+			seen[o] = struct{}{}
+
+			u, err := l.store.Get(ctx, o)
+			if err != nil {
+				return err
+			}
+			outpoints = append(outpoints, o)
+			a.spent = append(a.spent, u)
+		}
+	}
+
+	txIDBytes := tx.TxIDBytes()
+	utxos := make(bt.UTXOs, 0, len(tx.Outputs))
+	for i, o := range tx.Outputs {
+		if o.LockingScript.IsData() {
+			continue
+		}
+		u := &bt.UTXO{
+			TxID:          txIDBytes,
+			Vout:          uint32(i),
+			LockingScript: o.LockingScript,
+			Satoshis:      o.Satoshis,
+		}
+		utxos = append(utxos, u)
+		a.created = append(a.created, u.Outpoint())
+	}
+
+	if err := l.store.Spend(ctx, outpoints...); err != nil {
+		return err
+	}
+	if err := l.store.Add(ctx, utxos...); err != nil {
+		// put back what was spent, so the tx is not half applied
+		_ = l.store.Add(ctx, a.spent...)
+		return err
+	}
+
+	for _, o := range outpoints {
+		l.spentBy[o] = txID
+	}
+	l.applied[txID] = a
+
+	return nil
+}
+
+// Undo reverts an applied tx, removing its outputs from the utxo set and restoring
+// the utxos it spent. Txs must be undone in the reverse order they were applied,
+// so ErrOutputsSpent is returned if an output of the tx has been spent by another
+// applied tx, and ErrNotApplied if the tx has not been applied.
+func (l *Ledger) Undo(ctx context.Context, txID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.applied[txID]
+	if !ok {
+		return ErrNotApplied
+	}
+	created := make(bt.UTXOs, 0, len(a.created))
+	for _, o := range a.created {
+		if spender, ok := l.spentBy[o]; ok {
+			return errors.Wrapf(ErrOutputsSpent, "%s spent by tx %s", o, spender)
+		}
+		u, err := l.store.Get(ctx, o)
+		if err != nil {
+			return err
+		}
+		created = append(created, u)
+	}
+
+	if err := l.store.Spend(ctx, a.created...); err != nil {
+		return err
+	}
+	if err := l.store.Add(ctx, a.spent...); err != nil {
+		// put back the outputs of the tx, so the tx is not half undone
+		_ = l.store.Add(ctx, created...)
+		return err
+	}
+
+	for _, u := range a.spent {
+		delete(l.spentBy, u.Outpoint())
+	}
+	delete(l.applied, txID)
+
+	return nil
+}
+
+// SpentBy returns the id of the applied tx which spent the outpoint.
+func (l *Ledger) SpentBy(o bt.Outpoint) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	txID, ok := l.spentBy[o]
+	return txID, ok
+}
+
+// Forget discards the record of an applied tx, after which it can no longer be
+// undone, and a tx spending the same outpoints is reported as spending utxos
+// not in the set rather than as a double spend.
+func (l *Ledger) Forget(txID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.applied[txID]
+	if !ok {
+		return
+	}
+	for _, u := range a.spent {
+		delete(l.spentBy, u.Outpoint())
+	}
+	delete(l.applied, txID)
+}
@@ -0,0 +1,185 @@
+package utxostore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/utxostore"
+	"github.com/stretchr/testify/assert"
+)
+
+func spendingTx(t *testing.T, satoshis uint64, utxos ...*bt.UTXO) *bt.Tx {
+	t.Helper()
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.FromUTXOs(utxos...))
+	assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", satoshis))
+	assert.NoError(t, tx.AddOpReturnOutput([]byte("hi")))
+
+	return tx
+}
+
+// failingStore is a store whose Add fails once failAdd is set.
+type failingStore struct {
+	*utxostore.Memory
+	failAdd bool
+}
+
+func (s *failingStore) Add(ctx context.Context, utxos ...*bt.UTXO) error {
+	if s.failAdd {
+		s.failAdd = false
+		return errors.New("add failed")
+	}
+
+	return s.Memory.Add(ctx, utxos...)
+}
+
+func TestLedger(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac"
+
+	setup := func(t *testing.T) (*utxostore.Memory, *utxostore.Ledger, *bt.UTXO, *bt.UTXO) {
+		store := utxostore.NewMemory()
+		u0, u1 := utxo(t, 0, s, 1000), utxo(t, 1, s, 2000)
+		assert.NoError(t, store.Add(ctx, u0, u1))
+
+		return store, utxostore.NewLedger(store), u0, u1
+	}
+
+	t.Run("apply spends inputs and adds outputs", func(t *testing.T) {
+		store, ledger, u0, u1 := setup(t)
+
+		tx := spendingTx(t, 900, u0)
+		assert.NoError(t, ledger.Apply(ctx, tx))
+
+		utxos, err := store.Available(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, bt.UTXOs{u1, {
+			TxID:          tx.TxIDBytes(),
+			Vout:          0,
+			LockingScript: tx.Outputs[0].LockingScript,
+			Satoshis:      900,
+		}}, utxos)
+
+		spender, ok := ledger.SpentBy(u0.Outpoint())
+		assert.True(t, ok)
+		assert.Equal(t, tx.TxID(), spender)
+
+		// applying again does nothing
+		assert.NoError(t, ledger.Apply(ctx, tx))
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("double spends are reported", func(t *testing.T) {
+		store, ledger, u0, u1 := setup(t)
+
+		tx1 := spendingTx(t, 900, u0)
+		assert.NoError(t, ledger.Apply(ctx, tx1))
+
+		tx2 := spendingTx(t, 2800, u1, u0)
+		err := ledger.Apply(ctx, tx2)
+		assert.ErrorIs(t, err, utxostore.ErrDoubleSpend)
+
+		var conflict utxostore.ConflictError
+		assert.True(t, errors.As(err, &conflict))
+		assert.Equal(t, utxostore.ConflictError{Outpoint: u0.Outpoint(), TxID: tx2.TxID(), SpentBy: tx1.TxID()}, conflict)
+
+		// nothing of the conflicting tx is applied
+		_, err = store.Get(ctx, u1.Outpoint())
+		assert.NoError(t, err)
+
+		// nor can a tx spend the same outpoint twice
+		assert.ErrorIs(t, ledger.Apply(ctx, spendingTx(t, 3000, u1, u1)), utxostore.ErrDoubleSpend)
+	})
+
+	t.Run("unknown outpoints are reported", func(t *testing.T) {
+		_, ledger, _, _ := setup(t)
+		assert.ErrorIs(t, ledger.Apply(ctx, spendingTx(t, 100, utxo(t, 7, s, 500))), bt.ErrUTXONotFound)
+	})
+
+	t.Run("undo reverts txs in reverse order", func(t *testing.T) {
+		store, ledger, u0, u1 := setup(t)
+
+		tx1 := spendingTx(t, 900, u0)
+		assert.NoError(t, ledger.Apply(ctx, tx1))
+		utxos, err := store.ByLockingScript(ctx, tx1.Outputs[0].LockingScript)
+		assert.NoError(t, err)
+		tx2 := spendingTx(t, 2700, u1, utxos[0])
+		assert.NoError(t, ledger.Apply(ctx, tx2))
+
+		assert.ErrorIs(t, ledger.Undo(ctx, tx1.TxID()), utxostore.ErrOutputsSpent)
+		assert.NoError(t, ledger.Undo(ctx, tx2.TxID()))
+		assert.NoError(t, ledger.Undo(ctx, tx1.TxID()))
+		assert.ErrorIs(t, ledger.Undo(ctx, tx1.TxID()), utxostore.ErrNotApplied)
+
+		utxos, err = store.Available(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, bt.UTXOs{u0, u1}, utxos)
+		_, ok := ledger.SpentBy(u0.Outpoint())
+		assert.False(t, ok)
+
+		// the reorged tx can be applied again
+		assert.NoError(t, ledger.Apply(ctx, tx1))
+	})
+
+	t.Run("failed undo leaves the tx applied", func(t *testing.T) {
+		store := &failingStore{Memory: utxostore.NewMemory()}
+		u0 := utxo(t, 0, s, 1000)
+		assert.NoError(t, store.Add(ctx, u0))
+		ledger := utxostore.NewLedger(store)
+
+		tx := spendingTx(t, 900, u0)
+		assert.NoError(t, ledger.Apply(ctx, tx))
+		before, err := store.Available(ctx)
+		assert.NoError(t, err)
+
+		store.failAdd = true
+		assert.Error(t, ledger.Undo(ctx, tx.TxID()))
+
+		after, err := store.Available(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, before, after)
+		spender, ok := ledger.SpentBy(u0.Outpoint())
+		assert.True(t, ok)
+		assert.Equal(t, tx.TxID(), spender)
+
+		assert.NoError(t, ledger.Undo(ctx, tx.TxID()))
+		utxos, err := store.Available(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, bt.UTXOs{u0}, utxos)
+	})
+
+	t.Run("outputs without a locking script are rejected", func(t *testing.T) {
+		store, ledger, u0, _ := setup(t)
+
+		tx := spendingTx(t, 900, u0)
+		tx.AddOutput(&bt.Output{Satoshis: 10})
+		assert.ErrorIs(t, ledger.Apply(ctx, tx), utxostore.ErrNoLockingScript)
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("forgotten txs cannot be undone", func(t *testing.T) {
+		_, ledger, u0, _ := setup(t)
+
+		tx := spendingTx(t, 900, u0)
+		assert.NoError(t, ledger.Apply(ctx, tx))
+		ledger.Forget(tx.TxID())
+
+		assert.ErrorIs(t, ledger.Undo(ctx, tx.TxID()), utxostore.ErrNotApplied)
+		_, ok := ledger.SpentBy(u0.Outpoint())
+		assert.False(t, ok)
+	})
+
+	t.Run("coinbase inputs are not spent", func(t *testing.T) {
+		store, ledger, _, _ := setup(t)
+
+		tx, err := bt.NewTxFromString("01000000010000000000000000000000000000000000000000000000000000000000000000ffffffff0704ffff001d0104ffffffff0100f2052a0100000043410496b538e853519c726a2c91e61ec11600ae1390813a627c66fb8be7947be63c52da7589379515d4e0a604f8141781e62294721166bf621e73a82cbf2342c858eeac00000000")
+		assert.NoError(t, err)
+		assert.NoError(t, ledger.Apply(ctx, tx))
+		assert.Equal(t, 3, store.Len())
+	})
+}
@@ -0,0 +1,208 @@
+// Package utxostore provides implementations of `bt.UTXOStore`.
+//
+// Memory keeps the set in memory only, and File additionally records every
+// change to an append-only file, from which the set is restored when reopened.
+//
+// Either can fund a tx by way of `bt.UTXOStoreGetter`:
+//
+//	store := utxostore.NewMemory()
+//	if err := store.Add(ctx, utxos...); err != nil {}
+//	if err := tx.Fund(ctx, fq, bt.UTXOStoreGetter(store)); err != nil {}
+//
+// A Ledger keeps a store up to date as txs are made or seen, detecting double
+// spends and undoing txs which are reorged out.
+package utxostore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+)
+
+type entry struct {
+	utxo     *bt.UTXO
+	reserved bool
+}
+
+// Memory is an in-memory `bt.UTXOStore`. Utxos are listed in the order they were added.
+type Memory struct {
+	mu    sync.RWMutex
+	utxos map[bt.Outpoint]*entry
+	order []bt.Outpoint
+}
+
+// NewMemory returns an empty in-memory utxo set.
+func NewMemory() *Memory {
+	return &Memory{utxos: map[bt.Outpoint]*entry{}}
+}
+
+// Add implements `bt.UTXOStore`.
+func (m *Memory) Add(ctx context.Context, utxos ...*bt.UTXO) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkAdd(utxos); err != nil {
+		return err
+	}
+	m.add(utxos)
+
+	return nil
+}
+
+// Spend implements `bt.UTXOStore`.
+func (m *Memory) Spend(ctx context.Context, outpoints ...bt.Outpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkExist(outpoints); err != nil {
+		return err
+	}
+	m.spend(outpoints)
+
+	return nil
+}
+
+// Get implements `bt.UTXOStore`.
+func (m *Memory) Get(ctx context.Context, outpoint bt.Outpoint) (*bt.UTXO, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	e, ok := m.utxos[outpoint]
+	if !ok {
+		return nil, bt.ErrUTXONotFound
+	}
+
+	return e.utxo, nil
+}
+
+// ByLockingScript implements `bt.UTXOStore`.
+func (m *Memory) ByLockingScript(ctx context.Context, s *bscript.Script) (bt.UTXOs, error) {
+	return m.list(func(e *entry) bool {
+		return e.utxo.LockingScript != nil && e.utxo.LockingScript.Equals(s)
+	}), nil
+}
+
+// Available implements `bt.UTXOStore`.
+func (m *Memory) Available(ctx context.Context) (bt.UTXOs, error) {
+	return m.list(func(e *entry) bool {
+		return !e.reserved
+	}), nil
+}
+
+// Reserve implements `bt.UTXOStore`.
+func (m *Memory) Reserve(ctx context.Context, outpoints ...bt.Outpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkReserve(outpoints); err != nil {
+		return err
+	}
+	m.reserve(outpoints, true)
+
+	return nil
+}
+
+// Release implements `bt.UTXOStore`.
+func (m *Memory) Release(ctx context.Context, outpoints ...bt.Outpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reserve(outpoints, false)
+
+	return nil
+}
+
+// Len returns the number of utxos in the set.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.utxos)
+}
+
+func (m *Memory) list(include func(e *entry) bool) bt.UTXOs {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	utxos := bt.UTXOs{}
+	for _, o := range m.order {
+		if e := m.utxos[o]; include(e) {
+			utxos = append(utxos, e.utxo)
+		}
+	}
+
+	return utxos
+}
+
+func (m *Memory) checkAdd(utxos []*bt.UTXO) error {
+	seen := make(map[bt.Outpoint]struct{}, len(utxos))
+	for _, u := range utxos {
+		o := u.Outpoint()
+		if _, ok := m.utxos[o]; ok {
+			return outpointError(bt.ErrUTXOExists, o)
+		}
+		if _, ok := seen[o]; ok {
+			return outpointError(bt.ErrUTXOExists, o)
+		}
+		seen[o] = struct{}{}
+	}
+
+	return nil
+}
+
+func (m *Memory) checkExist(outpoints []bt.Outpoint) error {
+	for _, o := range outpoints {
+		if _, ok := m.utxos[o]; !ok {
+			return outpointError(bt.ErrUTXONotFound, o)
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) checkReserve(outpoints []bt.Outpoint) error {
+	seen := make(map[bt.Outpoint]struct{}, len(outpoints))
+	for _, o := range outpoints {
+		e, ok := m.utxos[o]
+		if !ok {
+			return outpointError(bt.ErrUTXONotFound, o)
+		}
+		if _, dup := seen[o]; e.reserved || dup {
+			return outpointError(bt.ErrUTXOReserved, o)
+		}
+		seen[o] = struct{}{}
+	}
+
+	return nil
+}
+
+func (m *Memory) add(utxos []*bt.UTXO) {
+	for _, u := range utxos {
+		o := u.Outpoint()
+		m.utxos[o] = &entry{utxo: u}
+		m.order = append(m.order, o)
+	}
+}
+
+func (m *Memory) spend(outpoints []bt.Outpoint) {
+	for _, o := range outpoints {
+		delete(m.utxos, o)
+	}
+
+	order := m.order[:0]
+	for _, o := range m.order {
+		if _, ok := m.utxos[o]; ok {
+			order = append(order, o)
+		}
+	}
+	m.order = order
+}
+
+func (m *Memory) reserve(outpoints []bt.Outpoint, reserved bool) {
+	for _, o := range outpoints {
+		if e, ok := m.utxos[o]; ok {
+			e.reserved = reserved
+		}
+	}
+}
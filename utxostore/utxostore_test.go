@@ -0,0 +1,196 @@
+package utxostore_test
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/utxostore"
+	"github.com/stretchr/testify/assert"
+)
+
+const txID = "11b476ad8e0a48fcd40807a111a050af51114877e09283bfa7f3505081a1819d"
+
+func utxo(t *testing.T, vout uint32, lockingScript string, satoshis uint64) *bt.UTXO {
+	t.Helper()
+
+	id, err := hex.DecodeString(txID)
+	assert.NoError(t, err)
+	s, err := bscript.NewFromHexString(lockingScript)
+	assert.NoError(t, err)
+
+	return &bt.UTXO{TxID: id, Vout: vout, LockingScript: s, Satoshis: satoshis}
+}
+
+func stores(t *testing.T) map[string]func(t *testing.T) bt.UTXOStore {
+	return map[string]func(t *testing.T) bt.UTXOStore{
+		"memory": func(t *testing.T) bt.UTXOStore {
+			return utxostore.NewMemory()
+		},
+		"file": func(t *testing.T) bt.UTXOStore {
+			s, err := utxostore.OpenFile(filepath.Join(t.TempDir(), "utxos"))
+			assert.NoError(t, err)
+			t.Cleanup(func() { assert.NoError(t, s.Close()) })
+			return s
+		},
+	}
+}
+
+func TestUTXOStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s1 := "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac"
+	s2 := "76a914eb0bd5edba389198e73f8efabddfc61666969ff788ac"
+
+	for name, newStore := range stores(t) {
+		newStore := newStore
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			u0, u1, u2 := utxo(t, 0, s1, 1000), utxo(t, 1, s2, 2000), utxo(t, 2, s1, 3000)
+
+			t.Run("add and get", func(t *testing.T) {
+				store := newStore(t)
+				assert.NoError(t, store.Add(ctx, u0, u1))
+
+				u, err := store.Get(ctx, bt.Outpoint{TxID: txID, Vout: 1})
+				assert.NoError(t, err)
+				assert.Equal(t, u1, u)
+
+				_, err = store.Get(ctx, bt.Outpoint{TxID: txID, Vout: 2})
+				assert.ErrorIs(t, err, bt.ErrUTXONotFound)
+
+				// adding is atomic
+				assert.ErrorIs(t, store.Add(ctx, u2, u0), bt.ErrUTXOExists)
+				assert.ErrorIs(t, store.Add(ctx, u2, u2), bt.ErrUTXOExists)
+				_, err = store.Get(ctx, u2.Outpoint())
+				assert.ErrorIs(t, err, bt.ErrUTXONotFound)
+			})
+
+			t.Run("by locking script", func(t *testing.T) {
+				store := newStore(t)
+				assert.NoError(t, store.Add(ctx, u0, u1, u2))
+
+				utxos, err := store.ByLockingScript(ctx, u0.LockingScript)
+				assert.NoError(t, err)
+				assert.Equal(t, bt.UTXOs{u0, u2}, utxos)
+
+				utxos, err = store.ByLockingScript(ctx, bscript.NewFromBytes([]byte{bscript.OpTRUE}))
+				assert.NoError(t, err)
+				assert.Empty(t, utxos)
+			})
+
+			t.Run("spend", func(t *testing.T) {
+				store := newStore(t)
+				assert.NoError(t, store.Add(ctx, u0, u1, u2))
+
+				assert.NoError(t, store.Spend(ctx, u1.Outpoint()))
+				utxos, err := store.Available(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, bt.UTXOs{u0, u2}, utxos)
+
+				// spending is atomic
+				assert.ErrorIs(t, store.Spend(ctx, u0.Outpoint(), u1.Outpoint()), bt.ErrUTXONotFound)
+				_, err = store.Get(ctx, u0.Outpoint())
+				assert.NoError(t, err)
+			})
+
+			t.Run("reserve and release", func(t *testing.T) {
+				store := newStore(t)
+				assert.NoError(t, store.Add(ctx, u0, u1, u2))
+
+				assert.NoError(t, store.Reserve(ctx, u0.Outpoint(), u2.Outpoint()))
+				utxos, err := store.Available(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, bt.UTXOs{u1}, utxos)
+
+				// reserved utxos are still listed by script
+				utxos, err = store.ByLockingScript(ctx, u0.LockingScript)
+				assert.NoError(t, err)
+				assert.Equal(t, bt.UTXOs{u0, u2}, utxos)
+
+				assert.ErrorIs(t, store.Reserve(ctx, u1.Outpoint(), u2.Outpoint()), bt.ErrUTXOReserved)
+				assert.ErrorIs(t, store.Reserve(ctx, bt.Outpoint{TxID: txID, Vout: 5}), bt.ErrUTXONotFound)
+
+				assert.NoError(t, store.Release(ctx, u0.Outpoint()))
+				assert.NoError(t, store.Spend(ctx, u2.Outpoint()))
+				utxos, err = store.Available(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, bt.UTXOs{u0, u1}, utxos)
+			})
+
+			t.Run("funds a tx", func(t *testing.T) {
+				store := newStore(t)
+				assert.NoError(t, store.Add(ctx, u0, u1, u2))
+
+				tx := bt.NewTx()
+				assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 2900))
+				assert.NoError(t, tx.Fund(ctx, bt.NewFeeQuote(), bt.UTXOStoreGetter(store)))
+				assert.Equal(t, 3, tx.InputCount())
+
+				utxos, err := store.Available(ctx)
+				assert.NoError(t, err)
+				assert.Empty(t, utxos)
+
+				tx = bt.NewTx()
+				assert.NoError(t, tx.PayToAddress("1GHMW7ABrFma2NSwiVe9b9bZxkMB7tuPZi", 500))
+				assert.ErrorIs(t, tx.Fund(ctx, bt.NewFeeQuote(), bt.UTXOStoreGetter(store)), bt.ErrInsufficientFunds)
+			})
+		})
+	}
+}
+
+func TestFile_Reopen(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "utxos")
+	s := "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac"
+	u0, u1, u2 := utxo(t, 0, s, 1000), utxo(t, 1, s, 2000), utxo(t, 2, s, 3000)
+
+	store, err := utxostore.OpenFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Add(ctx, u0, u1))
+	assert.NoError(t, store.Add(ctx, u2))
+	assert.NoError(t, store.Spend(ctx, u0.Outpoint()))
+	assert.NoError(t, store.Reserve(ctx, u1.Outpoint()))
+	assert.NoError(t, store.Close())
+
+	// a record partially written when the process stopped is discarded
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"op":"spend","outpo`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	store, err = utxostore.OpenFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, store.Len())
+
+	utxos, err := store.Available(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, bt.UTXOs{u2}, utxos)
+
+	assert.NoError(t, store.Release(ctx, u1.Outpoint()))
+	assert.NoError(t, store.Close())
+
+	store, err = utxostore.OpenFile(path)
+	assert.NoError(t, err)
+	utxos, err = store.Available(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, bt.UTXOs{u1, u2}, utxos)
+	assert.NoError(t, store.Close())
+
+	t.Run("invalid records are rejected", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "utxos")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"op":"spend","outpoints":[{"txid":"00","vout":0}]}`+"\n"), 0o600))
+
+		_, err := utxostore.OpenFile(path)
+		assert.ErrorIs(t, err, utxostore.ErrInvalidRecord)
+	})
+}
@@ -0,0 +1,396 @@
+// Package wallet provides a BIP32/BIP44 style HD account, which derives receive and
+// change addresses and can fund and unlock transactions spending to them.
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/libsv/go-bk/bec"
+	"github.com/libsv/go-bk/bip32"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/network"
+	"github.com/libsv/go-bt/v2/unlocker"
+)
+
+// Chains of an account, as per BIP44.
+const (
+	ChainReceive uint32 = 0
+	ChainChange  uint32 = 1
+)
+
+const (
+	// DefaultGapLimit the default number of consecutive unused addresses which
+	// can be issued on each chain, as per BIP44.
+	DefaultGapLimit uint32 = 20
+
+	// CoinTypeBSV the registered BIP44 coin type for BSV.
+	CoinTypeBSV uint32 = 236
+
+	purposeBIP44 uint32 = 44
+)
+
+// Derivation is the position of a key within an account.
+type Derivation struct {
+	Chain uint32
+	Index uint32
+}
+
+// String returns the derivation as a path relative to the account, ie 0/5.
+func (d Derivation) String() string {
+	return fmt.Sprintf("%d/%d", d.Chain, d.Index)
+}
+
+// Account is a BIP44 style HD account. It derives P2PKH receive and change scripts,
+// issuing at most the gap limit of consecutive unused scripts on each chain, and
+// recognises scripts up to the gap limit beyond the last used script on each chain.
+//
+// Account implements `bt.UnlockerGetter`, mapping a locking script to its derivation
+// to derive the private key, and provides a `bt.UTXOGetterFunc` over the utxos it
+// has been given, so can be used to fund, change and sign a tx:
+//
+//	if err := tx.Fund(ctx, fq, acc.UTXOGetter()); err != nil {}
+//	if err := acc.Change(tx, fq); err != nil {}
+//	if err := tx.FillAllInputs(ctx, acc); err != nil {}
+//
+// It is safe for concurrent use.
+type Account struct {
+	mu        sync.Mutex
+	key       *bip32.ExtendedKey
+	chainKeys [2]*bip32.ExtendedKey
+	path      string
+	net       *network.Params
+	gapLimit  uint32
+
+	next    [2]uint32
+	used    [2]int64
+	derived [2]uint32
+	scripts map[string]Derivation
+	utxos   bt.UTXOs
+}
+
+// NewAccount derives the account at the given index from a BIP32 master private
+// key, at the BIP44 path m/44'/coin_type'/index'.
+func NewAccount(master *bip32.ExtendedKey, index uint32, opts ...AccountOptionFunc) (*Account, error) {
+	o := defaultOpts(opts)
+	path := fmt.Sprintf("%d'/%d'/%d'", purposeBIP44, o.coinType, index)
+
+	key, err := master.DeriveChildFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAccount(key, "m/"+path, o)
+}
+
+// NewAccountFromKey returns the account for an account level extended key. If the
+// key is public, the account is watch only and cannot unlock.
+func NewAccountFromKey(key *bip32.ExtendedKey, opts ...AccountOptionFunc) (*Account, error) {
+	return newAccount(key, "", defaultOpts(opts))
+}
+
+func defaultOpts(opts []AccountOptionFunc) *accountOpts {
+	o := &accountOpts{
+		net:      network.Mainnet,
+		gapLimit: DefaultGapLimit,
+		coinType: CoinTypeBSV,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func newAccount(key *bip32.ExtendedKey, path string, o *accountOpts) (*Account, error) {
+	a := &Account{
+		key:      key,
+		path:     path,
+		net:      o.net,
+		gapLimit: o.gapLimit,
+		used:     [2]int64{-1, -1},
+		scripts:  map[string]Derivation{},
+	}
+
+	for _, chain := range []uint32{ChainReceive, ChainChange} {
+		k, err := key.Child(chain)
+		if err != nil {
+			return nil, err
+		}
+		a.chainKeys[chain] = k
+
+		if err = a.lookahead(chain); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Network returns the network the account produces addresses for.
+func (a *Account) Network() *network.Params {
+	return a.net
+}
+
+// Path returns the full derivation path of the key at d, or just the path relative
+// to the account if it was created from an account level key.
+func (a *Account) Path(d Derivation) string {
+	if a.path == "" {
+		return d.String()
+	}
+
+	return a.path + "/" + d.String()
+}
+
+// NextReceiveAddress issues the next receive address.
+func (a *Account) NextReceiveAddress() (*bscript.Address, error) {
+	s, err := a.NextReceiveScript()
+	if err != nil {
+		return nil, err
+	}
+
+	return bscript.NewAddressFromLockingScript(s, a.net)
+}
+
+// NextReceiveScript issues the next receive P2PKH locking script.
+func (a *Account) NextReceiveScript() (*bscript.Script, error) {
+	return a.nextScript(ChainReceive)
+}
+
+// NextChangeScript issues the next change P2PKH locking script.
+func (a *Account) NextChangeScript() (*bscript.Script, error) {
+	return a.nextScript(ChainChange)
+}
+
+// Change adds a change output to the tx paying to the next change script. See `Tx.Change`.
+func (a *Account) Change(tx *bt.Tx, f bt.FeeModel) error {
+	s, err := a.NextChangeScript()
+	if err != nil {
+		return err
+	}
+
+	return tx.Change(s, f)
+}
+
+// LockingScript returns the P2PKH locking script of the key at d.
+func (a *Account) LockingScript(d Derivation) (*bscript.Script, error) {
+	if d.Chain > ChainChange {
+		return nil, ErrInvalidChain
+	}
+
+	k, err := a.chainKeys[d.Chain].Child(d.Index)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := k.ECPubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return bscript.NewP2PKHFromPubKeyEC(pk)
+}
+
+// Derivation returns the derivation of a locking script issued, or looked ahead to, by the account.
+func (a *Account) Derivation(s *bscript.Script) (Derivation, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	d, ok := a.scripts[s.String()]
+	return d, ok
+}
+
+// MarkUsed records that the locking script has received funds, advancing the
+// gap limit window of its chain. It returns false if the script does not belong
+// to the account.
+func (a *Account) MarkUsed(s *bscript.Script) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.markUsed(s)
+}
+
+// PrivateKey returns the private key at d.
+func (a *Account) PrivateKey(d Derivation) (*bec.PrivateKey, error) {
+	if !a.key.IsPrivate() {
+		return nil, ErrWatchOnly
+	}
+	if d.Chain > ChainChange {
+		return nil, ErrInvalidChain
+	}
+
+	k, err := a.chainKeys[d.Chain].Child(d.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.ECPrivKey()
+}
+
+// Unlocker builds a new `bt.Unlocker` for a locking script belonging to the account,
+// as by `*unlocker.Getter` using the private key derived for the script.
+func (a *Account) Unlocker(ctx context.Context, lockingScript *bscript.Script) (bt.Unlocker, error) {
+	d, ok := a.Derivation(lockingScript)
+	if !ok {
+		return nil, ErrUnknownScript
+	}
+
+	pk, err := a.PrivateKey(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return (&unlocker.Getter{PrivateKey: pk}).Unlocker(ctx, lockingScript)
+}
+
+// AddUTXO adds a utxo paying to the account, marking its locking script as used.
+func (a *Account) AddUTXO(u *bt.UTXO) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ok, err := a.markUsed(u.LockingScript)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnknownScript
+	}
+
+	a.utxos = append(a.utxos, u)
+	return nil
+}
+
+// UTXOs returns the utxos of the account.
+func (a *Account) UTXOs() bt.UTXOs {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append(bt.UTXOs{}, a.utxos...)
+}
+
+// Balance returns the total satoshis of the utxos of the account.
+func (a *Account) Balance() (total uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, u := range a.utxos {
+		total += u.Satoshis
+	}
+
+	return total
+}
+
+// UTXOGetter returns a `bt.UTXOGetterFunc` for `Tx.Fund`, providing the utxos of the
+// account in the order they were added, each only once. The utxos are not removed
+// from the account until the tx is passed to Spend.
+func (a *Account) UTXOGetter() bt.UTXOGetterFunc {
+	utxos := a.UTXOs()
+	return func(ctx context.Context, deficit uint64) ([]*bt.UTXO, error) {
+		var total uint64
+		for i, u := range utxos {
+			total += u.Satoshis
+			if total >= deficit {
+				provided := utxos[:i+1]
+				utxos = utxos[i+1:]
+				return provided, nil
+			}
+		}
+		if len(utxos) == 0 {
+			return nil, bt.ErrNoUTXO
+		}
+
+		provided := utxos
+		utxos = nil
+		return provided, nil
+	}
+}
+
+// Spend removes the utxos spent by the tx from the account, and adds the outputs
+// of the tx which pay to the account as new utxos.
+func (a *Account) Spend(tx *bt.Tx) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	utxos := make(bt.UTXOs, 0, len(a.utxos))
+	for _, u := range a.utxos {
+		spent := false
+		for _, in := range tx.Inputs {
+			if in.PreviousTxOutIndex == u.Vout && bytes.Equal(in.PreviousTxID(), u.TxID) {
+				spent = true
+				break
+			}
+		}
+		if !spent {
+			utxos = append(utxos, u)
+		}
+	}
+
+	txID := tx.TxIDBytes()
+	for i, o := range tx.Outputs {
+		ok, err := a.markUsed(o.LockingScript)
+		if err != nil {
+			return err
+		}
+		if ok {
+			utxos = append(utxos, &bt.UTXO{
+				TxID:          txID,
+				Vout:          uint32(i),
+				LockingScript: o.LockingScript,
+				Satoshis:      o.Satoshis,
+			})
+		}
+	}
+
+	a.utxos = utxos
+	return nil
+}
+
+func (a *Account) nextScript(chain uint32) (*bscript.Script, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if int64(a.next[chain])-a.used[chain]-1 >= int64(a.gapLimit) {
+		return nil, ErrGapLimitReached
+	}
+
+	s, err := a.LockingScript(Derivation{Chain: chain, Index: a.next[chain]})
+	if err != nil {
+		return nil, err
+	}
+	a.next[chain]++
+
+	return s, nil
+}
+
+func (a *Account) markUsed(s *bscript.Script) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+
+	d, ok := a.scripts[s.String()]
+	if !ok {
+		return false, nil
+	}
+
+	if int64(d.Index) > a.used[d.Chain] {
+		a.used[d.Chain] = int64(d.Index)
+	}
+	if d.Index >= a.next[d.Chain] {
+		a.next[d.Chain] = d.Index + 1
+	}
+
+	return true, a.lookahead(d.Chain)
+}
+
+// lookahead derives the scripts of the chain up to the gap limit beyond the last used script.
+func (a *Account) lookahead(chain uint32) error {
+	to := uint32(a.used[chain]+1) + a.gapLimit
+	for ; a.derived[chain] < to; a.derived[chain]++ {
+		d := Derivation{Chain: chain, Index: a.derived[chain]}
+		s, err := a.LockingScript(d)
+		if err != nil {
+			return err
+		}
+		a.scripts[s.String()] = d
+	}
+
+	return nil
+}
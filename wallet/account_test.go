@@ -0,0 +1,218 @@
+package wallet_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/libsv/go-bk/bip32"
+	"github.com/libsv/go-bt/v2"
+	"github.com/libsv/go-bt/v2/bscript"
+	"github.com/libsv/go-bt/v2/bscript/interpreter"
+	"github.com/libsv/go-bt/v2/network"
+	"github.com/libsv/go-bt/v2/unlocker"
+	"github.com/libsv/go-bt/v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMaster(t *testing.T) *bip32.ExtendedKey {
+	master, err := bip32.NewMaster(bytes.Repeat([]byte{0x01}, 32), network.Mainnet.ChainParams())
+	assert.NoError(t, err)
+	return master
+}
+
+func TestAccount_NextScripts(t *testing.T) {
+	t.Parallel()
+
+	acc, err := wallet.NewAccount(testMaster(t), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "m/44'/236'/0'/1/3", acc.Path(wallet.Derivation{Chain: wallet.ChainChange, Index: 3}))
+
+	for i := uint32(0); i < 3; i++ {
+		s, err := acc.NextReceiveScript()
+		assert.NoError(t, err)
+		assert.True(t, s.IsP2PKH())
+
+		exp, err := acc.LockingScript(wallet.Derivation{Chain: wallet.ChainReceive, Index: i})
+		assert.NoError(t, err)
+		assert.Equal(t, exp, s)
+
+		d, ok := acc.Derivation(s)
+		assert.True(t, ok)
+		assert.Equal(t, wallet.Derivation{Chain: wallet.ChainReceive, Index: i}, d)
+	}
+
+	c, err := acc.NextChangeScript()
+	assert.NoError(t, err)
+	d, ok := acc.Derivation(c)
+	assert.True(t, ok)
+	assert.Equal(t, wallet.Derivation{Chain: wallet.ChainChange, Index: 0}, d)
+
+	_, err = acc.LockingScript(wallet.Derivation{Chain: 2})
+	assert.ErrorIs(t, err, wallet.ErrInvalidChain)
+}
+
+func TestAccount_NextReceiveAddress(t *testing.T) {
+	t.Parallel()
+
+	acc, err := wallet.NewAccount(testMaster(t), 1, wallet.WithNetwork(network.Testnet))
+	assert.NoError(t, err)
+
+	addr, err := acc.NextReceiveAddress()
+	assert.NoError(t, err)
+	assert.Equal(t, network.Testnet, addr.Network)
+	assert.Contains(t, "mn", addr.AddressString[:1])
+
+	s, err := addr.LockingScript()
+	assert.NoError(t, err)
+	_, ok := acc.Derivation(s)
+	assert.True(t, ok)
+}
+
+func TestAccount_GapLimit(t *testing.T) {
+	t.Parallel()
+
+	acc, err := wallet.NewAccount(testMaster(t), 0, wallet.WithGapLimit(3))
+	assert.NoError(t, err)
+
+	scripts := make([]*bscript.Script, 0)
+	for i := 0; i < 3; i++ {
+		s, err := acc.NextReceiveScript()
+		assert.NoError(t, err)
+		scripts = append(scripts, s)
+	}
+
+	_, err = acc.NextReceiveScript()
+	assert.ErrorIs(t, err, wallet.ErrGapLimitReached)
+
+	// The change chain has its own window.
+	_, err = acc.NextChangeScript()
+	assert.NoError(t, err)
+
+	// Scripts up to the gap limit beyond the last used script are recognised.
+	ahead, err := acc.LockingScript(wallet.Derivation{Chain: wallet.ChainReceive, Index: 4})
+	assert.NoError(t, err)
+	_, ok := acc.Derivation(ahead)
+	assert.False(t, ok)
+
+	ok, err = acc.MarkUsed(scripts[1])
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok = acc.Derivation(ahead)
+	assert.True(t, ok)
+
+	for i := 0; i < 2; i++ {
+		_, err = acc.NextReceiveScript()
+		assert.NoError(t, err)
+	}
+	_, err = acc.NextReceiveScript()
+	assert.ErrorIs(t, err, wallet.ErrGapLimitReached)
+
+	ok, err = acc.MarkUsed(bscript.NewFromBytes([]byte{bscript.OpTRUE}))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestAccount_FundChangeAndUnlock(t *testing.T) {
+	t.Parallel()
+
+	acc, err := wallet.NewAccount(testMaster(t), 0)
+	assert.NoError(t, err)
+
+	// Fund the account with two outputs.
+	fundTx := bt.NewTx()
+	assert.NoError(t, fundTx.From("11b476ad8e0a48fcd40807a111a050af51114877e09283bfa7f3505081a1819d", 0, "76a914b48b288c48e6cd7246876e19f848a60f46ab4a6188ac", 3000))
+	for i := 0; i < 2; i++ {
+		s, err := acc.NextReceiveScript()
+		assert.NoError(t, err)
+		assert.NoError(t, fundTx.AddP2PKHOutputFromScript(s, 1000))
+	}
+	assert.NoError(t, fundTx.PayToAddress("1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb", 500))
+	assert.NoError(t, acc.Spend(fundTx))
+	assert.Equal(t, uint64(2000), acc.Balance())
+	assert.Len(t, acc.UTXOs(), 2)
+
+	// Spend from the account.
+	tx := bt.NewTx()
+	assert.NoError(t, tx.PayToAddress("1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb", 1200))
+	assert.NoError(t, tx.Fund(context.Background(), bt.NewFeeQuote(), acc.UTXOGetter()))
+	assert.Len(t, tx.Inputs, 2)
+	assert.NoError(t, acc.Change(tx, bt.NewFeeQuote()))
+	assert.Len(t, tx.Outputs, 2)
+	assert.NoError(t, tx.FillAllInputs(context.Background(), acc))
+
+	for i, in := range tx.Inputs {
+		assert.NoError(t, interpreter.NewEngine().Execute(
+			interpreter.WithTx(tx, i, &bt.Output{LockingScript: in.PreviousTxScript, Satoshis: in.PreviousTxSatoshis}),
+			interpreter.WithForkID(),
+			interpreter.WithAfterGenesis(),
+		))
+	}
+
+	// The spent utxos are replaced by the change.
+	assert.NoError(t, acc.Spend(tx))
+	utxos := acc.UTXOs()
+	assert.Len(t, utxos, 1)
+	assert.Equal(t, tx.TxIDBytes(), utxos[0].TxID)
+	assert.Equal(t, uint32(1), utxos[0].Vout)
+	d, ok := acc.Derivation(utxos[0].LockingScript)
+	assert.True(t, ok)
+	assert.Equal(t, wallet.ChainChange, d.Chain)
+}
+
+func TestAccount_UTXOGetterExhausted(t *testing.T) {
+	t.Parallel()
+
+	acc, err := wallet.NewAccount(testMaster(t), 0)
+	assert.NoError(t, err)
+
+	s, err := acc.NextReceiveScript()
+	assert.NoError(t, err)
+	assert.NoError(t, acc.AddUTXO(&bt.UTXO{
+		TxID:          bytes.Repeat([]byte{0x01}, 32),
+		LockingScript: s,
+		Satoshis:      500,
+	}))
+	assert.ErrorIs(t, acc.AddUTXO(&bt.UTXO{
+		TxID:          bytes.Repeat([]byte{0x02}, 32),
+		LockingScript: bscript.NewFromBytes([]byte{bscript.OpTRUE}),
+		Satoshis:      500,
+	}), wallet.ErrUnknownScript)
+
+	tx := bt.NewTx()
+	assert.NoError(t, tx.PayToAddress("1NRoySJ9Lvby6DuE2UQYnyT67AASwNZxGb", 1000))
+	assert.ErrorIs(t, tx.Fund(context.Background(), bt.NewFeeQuote(), acc.UTXOGetter()), bt.ErrInsufficientFunds)
+}
+
+func TestAccount_WatchOnly(t *testing.T) {
+	t.Parallel()
+
+	acc, err := wallet.NewAccount(testMaster(t), 0)
+	assert.NoError(t, err)
+
+	accKey, err := testMaster(t).DeriveChildFromPath("44'/236'/0'")
+	assert.NoError(t, err)
+	pub, err := accKey.Neuter()
+	assert.NoError(t, err)
+
+	watch, err := wallet.NewAccountFromKey(pub)
+	assert.NoError(t, err)
+	assert.Equal(t, "0/0", watch.Path(wallet.Derivation{}))
+
+	s, err := acc.NextReceiveScript()
+	assert.NoError(t, err)
+	ws, err := watch.NextReceiveScript()
+	assert.NoError(t, err)
+	assert.Equal(t, s, ws)
+
+	_, err = watch.Unlocker(context.Background(), ws)
+	assert.ErrorIs(t, err, wallet.ErrWatchOnly)
+
+	u, err := acc.Unlocker(context.Background(), s)
+	assert.NoError(t, err)
+	assert.IsType(t, &unlocker.Simple{}, u)
+
+	_, err = acc.Unlocker(context.Background(), bscript.NewFromBytes([]byte{bscript.OpTRUE}))
+	assert.ErrorIs(t, err, wallet.ErrUnknownScript)
+}
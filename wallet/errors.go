@@ -0,0 +1,11 @@
+package wallet
+
+import "github.com/pkg/errors"
+
+// Sentinel errors raised by accounts.
+var (
+	ErrGapLimitReached = errors.New("gap limit of unused addresses reached")
+	ErrUnknownScript   = errors.New("locking script does not belong to the account")
+	ErrWatchOnly       = errors.New("account has no private key")
+	ErrInvalidChain    = errors.New("chain must be ChainReceive or ChainChange")
+)
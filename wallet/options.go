@@ -0,0 +1,35 @@
+package wallet
+
+import "github.com/libsv/go-bt/v2/network"
+
+// AccountOptionFunc for setting account options.
+type AccountOptionFunc func(o *accountOpts)
+
+type accountOpts struct {
+	net      *network.Params
+	gapLimit uint32
+	coinType uint32
+}
+
+// WithNetwork configure the network addresses are produced for. Defaults to network.Mainnet.
+func WithNetwork(net *network.Params) AccountOptionFunc {
+	return func(o *accountOpts) {
+		o.net = net
+	}
+}
+
+// WithGapLimit configure the number of consecutive unused addresses which can be
+// issued on each chain. Defaults to DefaultGapLimit.
+func WithGapLimit(n uint32) AccountOptionFunc {
+	return func(o *accountOpts) {
+		o.gapLimit = n
+	}
+}
+
+// WithCoinType configure the BIP44 coin type used when deriving the account from
+// a master key. Defaults to CoinTypeBSV.
+func WithCoinType(n uint32) AccountOptionFunc {
+	return func(o *accountOpts) {
+		o.coinType = n
+	}
+}